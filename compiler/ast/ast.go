@@ -1,5 +1,7 @@
 package ast
 
+import "strings"
+
 type Node interface {
 	TokenLiteral() string // token literal
 	String() string       // for debugging
@@ -29,6 +31,9 @@ func (p *Program) TokenLiteral() string {
 }
 
 func (p *Program) String() string {
-	// TODO: Will implement later
-	return ""
+	statements := make([]string, len(p.Statements))
+	for i, statement := range p.Statements {
+		statements[i] = statement.String()
+	}
+	return strings.Join(statements, "\n")
 }
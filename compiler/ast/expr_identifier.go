@@ -15,5 +15,5 @@ func (identifier *Identifier) String() string {
 }
 
 func (identifier *Identifier) TokenLiteral() string {
-	return identifier.Value
+	return identifier.Token.Value
 }
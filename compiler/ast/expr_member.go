@@ -0,0 +1,21 @@
+package ast
+
+import "compiler/token"
+
+// MemberExpression accesses a field or method on an object, e.g. `p.x` or,
+// when it's the Function of a CallExpression, `p.distance()`.
+type MemberExpression struct {
+	Token    token.Token // the '.' token
+	Object   Expression
+	Property *Identifier
+}
+
+func (member *MemberExpression) expressionNode() {}
+
+func (member *MemberExpression) TokenLiteral() string {
+	return member.Token.Value
+}
+
+func (member *MemberExpression) String() string {
+	return member.Object.String() + "." + member.Property.String()
+}
@@ -0,0 +1,20 @@
+package ast
+
+import "compiler/token"
+
+// ThisExpression is the implicit receiver inside a class method body,
+// bound to the instance the method was called on (see
+// nir.Function.Receiver). Using it outside a method is a lowering error.
+type ThisExpression struct {
+	Token token.Token
+}
+
+func (this *ThisExpression) expressionNode() {}
+
+func (this *ThisExpression) TokenLiteral() string {
+	return this.Token.Value
+}
+
+func (this *ThisExpression) String() string {
+	return "this"
+}
@@ -0,0 +1,31 @@
+package ast
+
+import (
+	"bytes"
+	"compiler/token"
+)
+
+// UnaryExpression applies a prefix operator to a single operand, e.g. `-x`
+// or `!done`.
+type UnaryExpression struct {
+	Token    token.Token // the operator token
+	Operator string
+	Operand  Expression
+}
+
+func (unary *UnaryExpression) expressionNode() {}
+
+func (unary *UnaryExpression) TokenLiteral() string {
+	return unary.Token.Value
+}
+
+func (unary *UnaryExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(unary.Operator)
+	out.WriteString(unary.Operand.String())
+	out.WriteString(")")
+
+	return out.String()
+}
@@ -0,0 +1,249 @@
+package ast
+
+import (
+	"fmt"
+	"io"
+)
+
+// Fprint writes an indented tree of node and its descendants to w, one
+// line per node: its concrete Go type, its source position, and its
+// String() form. It's a lightweight go/ast.Print analogue sized for this
+// package's small, closed set of node kinds - a type switch over them,
+// the same way checkExpression/checkStatement in the typechecker dispatch
+// on node type, rather than go/ast.Print's reflection over arbitrary
+// struct fields. Useful alongside the debug.Dumper "parse" phase (which
+// just calls Program.String()) when a diagnostic needs each node's exact
+// position, not just its reconstructed source form.
+func Fprint(w io.Writer, node Node) {
+	fprint(w, node, 0)
+}
+
+func fprint(w io.Writer, node Node, depth int) {
+	if node == nil || isNilNode(node) {
+		return
+	}
+
+	line, column := position(node)
+	fmt.Fprintf(w, "%*s%T %d:%d %s\n", depth*2, "", node, line, column, node.String())
+
+	for _, child := range children(node) {
+		fprint(w, child, depth+1)
+	}
+}
+
+// position extracts node's source line/column by a type switch over every
+// node kind, since Node itself only exposes TokenLiteral/String.
+func position(node Node) (int, int) {
+	switch n := node.(type) {
+	case *Program:
+		return 0, 0
+	case *LetStatement:
+		return n.Token.Line, n.Token.Column
+	case *FunctionStatement:
+		return n.Token.Line, n.Token.Column
+	case *ReturnStatement:
+		return n.Token.Line, n.Token.Column
+	case *ExpressionStatement:
+		return n.Token.Line, n.Token.Column
+	case *BlockStatement:
+		return n.Token.Line, n.Token.Column
+	case *AssignmentStatement:
+		return n.Token.Line, n.Token.Column
+	case *IfStatement:
+		return n.Token.Line, n.Token.Column
+	case *WhileStatement:
+		return n.Token.Line, n.Token.Column
+	case *BreakStatement:
+		return n.Token.Line, n.Token.Column
+	case *EventHandlerStatement:
+		return n.Token.Line, n.Token.Column
+	case *ImportStatement:
+		return n.Token.Line, n.Token.Column
+	case *ClassStatement:
+		return n.Token.Line, n.Token.Column
+	case *StructStatement:
+		return n.Token.Line, n.Token.Column
+	case *BinaryExpression:
+		return n.Token.Line, n.Token.Column
+	case *BooleanLiteral:
+		return n.Token.Line, n.Token.Column
+	case *CallExpression:
+		return n.Token.Line, n.Token.Column
+	case *FloatLiteral:
+		return n.Token.Line, n.Token.Column
+	case *Identifier:
+		return n.Token.Line, n.Token.Column
+	case *IntegerLiteral:
+		return n.Token.Line, n.Token.Column
+	case *MemberExpression:
+		return n.Token.Line, n.Token.Column
+	case *StringLiteral:
+		return n.Token.Line, n.Token.Column
+	case *ThisExpression:
+		return n.Token.Line, n.Token.Column
+	case *UnaryExpression:
+		return n.Token.Line, n.Token.Column
+	default:
+		return 0, 0
+	}
+}
+
+// children returns node's immediate child nodes in source order, so
+// Fprint can walk the tree without every node needing its own Walk
+// method.
+func children(node Node) []Node {
+	switch n := node.(type) {
+	case *Program:
+		children := make([]Node, len(n.Statements))
+		for i, statement := range n.Statements {
+			children[i] = statement
+		}
+		return children
+	case *LetStatement:
+		if len(n.Names) > 1 {
+			children := make([]Node, 0, len(n.Names)+1)
+			for _, name := range n.Names {
+				children = append(children, name)
+			}
+			return append(children, n.Value)
+		}
+		return []Node{n.Name, n.Value}
+	case *FunctionStatement:
+		children := make([]Node, 0, len(n.Parameters)+1)
+		for _, param := range n.Parameters {
+			children = append(children, param.Name)
+		}
+		if n.Body != nil {
+			children = append(children, n.Body)
+		}
+		return children
+	case *ReturnStatement:
+		if len(n.ReturnValues) > 1 {
+			children := make([]Node, len(n.ReturnValues))
+			for i, value := range n.ReturnValues {
+				children[i] = value
+			}
+			return children
+		}
+		if n.ReturnValue != nil {
+			return []Node{n.ReturnValue}
+		}
+		return nil
+	case *ExpressionStatement:
+		return []Node{n.Expression}
+	case *BlockStatement:
+		children := make([]Node, len(n.Statements))
+		for i, statement := range n.Statements {
+			children[i] = statement
+		}
+		return children
+	case *AssignmentStatement:
+		return []Node{n.Name, n.Value}
+	case *IfStatement:
+		children := []Node{n.Condition, n.Consequence}
+		if n.Alternative != nil {
+			children = append(children, n.Alternative)
+		}
+		return children
+	case *WhileStatement:
+		return []Node{n.Condition, n.Body}
+	case *EventHandlerStatement:
+		children := make([]Node, 0, len(n.Parameters)+2)
+		children = append(children, n.EventName)
+		for _, param := range n.Parameters {
+			children = append(children, param.Name)
+		}
+		if n.Body != nil {
+			children = append(children, n.Body)
+		}
+		return children
+	case *ClassStatement:
+		children := make([]Node, 0, len(n.Fields)+len(n.Methods)+1)
+		children = append(children, n.Name)
+		for _, field := range n.Fields {
+			children = append(children, field.Name)
+		}
+		for _, method := range n.Methods {
+			children = append(children, method)
+		}
+		return children
+	case *StructStatement:
+		children := make([]Node, 0, len(n.Fields)+1)
+		children = append(children, n.Name)
+		for _, field := range n.Fields {
+			children = append(children, field.Name)
+		}
+		return children
+	case *BinaryExpression:
+		return []Node{n.Left, n.Right}
+	case *CallExpression:
+		children := make([]Node, 0, len(n.Arguments)+1)
+		children = append(children, n.Function)
+		for _, arg := range n.Arguments {
+			children = append(children, arg)
+		}
+		return children
+	case *MemberExpression:
+		return []Node{n.Object, n.Property}
+	case *UnaryExpression:
+		return []Node{n.Operand}
+	default:
+		return nil
+	}
+}
+
+// isNilNode reports whether node holds a typed nil pointer (e.g. a
+// *LetStatement field left unset), which a plain `node == nil` check
+// misses once it's wrapped in the Node interface.
+func isNilNode(node Node) bool {
+	switch n := node.(type) {
+	case *LetStatement:
+		return n == nil
+	case *FunctionStatement:
+		return n == nil
+	case *ReturnStatement:
+		return n == nil
+	case *ExpressionStatement:
+		return n == nil
+	case *BlockStatement:
+		return n == nil
+	case *AssignmentStatement:
+		return n == nil
+	case *IfStatement:
+		return n == nil
+	case *WhileStatement:
+		return n == nil
+	case *BreakStatement:
+		return n == nil
+	case *EventHandlerStatement:
+		return n == nil
+	case *ImportStatement:
+		return n == nil
+	case *ClassStatement:
+		return n == nil
+	case *StructStatement:
+		return n == nil
+	case *BinaryExpression:
+		return n == nil
+	case *BooleanLiteral:
+		return n == nil
+	case *CallExpression:
+		return n == nil
+	case *FloatLiteral:
+		return n == nil
+	case *Identifier:
+		return n == nil
+	case *IntegerLiteral:
+		return n == nil
+	case *MemberExpression:
+		return n == nil
+	case *StringLiteral:
+		return n == nil
+	case *ThisExpression:
+		return n == nil
+	case *UnaryExpression:
+		return n == nil
+	default:
+		return false
+	}
+}
@@ -0,0 +1,19 @@
+package ast
+
+import "compiler/token"
+
+// BreakStatement is a bare `break`, exiting the nearest enclosing
+// WhileStatement's Body.
+type BreakStatement struct {
+	Token token.Token
+}
+
+func (breakStatement *BreakStatement) statementNode() {}
+
+func (breakStatement *BreakStatement) TokenLiteral() string {
+	return breakStatement.Token.Value
+}
+
+func (breakStatement *BreakStatement) String() string {
+	return breakStatement.TokenLiteral()
+}
@@ -0,0 +1,65 @@
+package ast
+
+import (
+	"bytes"
+	"compiler/token"
+)
+
+// ClassStatement declares a class with typed fields and methods, e.g.
+//
+//	class Point {
+//	    x: int
+//	    y: int
+//
+//	    func sum() -> int {
+//	        return this.x + this.y
+//	    }
+//	}
+//
+// Methods lower to ordinary NIR functions with an implicit `this`
+// receiver parameter (see nir.Function.Receiver), name-mangled as
+// ClassName_methodName so multiple classes can share method names.
+type ClassStatement struct {
+	Token   token.Token
+	Name    *Identifier
+	Fields  []*ClassField
+	Methods []*FunctionStatement
+}
+
+// ClassField is a single `name: Type` declaration inside a class body.
+type ClassField struct {
+	Name *Identifier
+	Type TypeAnnotation
+}
+
+func (class *ClassStatement) statementNode() {}
+
+func (class *ClassStatement) TokenLiteral() string {
+	return class.Token.Value
+}
+
+func (class *ClassStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("class ")
+	out.WriteString(class.Name.String())
+	out.WriteString(" {\n")
+
+	for _, field := range class.Fields {
+		out.WriteString("  ")
+		out.WriteString(field.Name.String())
+		out.WriteString(": ")
+		out.WriteString(field.Type.Value)
+		out.WriteString("\n")
+	}
+
+	for _, method := range class.Methods {
+		out.WriteString("  ")
+		out.WriteString(method.String())
+		out.WriteString("\n")
+	}
+
+	out.WriteString("}")
+
+	return out.String()
+}
@@ -0,0 +1,43 @@
+package ast
+
+import (
+	"bytes"
+	"compiler/token"
+	"strings"
+)
+
+// EventHandlerStatement is a top-level `on <event_name>(params) { ... }`
+// declaration, registering Body to run when the host fires EventName.
+// Unlike FunctionStatement it declares no return type: a handler's return
+// value, if any, is discarded by whatever runtime loop invokes it.
+type EventHandlerStatement struct {
+	Token      token.Token
+	EventName  *Identifier
+	Parameters []*FunctionParameter
+	Body       *BlockStatement
+}
+
+func (handler *EventHandlerStatement) statementNode() {}
+
+func (handler *EventHandlerStatement) TokenLiteral() string {
+	return handler.Token.Value
+}
+
+func (handler *EventHandlerStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("on ")
+	out.WriteString(handler.EventName.String())
+	out.WriteString("(")
+
+	params := []string{}
+	for _, param := range handler.Parameters {
+		params = append(params, param.Name.String()+": "+param.Type.Value)
+	}
+	out.WriteString(strings.Join(params, ", "))
+
+	out.WriteString(") ")
+	out.WriteString(handler.Body.String())
+
+	return out.String()
+}
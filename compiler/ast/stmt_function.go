@@ -12,11 +12,45 @@ type FunctionStatement struct {
 	Parameters []*FunctionParameter
 	ReturnType *TypeAnnotation
 	Body       *BlockStatement
+
+	// ReturnTypes holds every declared return type when the function
+	// signature names more than one (e.g. `func f() -> int, string`).
+	// It is nil for the common zero- or single-return-type case, where
+	// ReturnType alone is authoritative.
+	ReturnTypes []*TypeAnnotation
+
+	// Kernel is true when the function was declared with the `kernel`
+	// modifier (`kernel func f(...)`), marking it as an entry point for
+	// the GPU backends (codegen.OpenCLGenerator, codegen.CUDAGenerator)
+	// rather than the CPU/LLVM ones.
+	Kernel bool
+
+	// Extern is true when the function was declared with the `extern`
+	// modifier (`extern func f(...)`), meaning it has no body and is
+	// defined elsewhere (typically a C library function).
+	Extern bool
+
+	// Variadic is true when the function's parameter list ends with
+	// `...`, allowing callers to pass additional trailing arguments
+	// beyond Parameters (e.g. `extern func printf(fmt: string, ...)`).
+	Variadic bool
+
+	// Exported is true when the function was declared with the `export`
+	// modifier (`export func f(...)`), making it visible to importing
+	// modules through Module.Exports. An unexported function can still
+	// be called from within its own module, just not as `pkg.f` from
+	// another one.
+	Exported bool
 }
 
 type FunctionParameter struct {
 	Name *Identifier
 	Type TypeAnnotation
+
+	// MemorySpace is the optional GPU memory-space qualifier written
+	// before a kernel parameter ("global", "local", "shared", "private").
+	// It is "" for ordinary, non-kernel parameters.
+	MemorySpace string
 }
 
 type TypeAnnotation struct {
@@ -33,6 +67,15 @@ func (function *FunctionStatement) TokenLiteral() string {
 func (function *FunctionStatement) String() string {
 	var out bytes.Buffer
 
+	if function.Exported {
+		out.WriteString("export ")
+	}
+	if function.Extern {
+		out.WriteString("extern ")
+	}
+	if function.Kernel {
+		out.WriteString("kernel ")
+	}
 	out.WriteString("func ")
 	out.WriteString(function.Name.String())
 	out.WriteString("(")
@@ -40,14 +83,28 @@ func (function *FunctionStatement) String() string {
 	// Join parameters with comma
 	params := []string{}
 	for _, param := range function.Parameters {
-		params = append(params, param.Name.String()+": "+param.Type.Value)
+		prefix := ""
+		if param.MemorySpace != "" {
+			prefix = param.MemorySpace + " "
+		}
+		params = append(params, prefix+param.Name.String()+": "+param.Type.Value)
+	}
+	if function.Variadic {
+		params = append(params, "...")
 	}
 	out.WriteString(strings.Join(params, ", "))
 
 	out.WriteString(")")
 
-	// Add return type if exists
-	if function.ReturnType != nil {
+	// Add return type(s) if any exist
+	if len(function.ReturnTypes) > 1 {
+		returnTypes := []string{}
+		for _, returnType := range function.ReturnTypes {
+			returnTypes = append(returnTypes, returnType.Value)
+		}
+		out.WriteString(" -> ")
+		out.WriteString(strings.Join(returnTypes, ", "))
+	} else if function.ReturnType != nil {
 		out.WriteString(" -> ")
 		out.WriteString(function.ReturnType.Value)
 	}
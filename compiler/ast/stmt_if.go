@@ -0,0 +1,39 @@
+package ast
+
+import (
+	"bytes"
+	"compiler/token"
+)
+
+// IfStatement is `if condition { ... }` with an optional `else { ... }`.
+// An `else if` chain is represented by wrapping a nested *IfStatement in a
+// single-statement Alternative block, so Alternative is always nil or a
+// *BlockStatement.
+type IfStatement struct {
+	Token       token.Token
+	Condition   Expression
+	Consequence *BlockStatement
+	Alternative *BlockStatement
+}
+
+func (ifStatement *IfStatement) statementNode() {}
+
+func (ifStatement *IfStatement) TokenLiteral() string {
+	return ifStatement.Token.Value
+}
+
+func (ifStatement *IfStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("if ")
+	out.WriteString(ifStatement.Condition.String())
+	out.WriteString(" ")
+	out.WriteString(ifStatement.Consequence.String())
+
+	if ifStatement.Alternative != nil {
+		out.WriteString(" else ")
+		out.WriteString(ifStatement.Alternative.String())
+	}
+
+	return out.String()
+}
@@ -0,0 +1,30 @@
+package ast
+
+import (
+	"bytes"
+	"compiler/token"
+)
+
+// ImportStatement is `import "path"`, bringing another module's exported
+// `let`/`func` declarations into scope under the name compiler/modules
+// derives from Path (see modules.Importer).
+type ImportStatement struct {
+	Token token.Token
+	Path  string
+}
+
+func (importStatement *ImportStatement) statementNode() {}
+
+func (importStatement *ImportStatement) TokenLiteral() string {
+	return importStatement.Token.Value
+}
+
+func (importStatement *ImportStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("import \"")
+	out.WriteString(importStatement.Path)
+	out.WriteString("\"")
+
+	return out.String()
+}
@@ -3,6 +3,7 @@ package ast
 import (
 	"bytes"
 	"compiler/token"
+	"strings"
 )
 
 type LetStatement struct {
@@ -11,6 +12,15 @@ type LetStatement struct {
 	Value          Expression
 	TypeAnnotation *TypeAnnotation
 	Mutable        bool
+
+	// Names holds every bound identifier when destructuring a
+	// multi-value return (e.g. `let a, b = foo()`). It is nil for the
+	// common single-name case, where Name alone is authoritative.
+	Names []*Identifier
+
+	// Exported is true when the declaration was written `export let ...`,
+	// making it visible to importing modules through Module.Exports.
+	Exported bool
 }
 
 func (let *LetStatement) statementNode() {}
@@ -22,11 +32,23 @@ func (let *LetStatement) TokenLiteral() string {
 func (let *LetStatement) String() string {
 	var out bytes.Buffer
 
+	if let.Exported {
+		out.WriteString("export ")
+	}
 	out.WriteString(let.TokenLiteral() + " ")
 	if let.Mutable {
 		out.WriteString("mut ")
 	}
-	out.WriteString(let.Name.String())
+
+	if len(let.Names) > 1 {
+		names := make([]string, len(let.Names))
+		for i, name := range let.Names {
+			names[i] = name.String()
+		}
+		out.WriteString(strings.Join(names, ", "))
+	} else {
+		out.WriteString(let.Name.String())
+	}
 
 	// Add type annotation if present
 	if let.TypeAnnotation != nil {
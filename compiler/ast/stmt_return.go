@@ -3,11 +3,18 @@ package ast
 import (
 	"bytes"
 	"compiler/token"
+	"strings"
 )
 
 type ReturnStatement struct {
 	Token       token.Token
 	ReturnValue Expression
+
+	// ReturnValues holds every returned expression when the statement
+	// returns more than one value (e.g. `return a, b`). It is nil for
+	// the common zero- or single-value case, where ReturnValue alone
+	// is authoritative.
+	ReturnValues []Expression
 }
 
 func (r *ReturnStatement) statementNode() {}
@@ -21,7 +28,14 @@ func (r *ReturnStatement) String() string {
 
 	out.WriteString(r.TokenLiteral())
 
-	if r.ReturnValue != nil {
+	if len(r.ReturnValues) > 1 {
+		values := make([]string, len(r.ReturnValues))
+		for i, value := range r.ReturnValues {
+			values[i] = value.String()
+		}
+		out.WriteString(" ")
+		out.WriteString(strings.Join(values, ", "))
+	} else if r.ReturnValue != nil {
 		out.WriteString(" ")
 		out.WriteString(r.ReturnValue.String())
 	}
@@ -0,0 +1,56 @@
+package ast
+
+import (
+	"bytes"
+	"compiler/token"
+)
+
+// StructStatement declares a plain data aggregate with typed fields and
+// no methods, e.g.
+//
+//	struct Point {
+//	    x: int
+//	    y: int
+//	}
+//
+// Unlike ClassStatement, a struct has no receiver methods: field access
+// (`p.x`) lowers the same way as a class's, through MemberExpression, but
+// there's no `this` and no mangled method functions to generate.
+type StructStatement struct {
+	Token  token.Token
+	Name   *Identifier
+	Fields []*StructField
+}
+
+// StructField is a single `name: Type` field declaration inside a struct
+// body.
+type StructField struct {
+	Name *Identifier
+	Type TypeAnnotation
+}
+
+func (structStmt *StructStatement) statementNode() {}
+
+func (structStmt *StructStatement) TokenLiteral() string {
+	return structStmt.Token.Value
+}
+
+func (structStmt *StructStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("struct ")
+	out.WriteString(structStmt.Name.String())
+	out.WriteString(" {\n")
+
+	for _, field := range structStmt.Fields {
+		out.WriteString("  ")
+		out.WriteString(field.Name.String())
+		out.WriteString(": ")
+		out.WriteString(field.Type.Value)
+		out.WriteString("\n")
+	}
+
+	out.WriteString("}")
+
+	return out.String()
+}
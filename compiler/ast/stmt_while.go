@@ -0,0 +1,31 @@
+package ast
+
+import (
+	"bytes"
+	"compiler/token"
+)
+
+// WhileStatement is `while condition { ... }`, looping Body for as long as
+// Condition holds.
+type WhileStatement struct {
+	Token     token.Token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (whileStatement *WhileStatement) statementNode() {}
+
+func (whileStatement *WhileStatement) TokenLiteral() string {
+	return whileStatement.Token.Value
+}
+
+func (whileStatement *WhileStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("while ")
+	out.WriteString(whileStatement.Condition.String())
+	out.WriteString(" ")
+	out.WriteString(whileStatement.Body.String())
+
+	return out.String()
+}
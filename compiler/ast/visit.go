@@ -0,0 +1,126 @@
+package ast
+
+// ModifierFunc rewrites a single node, returning the node that should take
+// its place - itself if unchanged, or a different Node if the modifier
+// wants to replace it.
+type ModifierFunc func(Node) Node
+
+// Modify walks node and every descendant, replacing each child in place
+// with the result of recursively modifying it before finally calling
+// modifier on node itself, so a modifier that matches on a leaf kind (e.g.
+// *IntegerLiteral) sees every occurrence of it regardless of how deeply
+// nested. It's the mutating counterpart to Walk below, and the substrate
+// later passes (constant folding, macro expansion, desugaring) can build
+// on instead of hand-rolling their own switch over concrete node types.
+// Declaration names are left alone: the Name of a FunctionStatement,
+// ClassStatement or StructStatement, and class/struct field names, are
+// not visited.
+//
+// Like children in print.go, Modify has to type-switch over every node
+// kind by hand: Node only exposes TokenLiteral/String, so there's no way
+// to discover a node's fields generically.
+func Modify(node Node, modifier ModifierFunc) Node {
+	switch n := node.(type) {
+	case *Program:
+		for i, statement := range n.Statements {
+			n.Statements[i] = Modify(statement, modifier).(Statement)
+		}
+	case *LetStatement:
+		if len(n.Names) > 1 {
+			for i, name := range n.Names {
+				n.Names[i] = Modify(name, modifier).(*Identifier)
+			}
+		} else if n.Name != nil {
+			n.Name = Modify(n.Name, modifier).(*Identifier)
+		}
+		if n.Value != nil {
+			n.Value = Modify(n.Value, modifier).(Expression)
+		}
+	case *FunctionStatement:
+		for _, param := range n.Parameters {
+			param.Name = Modify(param.Name, modifier).(*Identifier)
+		}
+		if n.Body != nil {
+			n.Body = Modify(n.Body, modifier).(*BlockStatement)
+		}
+	case *ReturnStatement:
+		if len(n.ReturnValues) > 1 {
+			for i, value := range n.ReturnValues {
+				n.ReturnValues[i] = Modify(value, modifier).(Expression)
+			}
+		} else if n.ReturnValue != nil {
+			n.ReturnValue = Modify(n.ReturnValue, modifier).(Expression)
+		}
+	case *ExpressionStatement:
+		n.Expression = Modify(n.Expression, modifier).(Expression)
+	case *BlockStatement:
+		for i, statement := range n.Statements {
+			n.Statements[i] = Modify(statement, modifier).(Statement)
+		}
+	case *AssignmentStatement:
+		n.Name = Modify(n.Name, modifier).(*Identifier)
+		n.Value = Modify(n.Value, modifier).(Expression)
+	case *IfStatement:
+		n.Condition = Modify(n.Condition, modifier).(Expression)
+		n.Consequence = Modify(n.Consequence, modifier).(*BlockStatement)
+		if n.Alternative != nil {
+			n.Alternative = Modify(n.Alternative, modifier).(*BlockStatement)
+		}
+	case *WhileStatement:
+		n.Condition = Modify(n.Condition, modifier).(Expression)
+		n.Body = Modify(n.Body, modifier).(*BlockStatement)
+	case *EventHandlerStatement:
+		n.EventName = Modify(n.EventName, modifier).(*Identifier)
+		for _, param := range n.Parameters {
+			param.Name = Modify(param.Name, modifier).(*Identifier)
+		}
+		if n.Body != nil {
+			n.Body = Modify(n.Body, modifier).(*BlockStatement)
+		}
+	case *ClassStatement:
+		for i, method := range n.Methods {
+			n.Methods[i] = Modify(method, modifier).(*FunctionStatement)
+		}
+	case *BinaryExpression:
+		n.Left = Modify(n.Left, modifier).(Expression)
+		n.Right = Modify(n.Right, modifier).(Expression)
+	case *UnaryExpression:
+		n.Operand = Modify(n.Operand, modifier).(Expression)
+	case *CallExpression:
+		n.Function = Modify(n.Function, modifier).(Expression)
+		for i, argument := range n.Arguments {
+			n.Arguments[i] = Modify(argument, modifier).(Expression)
+		}
+	case *MemberExpression:
+		n.Object = Modify(n.Object, modifier).(Expression)
+
+	// Leaf kinds: IntegerLiteral, FloatLiteral, StringLiteral,
+	// BooleanLiteral, Identifier, ThisExpression, ImportStatement,
+	// StructStatement, BreakStatement - nothing Modify descends into, fall
+	// through to the modifier call below.
+	case *IntegerLiteral, *FloatLiteral, *StringLiteral, *BooleanLiteral,
+		*Identifier, *ThisExpression, *ImportStatement, *StructStatement,
+		*BreakStatement:
+	}
+
+	return modifier(node)
+}
+
+// Walk is Modify's read-only counterpart: it calls visit on node and then,
+// if visit returned true, walks each of node's children in turn. A node is
+// visited before its descendants, the reverse of Modify, which calls
+// modifier after them; visit returning false skips only that node's
+// subtree, and the walk goes on with its siblings. It reuses children (see
+// print.go) rather than repeating Modify's type switch, since it only
+// needs to enumerate a node's immediate children, not reassign them; so it
+// visits the nodes Fprint prints, which include the class/struct names and
+// field names Modify leaves alone.
+func Walk(node Node, visit func(Node) bool) {
+	if node == nil || isNilNode(node) || !visit(node) {
+		return
+	}
+
+	for _, child := range children(node) {
+		Walk(child, visit)
+	}
+}
@@ -0,0 +1,93 @@
+package ast
+
+import "testing"
+
+// doubleIntegers is a ModifierFunc that rewrites every IntegerLiteral to
+// twice its value, for exercising Modify's descent into each node kind.
+func doubleIntegers(node Node) Node {
+	integer, ok := node.(*IntegerLiteral)
+	if !ok {
+		return node
+	}
+
+	switch integer.Value {
+	case "1":
+		integer.Value = "2"
+	case "2":
+		integer.Value = "4"
+	case "3":
+		integer.Value = "6"
+	}
+	return integer
+}
+
+func TestModifyRewritesIntegerInBinaryTree(t *testing.T) {
+	tree := &BinaryExpression{
+		Left:     &IntegerLiteral{Value: "1"},
+		Operator: "+",
+		Right: &BinaryExpression{
+			Left:     &IntegerLiteral{Value: "2"},
+			Operator: "*",
+			Right:    &IntegerLiteral{Value: "3"},
+		},
+	}
+
+	modified := Modify(tree, doubleIntegers).(*BinaryExpression)
+
+	if modified.Left.(*IntegerLiteral).Value != "2" {
+		t.Errorf("left operand = %s, want 2", modified.Left.(*IntegerLiteral).Value)
+	}
+	nested := modified.Right.(*BinaryExpression)
+	if nested.Left.(*IntegerLiteral).Value != "4" {
+		t.Errorf("nested left operand = %s, want 4", nested.Left.(*IntegerLiteral).Value)
+	}
+	if nested.Right.(*IntegerLiteral).Value != "6" {
+		t.Errorf("nested right operand = %s, want 6", nested.Right.(*IntegerLiteral).Value)
+	}
+}
+
+func TestModifyRewritesIntegersInCallArguments(t *testing.T) {
+	call := &CallExpression{
+		Function: &Identifier{Value: "add"},
+		Arguments: []Expression{
+			&IntegerLiteral{Value: "1"},
+			&IntegerLiteral{Value: "2"},
+		},
+	}
+
+	modified := Modify(call, doubleIntegers).(*CallExpression)
+
+	want := []string{"2", "4"}
+	for i, argument := range modified.Arguments {
+		if got := argument.(*IntegerLiteral).Value; got != want[i] {
+			t.Errorf("argument %d = %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestWalkVisitsEveryNodeAndCanStopEarly(t *testing.T) {
+	program := &Program{
+		Statements: []Statement{
+			&ExpressionStatement{Expression: &IntegerLiteral{Value: "1"}},
+			&ExpressionStatement{Expression: &IntegerLiteral{Value: "2"}},
+		},
+	}
+
+	var visited int
+	Walk(program, func(Node) bool {
+		visited++
+		return true
+	})
+	if visited != 5 { // Program, 2x ExpressionStatement, 2x IntegerLiteral
+		t.Errorf("visited %d nodes, want 5", visited)
+	}
+
+	var stoppedAfter int
+	Walk(program, func(Node) bool {
+		stoppedAfter++
+		return false
+	})
+	if stoppedAfter != 1 {
+		t.Errorf("visit returning false stopped after %d nodes, want 1", stoppedAfter)
+	}
+}
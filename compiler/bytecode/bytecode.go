@@ -0,0 +1,18 @@
+package bytecode
+
+import "compiler/token"
+
+// Bytecode is the compiled output of a Compiler run: the entry point's
+// instruction stream, every constant (Integer, CompiledFunction, Builtin)
+// referenced from it, and a map back to source positions for error
+// reporting and future debug-info support.
+type Bytecode struct {
+	Instructions []byte
+	Constants    []Value
+
+	// SourceMap records the source position a given instruction (keyed
+	// by its starting offset within Instructions) was emitted from.
+	// Instructions synthesized by the compiler itself (e.g. an implicit
+	// trailing OpReturn) have no entry.
+	SourceMap map[int]token.Position
+}
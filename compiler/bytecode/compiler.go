@@ -0,0 +1,338 @@
+package bytecode
+
+import (
+	"compiler/parser"
+	"compiler/token"
+	"fmt"
+)
+
+// EmittedInstruction records where in the current scope's instruction
+// stream an opcode was written, so the compiler can later inspect or
+// rewrite (e.g. patch jump targets into) the most recent instructions.
+type EmittedInstruction struct {
+	Opcode   Opcode
+	Position int
+}
+
+// compilationScope holds one function body's in-progress instruction
+// stream. A new one is pushed for every parser.FunctionDeclaration so
+// jump-patching and the "last instruction" tracking never cross a
+// function boundary.
+//
+// continues/breaks collect the offsets of OpJump instructions emitted by
+// a `continue`/`break` inside the loop currently being compiled, still
+// waiting to be patched once that loop's start/end addresses are known.
+// The legacy parser grammar has no while/for statement yet, so nothing
+// appends to these today; they exist so a future loop compiler can
+// backpatch on exit instead of inventing this bookkeeping from scratch.
+type compilationScope struct {
+	instructions        []byte
+	lastInstruction     EmittedInstruction
+	previousInstruction EmittedInstruction
+
+	continues []int
+	breaks    []int
+}
+
+// Compiler walks a parser.Program into a Bytecode, reusing the same
+// parser.FunctionDeclaration / BinaryExpression / LetStatement nodes the
+// ErlangGenerator consumes, but targeting the standalone register-VM
+// instead of the BEAM.
+type Compiler struct {
+	constants []Value
+	sourceMap map[int]token.Position
+	currentPos token.Position
+
+	symbolTable *SymbolTable
+
+	scopes     []compilationScope
+	scopeIndex int
+
+	// functionIndex maps a declared function's name to the constant
+	// slot holding its (eventually compiled) *CompiledFunction, so a
+	// call to a function declared later in the file still resolves.
+	functionIndex map[string]int
+	builtins      map[string]int
+}
+
+func New() *Compiler {
+	compiler := &Compiler{
+		sourceMap:     make(map[int]token.Position),
+		symbolTable:   NewSymbolTable(),
+		scopes:        []compilationScope{{}},
+		functionIndex: make(map[string]int),
+		builtins:      make(map[string]int),
+	}
+
+	compiler.defineBuiltin("print", builtinPrint)
+
+	return compiler
+}
+
+func (compiler *Compiler) defineBuiltin(name string, fn BuiltinFunction) {
+	compiler.builtins[name] = compiler.addConstant(&Builtin{Name: name, Fn: fn})
+}
+
+// Compile lowers every function in program into bytecode and returns the
+// module's entry point: push the `main` function and call it.
+func (compiler *Compiler) Compile(program *parser.Program) (*Bytecode, error) {
+	// Reserve a constant slot per function up front so a call to a
+	// function declared later in the file still has somewhere to point.
+	for _, function := range program.Functions {
+		compiler.functionIndex[function.Name] = compiler.addConstant(&CompiledFunction{})
+	}
+
+	mainIndex, hasMain := compiler.functionIndex["main"]
+	if !hasMain {
+		return nil, fmt.Errorf("bytecode: no main function declared")
+	}
+
+	for i := range program.Functions {
+		if err := compiler.compileFunction(&program.Functions[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	compiler.emit(OpConstant, mainIndex)
+	compiler.emit(OpCall, 0)
+	compiler.emit(OpPop)
+
+	return &Bytecode{
+		Instructions: compiler.currentInstructions(),
+		Constants:    compiler.constants,
+		SourceMap:    compiler.sourceMap,
+	}, nil
+}
+
+func (compiler *Compiler) compileFunction(function *parser.FunctionDeclaration) error {
+	compiler.enterScope()
+
+	for _, parameterName := range function.Parameters {
+		compiler.symbolTable.Define(parameterName)
+	}
+
+	if err := compiler.compileBlockStatement(&function.Body); err != nil {
+		return err
+	}
+
+	// The legacy grammar has no return statement, so every function
+	// implicitly returns 0 once its body finishes - the same convention
+	// CodeGenerator/CGenerator use for an implicit `return 0` from main.
+	compiler.emit(OpConstant, compiler.addConstant(&Integer{Value: 0}))
+	compiler.emit(OpReturn)
+
+	numLocals := compiler.symbolTable.NumDefinitions()
+	instructions := compiler.leaveScope()
+
+	index := compiler.functionIndex[function.Name]
+	compiler.constants[index] = &CompiledFunction{
+		Instructions:  instructions,
+		NumLocals:     numLocals,
+		NumParameters: len(function.Parameters),
+	}
+
+	return nil
+}
+
+func (compiler *Compiler) compileBlockStatement(block *parser.BlockStatement) error {
+	for _, statement := range block.Statements {
+		if err := compiler.compileStatement(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (compiler *Compiler) compileStatement(statement parser.Statement) error {
+	switch stmt := statement.(type) {
+	case *parser.LetStatement:
+		compiler.setPosition(stmt.Token.Line, stmt.Token.Column)
+		return compiler.compileLetStatement(stmt)
+	case *parser.ExpressionStatement:
+		compiler.setPosition(stmt.Token.Line, stmt.Token.Column)
+		return compiler.compileExpressionStatement(stmt)
+	case *parser.BlockStatement:
+		return compiler.compileBlockStatement(stmt)
+	default:
+		return fmt.Errorf("bytecode: unsupported statement type %T", stmt)
+	}
+}
+
+func (compiler *Compiler) compileLetStatement(let *parser.LetStatement) error {
+	if err := compiler.compileExpression(let.Value); err != nil {
+		return err
+	}
+
+	index := compiler.symbolTable.Define(let.Name)
+	compiler.emit(OpSetLocal, index)
+
+	return nil
+}
+
+func (compiler *Compiler) compileExpressionStatement(exprStmt *parser.ExpressionStatement) error {
+	if err := compiler.compileExpression(exprStmt.Expression); err != nil {
+		return err
+	}
+	compiler.emit(OpPop)
+	return nil
+}
+
+func (compiler *Compiler) compileExpression(expression parser.Expression) error {
+	switch expr := expression.(type) {
+	case *parser.NumberLiteral:
+		compiler.emit(OpConstant, compiler.addConstant(&Integer{Value: expr.Value}))
+		return nil
+	case *parser.Identifier:
+		return compiler.compileIdentifier(expr)
+	case *parser.BinaryExpression:
+		return compiler.compileBinaryExpression(expr)
+	case *parser.CallExpression:
+		return compiler.compileCallExpression(expr)
+	default:
+		return fmt.Errorf("bytecode: unsupported expression type %T", expr)
+	}
+}
+
+func (compiler *Compiler) compileIdentifier(identifier *parser.Identifier) error {
+	if index, ok := compiler.symbolTable.Resolve(identifier.Value); ok {
+		compiler.emit(OpGetLocal, index)
+		return nil
+	}
+
+	if index, ok := compiler.resolveCallee(identifier.Value); ok {
+		compiler.emit(OpConstant, index)
+		return nil
+	}
+
+	return fmt.Errorf("bytecode: undefined identifier %s", identifier.Value)
+}
+
+func (compiler *Compiler) compileBinaryExpression(binary *parser.BinaryExpression) error {
+	if err := compiler.compileExpression(binary.Left); err != nil {
+		return err
+	}
+	if err := compiler.compileExpression(binary.Right); err != nil {
+		return err
+	}
+
+	switch binary.Operator {
+	case "+":
+		compiler.emit(OpAdd)
+	case "-":
+		compiler.emit(OpSub)
+	case "*":
+		compiler.emit(OpMul)
+	case "/":
+		compiler.emit(OpDiv)
+	default:
+		return fmt.Errorf("bytecode: unsupported operator %s", binary.Operator)
+	}
+
+	return nil
+}
+
+func (compiler *Compiler) compileCallExpression(call *parser.CallExpression) error {
+	index, ok := compiler.resolveCallee(call.Function)
+	if !ok {
+		return fmt.Errorf("bytecode: undefined function %s", call.Function)
+	}
+
+	// Push the callee first, then its arguments, matching the stack
+	// layout OpCall/callFunction expect (VM.callFunction locates the
+	// callee at sp-1-numArgs).
+	compiler.emit(OpConstant, index)
+
+	for _, argument := range call.Arguments {
+		if err := compiler.compileExpression(argument); err != nil {
+			return err
+		}
+	}
+
+	compiler.emit(OpCall, len(call.Arguments))
+
+	return nil
+}
+
+func (compiler *Compiler) resolveCallee(name string) (int, bool) {
+	if index, ok := compiler.builtins[name]; ok {
+		return index, true
+	}
+	if index, ok := compiler.functionIndex[name]; ok {
+		return index, true
+	}
+	return 0, false
+}
+
+func (compiler *Compiler) addConstant(value Value) int {
+	compiler.constants = append(compiler.constants, value)
+	return len(compiler.constants) - 1
+}
+
+func (compiler *Compiler) setPosition(line, column int) {
+	compiler.currentPos = token.Position{Line: line, Column: column}
+}
+
+func (compiler *Compiler) currentInstructions() []byte {
+	return compiler.scopes[compiler.scopeIndex].instructions
+}
+
+func (compiler *Compiler) emit(op Opcode, operands ...int) int {
+	instruction := Make(op, operands...)
+	position := len(compiler.currentInstructions())
+
+	scope := &compiler.scopes[compiler.scopeIndex]
+	scope.instructions = append(scope.instructions, instruction...)
+
+	if compiler.currentPos.Line > 0 {
+		compiler.sourceMap[position] = compiler.currentPos
+	}
+
+	scope.previousInstruction = scope.lastInstruction
+	scope.lastInstruction = EmittedInstruction{Opcode: op, Position: position}
+
+	return position
+}
+
+// changeOperand overwrites the operand of an already-emitted instruction
+// in place - used to backpatch a forward jump once its target offset is
+// known (see continues/breaks).
+func (compiler *Compiler) changeOperand(position int, operand int) error {
+	instructions := compiler.currentInstructions()
+	op := Opcode(instructions[position])
+
+	if _, err := Lookup(op); err != nil {
+		return err
+	}
+
+	newInstruction := Make(op, operand)
+	copy(instructions[position:position+len(newInstruction)], newInstruction)
+
+	return nil
+}
+
+func (compiler *Compiler) enterScope() {
+	compiler.scopes = append(compiler.scopes, compilationScope{})
+	compiler.scopeIndex++
+	compiler.symbolTable = NewEnclosedSymbolTable(compiler.symbolTable)
+}
+
+func (compiler *Compiler) leaveScope() []byte {
+	instructions := compiler.currentInstructions()
+
+	compiler.scopes = compiler.scopes[:len(compiler.scopes)-1]
+	compiler.scopeIndex--
+	compiler.symbolTable = compiler.symbolTable.parent
+
+	return instructions
+}
+
+func builtinPrint(arguments ...Value) Value {
+	for i, argument := range arguments {
+		if i > 0 {
+			fmt.Print(" ")
+		}
+		fmt.Print(argument.String())
+	}
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,18 @@
+package bytecode
+
+// Frame is one call's activation record: the CompiledFunction being
+// executed, its instruction pointer, and the stack index its locals and
+// arguments start at.
+type Frame struct {
+	fn          *CompiledFunction
+	ip          int
+	basePointer int
+}
+
+func NewFrame(fn *CompiledFunction, basePointer int) *Frame {
+	return &Frame{fn: fn, ip: -1, basePointer: basePointer}
+}
+
+func (frame *Frame) Instructions() []byte {
+	return frame.fn.Instructions
+}
@@ -0,0 +1,97 @@
+package bytecode
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Opcode identifies a single bytecode instruction. Each instruction is a
+// one-byte opcode followed by zero or more big-endian operands, the exact
+// widths of which are given by that opcode's Definition.
+type Opcode byte
+
+const (
+	OpConstant    Opcode = iota // push Constants[operand] (2-byte operand)
+	OpAdd                       // pop b, a; push a + b
+	OpSub                       // pop b, a; push a - b
+	OpMul                       // pop b, a; push a * b
+	OpDiv                       // pop b, a; push a / b
+	OpGetLocal                  // push the current frame's local at operand (1-byte operand)
+	OpSetLocal                  // pop and store into the current frame's local at operand (1-byte operand)
+	OpCall                      // call the function pushed numArgs below the top of the stack (1-byte operand: numArgs)
+	OpReturn                    // pop the return value, pop the current frame, push the return value
+	OpJump                      // unconditional jump to operand (2-byte operand: absolute instruction index)
+	OpJumpIfFalse               // pop a condition; jump to operand if it was falsy (2-byte operand)
+	OpPop                       // discard the top of the stack (used to clear expression-statement results)
+)
+
+// Definition describes how to disassemble and encode one opcode.
+type Definition struct {
+	Name          string
+	OperandWidths []int // bytes per operand, in order
+}
+
+var definitions = map[Opcode]*Definition{
+	OpConstant:    {"OpConstant", []int{2}},
+	OpAdd:         {"OpAdd", []int{}},
+	OpSub:         {"OpSub", []int{}},
+	OpMul:         {"OpMul", []int{}},
+	OpDiv:         {"OpDiv", []int{}},
+	OpGetLocal:    {"OpGetLocal", []int{1}},
+	OpSetLocal:    {"OpSetLocal", []int{1}},
+	OpCall:        {"OpCall", []int{1}},
+	OpReturn:      {"OpReturn", []int{}},
+	OpJump:        {"OpJump", []int{2}},
+	OpJumpIfFalse: {"OpJumpIfFalse", []int{2}},
+	OpPop:         {"OpPop", []int{}},
+}
+
+// Lookup returns the Definition for op, or an error if op is unknown.
+func Lookup(op Opcode) (*Definition, error) {
+	definition, ok := definitions[op]
+	if !ok {
+		return nil, fmt.Errorf("opcode %d undefined", op)
+	}
+	return definition, nil
+}
+
+// Make encodes op and its operands into a single variable-width
+// instruction.
+func Make(op Opcode, operands ...int) []byte {
+	definition, ok := definitions[op]
+	if !ok {
+		return []byte{}
+	}
+
+	instructionLength := 1
+	for _, width := range definition.OperandWidths {
+		instructionLength += width
+	}
+
+	instruction := make([]byte, instructionLength)
+	instruction[0] = byte(op)
+
+	offset := 1
+	for i, operand := range operands {
+		width := definition.OperandWidths[i]
+		switch width {
+		case 2:
+			binary.BigEndian.PutUint16(instruction[offset:], uint16(operand))
+		case 1:
+			instruction[offset] = byte(operand)
+		}
+		offset += width
+	}
+
+	return instruction
+}
+
+// ReadUint16 decodes a 2-byte big-endian operand starting at instructions[offset].
+func ReadUint16(instructions []byte, offset int) uint16 {
+	return binary.BigEndian.Uint16(instructions[offset:])
+}
+
+// ReadUint8 decodes a 1-byte operand at instructions[offset].
+func ReadUint8(instructions []byte, offset int) uint8 {
+	return instructions[offset]
+}
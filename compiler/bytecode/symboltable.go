@@ -0,0 +1,62 @@
+package bytecode
+
+import "compiler/types"
+
+// SymbolTable adapts the AST-level types.SymbolTable to the VM's needs.
+// types.SymbolTable already tracks name -> Type scoping, which is exactly
+// the shape a compiler wants for resolving identifiers; it just has no
+// notion of a flat local-variable slot. This wraps one and hands out that
+// slot index alongside the existing Define/Lookup behavior, instead of
+// duplicating scope-chain bookkeeping from scratch.
+type SymbolTable struct {
+	types  *types.SymbolTable
+	parent *SymbolTable
+
+	indices        map[string]int
+	numDefinitions int
+}
+
+func NewSymbolTable() *SymbolTable {
+	return &SymbolTable{
+		types:   types.NewSymbolTable(),
+		indices: make(map[string]int),
+	}
+}
+
+func NewEnclosedSymbolTable(parent *SymbolTable) *SymbolTable {
+	return &SymbolTable{
+		types:   parent.types.NewChildScope(),
+		parent:  parent,
+		indices: make(map[string]int),
+	}
+}
+
+// Define registers name as a local of type int and returns the slot index
+// OpGetLocal/OpSetLocal should address it by within the current scope.
+func (table *SymbolTable) Define(name string) int {
+	index := table.numDefinitions
+	table.types.Define(name, types.Int, true)
+	table.indices[name] = index
+	table.numDefinitions++
+	return index
+}
+
+// Resolve looks up name as a local in this scope or any enclosing one,
+// returning the slot index to address it by.
+func (table *SymbolTable) Resolve(name string) (index int, ok bool) {
+	if index, found := table.indices[name]; found {
+		return index, true
+	}
+
+	if table.parent != nil {
+		return table.parent.Resolve(name)
+	}
+
+	return 0, false
+}
+
+// NumDefinitions reports how many locals have been defined directly in
+// this scope (not counting parents).
+func (table *SymbolTable) NumDefinitions() int {
+	return table.numDefinitions
+}
@@ -0,0 +1,53 @@
+package bytecode
+
+import "fmt"
+
+// Value is anything the VM can push onto its value stack or store as a
+// constant: an Integer literal, a CompiledFunction the VM can call, or a
+// Builtin bridging out to the host (e.g. print).
+type Value interface {
+	valueMarker()
+	String() string
+}
+
+// Integer is the only literal type the legacy MVP parser produces today
+// (parser.NumberLiteral). String/float/bool constants fall out of this
+// the same way they fell out of parser.Program in the first place.
+type Integer struct {
+	Value int64
+}
+
+func (integer *Integer) valueMarker() {}
+func (integer *Integer) String() string {
+	return fmt.Sprintf("%d", integer.Value)
+}
+
+// CompiledFunction is a parser.FunctionDeclaration lowered to bytecode.
+// It's stored as a constant, like any other Value, and pushed onto the
+// stack before an OpCall.
+type CompiledFunction struct {
+	Instructions  []byte
+	NumLocals     int
+	NumParameters int
+}
+
+func (function *CompiledFunction) valueMarker() {}
+func (function *CompiledFunction) String() string {
+	return fmt.Sprintf("CompiledFunction[%d bytes]", len(function.Instructions))
+}
+
+// BuiltinFunction is a host function the VM invokes directly instead of
+// dispatching into compiled instructions (e.g. "print").
+type BuiltinFunction func(arguments ...Value) Value
+
+// Builtin wraps a BuiltinFunction so it can live in the constant pool
+// alongside Integer and CompiledFunction values.
+type Builtin struct {
+	Name string
+	Fn   BuiltinFunction
+}
+
+func (builtin *Builtin) valueMarker() {}
+func (builtin *Builtin) String() string {
+	return fmt.Sprintf("builtin %s", builtin.Name)
+}
@@ -0,0 +1,216 @@
+package bytecode
+
+import "fmt"
+
+const (
+	stackSize = 2048
+	maxFrames = 1024
+)
+
+// VM is a stack machine: a flat value stack shared by every frame, plus a
+// frame stack giving each call its own instruction pointer and base
+// pointer into that shared stack.
+type VM struct {
+	constants []Value
+
+	stack []Value
+	sp    int // stack[sp-1] is the top of the stack
+
+	frames      []*Frame
+	framesIndex int
+}
+
+// NewVM wraps bc's entry-point instructions in a synthetic frame (distinct
+// from any user-defined `main`, which already got called via OpCall inside
+// those instructions) and prepares an empty stack to run it on.
+func NewVM(bc *Bytecode) *VM {
+	entryPoint := &CompiledFunction{Instructions: bc.Instructions}
+	entryFrame := NewFrame(entryPoint, 0)
+
+	frames := make([]*Frame, maxFrames)
+	frames[0] = entryFrame
+
+	return &VM{
+		constants:   bc.Constants,
+		stack:       make([]Value, stackSize),
+		frames:      frames,
+		framesIndex: 1,
+	}
+}
+
+func (vm *VM) currentFrame() *Frame {
+	return vm.frames[vm.framesIndex-1]
+}
+
+func (vm *VM) pushFrame(frame *Frame) {
+	vm.frames[vm.framesIndex] = frame
+	vm.framesIndex++
+}
+
+func (vm *VM) popFrame() *Frame {
+	vm.framesIndex--
+	return vm.frames[vm.framesIndex]
+}
+
+// Run executes the entry-point frame to completion.
+func (vm *VM) Run() error {
+	for vm.currentFrame().ip < len(vm.currentFrame().Instructions())-1 {
+		vm.currentFrame().ip++
+
+		ip := vm.currentFrame().ip
+		instructions := vm.currentFrame().Instructions()
+		op := Opcode(instructions[ip])
+
+		switch op {
+		case OpConstant:
+			constIndex := ReadUint16(instructions, ip+1)
+			vm.currentFrame().ip += 2
+			if err := vm.push(vm.constants[constIndex]); err != nil {
+				return err
+			}
+
+		case OpAdd, OpSub, OpMul, OpDiv:
+			if err := vm.executeBinaryOperation(op); err != nil {
+				return err
+			}
+
+		case OpPop:
+			vm.pop()
+
+		case OpGetLocal:
+			slot := ReadUint8(instructions, ip+1)
+			vm.currentFrame().ip += 1
+			frame := vm.currentFrame()
+			if err := vm.push(vm.stack[frame.basePointer+int(slot)]); err != nil {
+				return err
+			}
+
+		case OpSetLocal:
+			slot := ReadUint8(instructions, ip+1)
+			vm.currentFrame().ip += 1
+			frame := vm.currentFrame()
+			vm.stack[frame.basePointer+int(slot)] = vm.pop()
+
+		case OpJump:
+			target := ReadUint16(instructions, ip+1)
+			vm.currentFrame().ip = int(target) - 1
+
+		case OpJumpIfFalse:
+			target := ReadUint16(instructions, ip+1)
+			vm.currentFrame().ip += 2
+			condition := vm.pop()
+			if !vm.isTruthy(condition) {
+				vm.currentFrame().ip = int(target) - 1
+			}
+
+		case OpCall:
+			numArgs := int(ReadUint8(instructions, ip+1))
+			vm.currentFrame().ip += 1
+			if err := vm.callFunction(numArgs); err != nil {
+				return err
+			}
+
+		case OpReturn:
+			returnValue := vm.pop()
+
+			frame := vm.popFrame()
+			vm.sp = frame.basePointer - 1
+
+			if err := vm.push(returnValue); err != nil {
+				return err
+			}
+
+		default:
+			return fmt.Errorf("bytecode: unknown opcode %d", op)
+		}
+	}
+
+	return nil
+}
+
+func (vm *VM) callFunction(numArgs int) error {
+	callee := vm.stack[vm.sp-1-numArgs]
+
+	switch fn := callee.(type) {
+	case *CompiledFunction:
+		if numArgs != fn.NumParameters {
+			return fmt.Errorf("bytecode: wrong number of arguments: want %d, got %d", fn.NumParameters, numArgs)
+		}
+
+		frame := NewFrame(fn, vm.sp-numArgs)
+		vm.pushFrame(frame)
+		vm.sp = frame.basePointer + fn.NumLocals
+
+		return nil
+
+	case *Builtin:
+		arguments := vm.stack[vm.sp-numArgs : vm.sp]
+		result := fn.Fn(arguments...)
+
+		vm.sp = vm.sp - numArgs - 1
+		if result == nil {
+			result = &Integer{Value: 0}
+		}
+		return vm.push(result)
+
+	default:
+		return fmt.Errorf("bytecode: calling non-function %s", callee.String())
+	}
+}
+
+func (vm *VM) executeBinaryOperation(op Opcode) error {
+	right := vm.pop()
+	left := vm.pop()
+
+	leftInt, leftOk := left.(*Integer)
+	rightInt, rightOk := right.(*Integer)
+	if !leftOk || !rightOk {
+		return fmt.Errorf("bytecode: unsupported operand types for binary operation: %s %s", left.String(), right.String())
+	}
+
+	var result int64
+	switch op {
+	case OpAdd:
+		result = leftInt.Value + rightInt.Value
+	case OpSub:
+		result = leftInt.Value - rightInt.Value
+	case OpMul:
+		result = leftInt.Value * rightInt.Value
+	case OpDiv:
+		result = leftInt.Value / rightInt.Value
+	}
+
+	return vm.push(&Integer{Value: result})
+}
+
+func (vm *VM) isTruthy(value Value) bool {
+	if integer, ok := value.(*Integer); ok {
+		return integer.Value != 0
+	}
+	return true
+}
+
+func (vm *VM) push(value Value) error {
+	if vm.sp >= stackSize {
+		return fmt.Errorf("bytecode: stack overflow")
+	}
+	vm.stack[vm.sp] = value
+	vm.sp++
+	return nil
+}
+
+func (vm *VM) pop() Value {
+	value := vm.stack[vm.sp-1]
+	vm.sp--
+	return value
+}
+
+// StackTop returns the value on top of the stack (stack[sp-1]), or nil
+// when the stack is empty. After a complete Run it is nil, since the entry
+// point pops main's return value.
+func (vm *VM) StackTop() Value {
+	if vm.sp == 0 {
+		return nil
+	}
+	return vm.stack[vm.sp-1]
+}
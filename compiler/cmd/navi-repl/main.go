@@ -0,0 +1,490 @@
+// Command navi-repl is an interactive read-eval-print loop for Naviary,
+// giving the lexer/parser and the compiler/typechecker registry package a
+// second real client besides the `naviary`/`naviary run` compiler pipeline
+// (compiler/main.go). It does not call compiler/type-checker, the actual
+// type-checking pass: inferType/inferBinaryType below are a separate,
+// much lighter ad-hoc inference used only to answer `:type` and to
+// register let/function signatures into the session's typechecker.Registry.
+// Each accepted line is parsed, folded into the session's accumulated
+// declarations, then the whole session is recompiled through NIR, LLVM,
+// and MCJIT and its synthetic main is run - there's no incremental
+// codegen in this repo to build true per-line execution on top of, so
+// re-running the full program is the simplest correct option and means
+// earlier print() calls replay on every subsequent line.
+package main
+
+import (
+	"bufio"
+	"compiler/ast"
+	"compiler/codegen/llvm"
+	"compiler/codegen/llvm/jit"
+	"compiler/errors"
+	"compiler/lexer"
+	"compiler/nir"
+	"compiler/nir/opt"
+	"compiler/nir/ssa"
+	"compiler/parser"
+	"compiler/sema"
+	"compiler/typechecker"
+	"compiler/types"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// session holds everything that must survive from one REPL input to the
+// next: every function the user has declared, every other top-level
+// statement (assembled into a synthetic main, see program), and a
+// persistent typechecker.Registry so `let x = 5` on one line makes x's
+// type visible to `:type x` or `x + 1` on the next.
+type session struct {
+	functions []*ast.FunctionStatement
+	mainBody  []ast.Statement
+	registry  *typechecker.Registry
+}
+
+func newSession() *session {
+	return &session{registry: typechecker.New()}
+}
+
+func (s *session) reset() {
+	s.functions = nil
+	s.mainBody = nil
+	s.registry = typechecker.New()
+}
+
+// hasMain reports whether the user has declared their own `func main`,
+// in which case program uses it as-is instead of synthesizing one.
+func (s *session) hasMain() bool {
+	for _, function := range s.functions {
+		if function.Name.Value == "main" {
+			return true
+		}
+	}
+	return false
+}
+
+// program assembles the session's accumulated declarations into a
+// runnable *ast.Program.
+func (s *session) program() *ast.Program {
+	program := &ast.Program{}
+
+	for _, function := range s.functions {
+		program.Statements = append(program.Statements, function)
+	}
+
+	if !s.hasMain() {
+		program.Statements = append(program.Statements, &ast.FunctionStatement{
+			Name: &ast.Identifier{Value: "main"},
+			Body: &ast.BlockStatement{Statements: s.mainBody},
+		})
+	}
+
+	return program
+}
+
+// addStatement folds one freshly parsed top-level statement into the
+// session, registering a let's inferred type or a function's signature
+// with the registry when possible. A registry.Register failure (the name
+// is already bound) is ignored rather than reported: the registry keeps
+// the first binding's entry, so `:type` still shows that type, while the
+// session's functions/mainBody slices, which are what gets compiled and
+// run, take the redefinition.
+func (s *session) addStatement(statement ast.Statement) {
+	switch statement := statement.(type) {
+	case *ast.FunctionStatement:
+		s.replaceFunction(statement)
+		s.registry.Register(statement.Name.Value, typechecker.NewFunctionEntry(statement.Name.Value, functionSignature(statement)))
+	case *ast.LetStatement:
+		s.mainBody = append(s.mainBody, statement)
+		if statement.Name == nil || len(statement.Names) > 1 {
+			return
+		}
+		if inferredType, err := inferType(statement.Value, s.registry); err == nil {
+			s.registry.Register(statement.Name.Value, typechecker.NewVariableEntry(statement.Name.Value, inferredType))
+		}
+	default:
+		s.mainBody = append(s.mainBody, statement)
+	}
+}
+
+// replaceFunction overwrites an earlier declaration with the same name,
+// so redeclaring a function at the prompt doesn't also emit a duplicate
+// LLVM function definition.
+func (s *session) replaceFunction(function *ast.FunctionStatement) {
+	for i, existing := range s.functions {
+		if existing.Name.Value == function.Name.Value {
+			s.functions[i] = function
+			return
+		}
+	}
+	s.functions = append(s.functions, function)
+}
+
+// functionSignature converts a FunctionStatement's declared parameter
+// and return types to a *types.FunctionType, the same annotation-name
+// mapping sema.Resolver.resolveTypeAnnotation uses.
+func functionSignature(function *ast.FunctionStatement) *types.FunctionType {
+	parameterTypes := make([]types.Type, len(function.Parameters))
+	for i, parameter := range function.Parameters {
+		parameterTypes[i] = annotationType(&parameter.Type)
+	}
+
+	returnType := types.Type(types.Nil)
+	if function.ReturnType != nil {
+		returnType = annotationType(function.ReturnType)
+	}
+
+	return &types.FunctionType{ParameterTypes: parameterTypes, ReturnType: returnType}
+}
+
+// annotationType resolves a TypeAnnotation's name to a types.Type,
+// defaulting to types.Int for an unrecognized name (a class/struct
+// annotation) - the repl doesn't track declared class/struct types.
+func annotationType(annotation *ast.TypeAnnotation) types.Type {
+	if primitive := types.GetPrimitiveType(annotation.Value); primitive != nil {
+		return primitive
+	}
+	if annotation.Value == "nil" {
+		return types.Nil
+	}
+	return types.Int
+}
+
+// inferType computes expression's type well enough to answer `:type` and
+// to populate the registry for a `let`. It isn't a full type checker -
+// only the handful of expression kinds a REPL line is likely to contain.
+func inferType(expression ast.Expression, registry *typechecker.Registry) (types.Type, error) {
+	switch expression := expression.(type) {
+	case *ast.IntegerLiteral:
+		return types.Int, nil
+	case *ast.FloatLiteral:
+		return types.Float, nil
+	case *ast.StringLiteral:
+		return types.String, nil
+	case *ast.BooleanLiteral:
+		return types.Bool, nil
+	case *ast.Identifier:
+		entry := registry.Lookup(expression.Value)
+		if entry == nil {
+			return nil, fmt.Errorf("undefined identifier %s", expression.Value)
+		}
+		return entry.Type, nil
+	case *ast.UnaryExpression:
+		return inferType(expression.Operand, registry)
+	case *ast.BinaryExpression:
+		return inferBinaryType(expression, registry)
+	case *ast.CallExpression:
+		callee, ok := expression.Function.(*ast.Identifier)
+		if !ok {
+			return nil, fmt.Errorf("cannot infer the type of a call through a non-identifier callee")
+		}
+		entry := registry.Lookup(callee.Value)
+		if entry == nil {
+			return nil, fmt.Errorf("undefined function %s", callee.Value)
+		}
+		functionType, ok := entry.Type.(*types.FunctionType)
+		if !ok {
+			return nil, fmt.Errorf("%s is not callable", callee.Value)
+		}
+		return functionType.ReturnType, nil
+	default:
+		return nil, fmt.Errorf("cannot infer the type of a %T", expression)
+	}
+}
+
+// comparisonOperators yield bool regardless of their operand types.
+var comparisonOperators = map[string]bool{
+	"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
+	"&&": true, "||": true,
+}
+
+func inferBinaryType(binary *ast.BinaryExpression, registry *typechecker.Registry) (types.Type, error) {
+	if comparisonOperators[binary.Operator] {
+		return types.Bool, nil
+	}
+
+	leftType, err := inferType(binary.Left, registry)
+	if err != nil {
+		return nil, err
+	}
+	rightType, err := inferType(binary.Right, registry)
+	if err != nil {
+		return nil, err
+	}
+	if !leftType.Equals(rightType) {
+		return nil, fmt.Errorf("mismatched operand types %s and %s", leftType.String(), rightType.String())
+	}
+	return leftType, nil
+}
+
+// parseSource runs source through the lexer and parser in one shot, the
+// same two steps compiler/main.go's CompileFile performs, reporting any
+// lex/parse errors through errorCollector.Display rather than returning
+// them, since that's how the rest of the toolchain surfaces them.
+func parseSource(source, fileName string) (*ast.Program, bool) {
+	errorCollector := errors.New(source, fileName)
+	lexerInstance := lexer.New(source, fileName, errorCollector)
+	if errorCollector.HasErrors() {
+		errorCollector.Display()
+		return nil, false
+	}
+
+	parserInstance := parser.New(lexerInstance, errorCollector)
+	program := parserInstance.ParseProgram()
+	if errorCollector.HasErrors() {
+		errorCollector.Display()
+		return nil, false
+	}
+
+	return program, true
+}
+
+// lower runs program through name resolution and NIR lowering/opt/ssa,
+// mirroring compiler/main.go's CompileFile steps 2.5 through 3.6.
+func lower(program *ast.Program) (*nir.Module, bool) {
+	errorCollector := errors.New(program.String(), "<repl>")
+
+	resolver := sema.NewResolver(errorCollector)
+	resolution := resolver.Resolve(program)
+	if errorCollector.HasErrors() {
+		errorCollector.Display()
+		return nil, false
+	}
+
+	lowerer := nir.NewLowerer(errorCollector)
+	lowerer.SetResolution(resolution)
+	nirModule := lowerer.Lower(program)
+	if errorCollector.HasErrors() {
+		errorCollector.Display()
+		return nil, false
+	}
+	if !nirModule.IsComplete() {
+		fmt.Println("generated NIR module is incomplete")
+		return nil, false
+	}
+
+	opt.Run(nirModule, 1)
+	ssa.Run(nirModule)
+	opt.Run(nirModule, 1)
+
+	return nirModule, true
+}
+
+// compileIR lowers program all the way to LLVM IR text, for the `:ir`
+// meta-command - it doesn't JIT it, so it has no side effects to replay.
+func compileIR(program *ast.Program) (string, bool) {
+	nirModule, ok := lower(program)
+	if !ok {
+		return "", false
+	}
+
+	errorCollector := errors.New(program.String(), "<repl>")
+	generator := llvm.NewGenerator(errorCollector)
+	defer generator.Dispose()
+
+	llvmIR, err := generator.Generate(nirModule)
+	if errorCollector.HasErrors() {
+		errorCollector.Display()
+		return "", false
+	}
+	if err != nil {
+		fmt.Println("codegen failed:", err)
+		return "", false
+	}
+
+	return llvmIR, true
+}
+
+// run lowers program to LLVM IR and JITs it, printing whatever its
+// main prints and reporting a non-zero exit code.
+func run(program *ast.Program) {
+	nirModule, ok := lower(program)
+	if !ok {
+		return
+	}
+
+	errorCollector := errors.New(program.String(), "<repl>")
+	generator := llvm.NewGenerator(errorCollector)
+	defer generator.Dispose()
+
+	_, err := generator.Generate(nirModule)
+	if errorCollector.HasErrors() {
+		errorCollector.Display()
+		return
+	}
+	if err != nil {
+		fmt.Println("codegen failed:", err)
+		return
+	}
+
+	engine, err := jit.New(generator.Module())
+	if err != nil {
+		fmt.Println("jit setup failed:", err)
+		return
+	}
+	defer engine.Dispose()
+
+	exitCode, err := engine.RunMain()
+	if err != nil {
+		fmt.Println("run failed:", err)
+		return
+	}
+	if exitCode != 0 {
+		fmt.Printf("[exit code %d]\n", exitCode)
+	}
+}
+
+// handleMeta dispatches a leading-colon meta-command, reporting whether
+// input was one (so the caller knows not to also treat it as source).
+func handleMeta(input string, s *session) bool {
+	command, argument, _ := strings.Cut(input, " ")
+	argument = strings.TrimSpace(argument)
+
+	switch command {
+	case ":reset":
+		s.reset()
+		fmt.Println("session reset")
+	case ":ast":
+		fmt.Println(s.program().String())
+	case ":ir":
+		if llvmIR, ok := compileIR(s.program()); ok {
+			fmt.Println(llvmIR)
+		}
+	case ":type":
+		program, ok := parseSource(fmt.Sprintf("let __navi_repl_type_probe = %s\n", argument), "<repl>")
+		if !ok {
+			return true
+		}
+		letStatement, ok := program.Statements[0].(*ast.LetStatement)
+		if !ok {
+			fmt.Println("could not parse expression")
+			return true
+		}
+		inferredType, err := inferType(letStatement.Value, s.registry)
+		if err != nil {
+			fmt.Println(err)
+			return true
+		}
+		fmt.Println(inferredType.String())
+	case ":load":
+		loadFile(argument, s)
+	default:
+		fmt.Printf("unknown command %s (known: :ast, :ir, :type <expr>, :reset, :load <file>)\n", command)
+	}
+
+	return true
+}
+
+// loadFile parses path as a full Naviary source file and folds every
+// top-level statement into the session, the same as typing them in one
+// at a time, then runs the resulting program.
+func loadFile(path string, s *session) {
+	if path == "" {
+		fmt.Println(":load requires a file path")
+		return
+	}
+
+	sourceBytes, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	program, ok := parseSource(string(sourceBytes), path)
+	if !ok {
+		return
+	}
+
+	for _, statement := range program.Statements {
+		s.addStatement(statement)
+	}
+
+	run(s.program())
+}
+
+// pendingDelimiters counts source's net unclosed {, (, and [ so the
+// prompt loop knows to keep reading a multi-line statement (a function
+// or while body, typically) instead of handing an incomplete parse to
+// the parser. It tracks string literals just well enough that a brace
+// inside one doesn't throw off the count.
+func pendingDelimiters(source string) int {
+	depth := 0
+	inString := false
+	escaped := false
+
+	for _, r := range source {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch r {
+		case '"':
+			inString = true
+		case '{', '(', '[':
+			depth++
+		case '}', ')', ']':
+			depth--
+		}
+	}
+
+	return depth
+}
+
+func main() {
+	fmt.Println("Naviary REPL - :ast, :ir, :type <expr>, :reset, :load <file>, Ctrl-D to quit")
+
+	s := newSession()
+	scanner := bufio.NewScanner(os.Stdin)
+
+	var buffer strings.Builder
+	prompt := "navi> "
+
+	for {
+		fmt.Print(prompt)
+		if !scanner.Scan() {
+			fmt.Println()
+			return
+		}
+
+		buffer.WriteString(scanner.Text())
+		buffer.WriteString("\n")
+
+		if pendingDelimiters(buffer.String()) > 0 {
+			prompt = "...   "
+			continue
+		}
+
+		input := strings.TrimSpace(buffer.String())
+		buffer.Reset()
+		prompt = "navi> "
+
+		if input == "" {
+			continue
+		}
+
+		if strings.HasPrefix(input, ":") {
+			handleMeta(input, s)
+			continue
+		}
+
+		program, ok := parseSource(input, "<repl>")
+		if !ok {
+			continue
+		}
+
+		for _, statement := range program.Statements {
+			s.addStatement(statement)
+		}
+
+		run(s.program())
+	}
+}
@@ -0,0 +1,129 @@
+// Package abi describes how Naviary function signatures map onto a target's
+// calling convention: which registers carry parameters and results, and how
+// the overflow spills onto the stack.
+package abi
+
+import (
+	"compiler/codegen"
+	"compiler/types"
+)
+
+// ABIConfig describes the register pools and stack layout rules of a
+// register-based calling convention.
+type ABIConfig struct {
+	// Name identifies the convention, e.g. "arm64-darwin".
+	Name string
+
+	// IntegerParameterRegisters are consulted in order for each integer-like
+	// (int/bool/pointer) parameter, before spilling to the stack.
+	IntegerParameterRegisters []codegen.Register
+
+	// FloatParameterRegisters are consulted in order for each float
+	// parameter. Naviary currently lowers float to the integer file, so this
+	// is empty until a dedicated float register class exists.
+	FloatParameterRegisters []codegen.Register
+
+	// ResultRegisters are consulted in order for each returned value.
+	ResultRegisters []codegen.Register
+
+	// StackAlignment is the required alignment, in bytes, of the overflow
+	// area used once the register pools are exhausted.
+	StackAlignment int64
+}
+
+// ARM64DarwinABI is the register-based convention used by DarwinARM64Emitter:
+// x0-x7 for integer parameters, x0-x1 for results, 16-byte aligned stack.
+var ARM64DarwinABI = ABIConfig{
+	Name: "arm64-darwin",
+	IntegerParameterRegisters: []codegen.Register{
+		codegen.Register0, codegen.Register1, codegen.Register2, codegen.Register3,
+		codegen.Register4, codegen.Register5, codegen.Register6, codegen.Register7,
+	},
+	FloatParameterRegisters: nil,
+	ResultRegisters:         []codegen.Register{codegen.Register0, codegen.Register1},
+	StackAlignment:          16,
+}
+
+// ParamLocation describes where a single parameter or result value lives:
+// either in a register, or at [fp, +Offset] on the stack.
+type ParamLocation struct {
+	InRegister bool
+	Register   codegen.Register
+	Offset     int64 // valid when !InRegister
+}
+
+// ABIParamResultInfo maps each parameter and result of a function signature
+// to its concrete location under a given ABIConfig.
+type ABIParamResultInfo struct {
+	Parameters []ParamLocation
+	Results    []ParamLocation
+	// IndirectReturn is true when the results don't fit in ResultRegisters
+	// and must instead be written through a hidden pointer argument.
+	IndirectReturn bool
+}
+
+// Signature is the minimal description ABIAnalyze needs: the types of a
+// function's parameters and results, independent of any AST/NIR node.
+type Signature struct {
+	Parameters []types.Type
+	Results    []types.Type
+}
+
+// ABIAnalyze assigns registers (and, on overflow, stack slots) to every
+// parameter and result of sig according to config.
+func ABIAnalyze(config ABIConfig, sig Signature) ABIParamResultInfo {
+	info := ABIParamResultInfo{
+		Parameters: make([]ParamLocation, len(sig.Parameters)),
+		Results:    make([]ParamLocation, len(sig.Results)),
+	}
+
+	var stackOffset int64
+	intRegisterIndex := 0
+
+	for i := range sig.Parameters {
+		if intRegisterIndex < len(config.IntegerParameterRegisters) {
+			info.Parameters[i] = ParamLocation{
+				InRegister: true,
+				Register:   config.IntegerParameterRegisters[intRegisterIndex],
+			}
+			intRegisterIndex++
+			continue
+		}
+
+		stackOffset = alignTo(stackOffset, config.StackAlignment)
+		info.Parameters[i] = ParamLocation{InRegister: false, Offset: stackOffset}
+		stackOffset += 8
+	}
+
+	if len(sig.Results) > len(config.ResultRegisters) {
+		info.IndirectReturn = true
+	}
+
+	for i := range sig.Results {
+		if i < len(config.ResultRegisters) {
+			info.Results[i] = ParamLocation{
+				InRegister: true,
+				Register:   config.ResultRegisters[i],
+			}
+			continue
+		}
+
+		// Results that don't fit are written through the indirect return
+		// buffer pointer rather than the stack overflow area.
+		info.Results[i] = ParamLocation{InRegister: false, Offset: int64(i) * 8}
+	}
+
+	return info
+}
+
+// alignTo rounds offset up to the next multiple of alignment.
+func alignTo(offset int64, alignment int64) int64 {
+	if alignment == 0 {
+		return offset
+	}
+	remainder := offset % alignment
+	if remainder == 0 {
+		return offset
+	}
+	return offset + (alignment - remainder)
+}
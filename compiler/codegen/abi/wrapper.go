@@ -0,0 +1,26 @@
+package abi
+
+import "compiler/codegen"
+
+// ExternBinding describes a Naviary function exposed under a different
+// external symbol, e.g. a C function reached through the macOS underscore
+// convention (applied by the emitter's MangleSymbol in emitCall).
+type ExternBinding struct {
+	NaviaryName  string
+	ExternSymbol string
+	Config       ABIConfig
+}
+
+// GenerateWrapper builds a small thunk that forwards arguments from
+// Naviary's calling convention to binding.ExternSymbol and returns straight
+// through, so the rest of the backend never has to special-case extern
+// calls. The wrapper is a label plus a branch; argument registers already
+// line up because both sides share the same ABIConfig.
+func GenerateWrapper(binding ExternBinding) []codegen.Instruction {
+	return []codegen.Instruction{
+		codegen.MakeGlobal(binding.NaviaryName),
+		codegen.MakeLabel(binding.NaviaryName),
+		codegen.CallFunction(binding.ExternSymbol),
+		codegen.ReturnValue(codegen.Register0),
+	}
+}
@@ -0,0 +1,91 @@
+package codegen
+
+// allocatorTarget is implemented by emitters the linear-scan allocator
+// (see Allocate) knows how to place spills for - specifically, anything
+// that can reserve extra stack space on demand. DarwinARM64Emitter is
+// the only one today; other targets keep generating -O1 code with the
+// naive -O0 strategy (see CodeGenerator.allocateVirtual) until they grow
+// the same hook.
+type allocatorTarget interface {
+	InstructionEmitter
+	AdjustStack(bytes int64) (entry string, exit string)
+}
+
+// supportsAllocation reports whether generator's emitter can back the
+// register allocator.
+func (generator *CodeGenerator) supportsAllocation() bool {
+	_, ok := generator.emitter.(allocatorTarget)
+	return ok
+}
+
+// allocateVirtual returns the destination operand for a value-producing
+// expression: a fresh VirtualRegister under -O1 on a target the
+// allocator supports, left for allocateFunction to place; otherwise
+// Register0, today's -O0 strategy of reusing a single scratch register
+// (see generateBinaryExpression for why that strategy breaks on nested
+// expressions).
+func (generator *CodeGenerator) allocateVirtual() Operand {
+	if generator.optLevel == 0 || !generator.supportsAllocation() {
+		return Register0
+	}
+	v := VirtualRegister(generator.nextVirtual)
+	generator.nextVirtual++
+	return v
+}
+
+// allocateFunction runs Allocate over the instructions generated for one
+// function since bodyStart, lowering every virtual register the body
+// introduced onto the emitter's scratch range, then widens the frame
+// the prologue already reserved by however much spilling needed.
+func (generator *CodeGenerator) allocateFunction(bodyStart int) {
+	allocator, ok := generator.emitter.(allocatorTarget)
+	if !ok {
+		return
+	}
+
+	const numPhysical = 6 // x9-x14; x15 (base+numPhysical) is Allocate's own spill scratch
+	rewritten, frameSize := Allocate(generator.instructions[bodyStart:], generator.nextVirtual, numPhysical, Register8)
+	generator.instructions = append(generator.instructions[:bodyStart], rewritten...)
+
+	if frameSize == 0 {
+		return
+	}
+
+	entry, exit := allocator.AdjustStack(frameSize)
+	generator.insertFrameAdjust(bodyStart, entry, exit)
+}
+
+// insertFrameAdjust splices entry right after the prologue (bodyStart)
+// and exit immediately before every occurrence of the function's
+// epilogue: each return statement re-emits it, plus the implicit
+// fallthrough exit (see emitPopAndReturn) - so there can be more than
+// one. Positions are found by matching the epilogue's first emitted
+// line, then filled in back-to-front so earlier insertions don't shift
+// indices still to be used.
+func (generator *CodeGenerator) insertFrameAdjust(bodyStart int, entry, exit string) {
+	epilogueLines := generator.emitter.EmitEpilogue()
+	if len(epilogueLines) == 0 {
+		return
+	}
+	epilogueMarker := epilogueLines[0]
+
+	var epiloguePositions []int
+	for i := bodyStart; i < len(generator.instructions); i++ {
+		inst := generator.instructions[i]
+		if inst.Operation == Raw && inst.Comment == epilogueMarker {
+			epiloguePositions = append(epiloguePositions, i)
+		}
+	}
+
+	for i := len(epiloguePositions) - 1; i >= 0; i-- {
+		generator.insertInstructionAt(epiloguePositions[i], MakeRaw(exit))
+	}
+	generator.insertInstructionAt(bodyStart, MakeRaw(entry))
+}
+
+// insertInstructionAt splices instruction into the generator's stream
+// just before index.
+func (generator *CodeGenerator) insertInstructionAt(index int, instruction Instruction) {
+	generator.instructions = append(generator.instructions[:index:index],
+		append([]Instruction{instruction}, generator.instructions[index:]...)...)
+}
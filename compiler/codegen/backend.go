@@ -0,0 +1,12 @@
+package codegen
+
+import "compiler/nir"
+
+// Backend is implemented by code generators that consume the optimized,
+// SSA-form NIR produced by the nir/opt and nir/ssa passes, as opposed to
+// the AST-walking generators in this package (ErlangGenerator, CGenerator,
+// the ARM64/AMD64 assembly CodeGenerator). codegen/llvm.Generator is the
+// first and, for now, only implementation.
+type Backend interface {
+	Generate(module *nir.Module) (string, error)
+}
@@ -0,0 +1,92 @@
+// Package bitvec implements a fixed-universe bitmap packed into 64-bit
+// words, used by codegen's liveness analysis to represent live-in/live-out
+// sets over virtual register indices without a map's per-entry overhead.
+package bitvec
+
+const wordBits = 64
+
+// BV is a bitmap over the index range [0, size).
+type BV struct {
+	words []uint64
+	size  int
+}
+
+// New returns a BV with every bit clear, capable of holding indices
+// [0, size).
+func New(size int) *BV {
+	return &BV{
+		words: make([]uint64, (size+wordBits-1)/wordBits),
+		size:  size,
+	}
+}
+
+func (bv *BV) Set(i int) {
+	bv.words[i/wordBits] |= 1 << uint(i%wordBits)
+}
+
+func (bv *BV) Clear(i int) {
+	bv.words[i/wordBits] &^= 1 << uint(i%wordBits)
+}
+
+func (bv *BV) Has(i int) bool {
+	return bv.words[i/wordBits]&(1<<uint(i%wordBits)) != 0
+}
+
+// Union ORs other into bv in place and reports whether bv changed, so
+// dataflow fixed-point loops can tell when to stop.
+func (bv *BV) Union(other *BV) bool {
+	changed := false
+	for i, word := range other.words {
+		merged := bv.words[i] | word
+		if merged != bv.words[i] {
+			bv.words[i] = merged
+			changed = true
+		}
+	}
+	return changed
+}
+
+// Intersect returns a new BV holding bv AND other.
+func (bv *BV) Intersect(other *BV) *BV {
+	result := New(bv.size)
+	for i := range result.words {
+		result.words[i] = bv.words[i] & other.words[i]
+	}
+	return result
+}
+
+func (bv *BV) Equal(other *BV) bool {
+	for i, word := range bv.words {
+		if word != other.words[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns an independent copy of bv.
+func (bv *BV) Clone() *BV {
+	clone := New(bv.size)
+	copy(clone.words, bv.words)
+	return clone
+}
+
+// Iter calls fn once for every set bit, in ascending order.
+func (bv *BV) Iter(fn func(i int)) {
+	for wordIndex, word := range bv.words {
+		for word != 0 {
+			bit := trailingZeros64(word)
+			fn(wordIndex*wordBits + bit)
+			word &= word - 1 // clear the lowest set bit
+		}
+	}
+}
+
+func trailingZeros64(word uint64) int {
+	count := 0
+	for word&1 == 0 {
+		word >>= 1
+		count++
+	}
+	return count
+}
@@ -8,6 +8,11 @@ import (
 type CGenerator struct {
 	emitter        *Emitter
 	errorCollector *errors.ErrorCollector
+
+	// needsStringConcat is set once generateExpression emits a call to
+	// naviary_strcat, so Generate knows to declare it (and pull in
+	// string.h) without scanning the program up front.
+	needsStringConcat bool
 }
 
 func NewCGenerator(errorCollector *errors.ErrorCollector) *CGenerator {
@@ -17,22 +22,49 @@ func NewCGenerator(errorCollector *errors.ErrorCollector) *CGenerator {
 	}
 }
 
+// Generate emits the body first so EmitHeaders can see whether any
+// expression needed a runtime helper like naviary_strcat, then prepends
+// the headers to the body's output.
 func (generator *CGenerator) Generate(program *ast.Program) string {
-	generator.EmitHeaders()
-
 	for _, statement := range program.Statements {
 		generator.generateStatement(statement)
 	}
 
-	return generator.emitter.GetOutput()
+	headers := NewEmitter()
+	generator.EmitHeaders(headers)
+
+	return headers.GetOutput() + generator.emitter.GetOutput()
 }
 
-func (generator *CGenerator) EmitHeaders() {
+func (generator *CGenerator) EmitHeaders(headers *Emitter) {
 	// TODO: dynamic header include
-	generator.emitter.EmitLine("#include <stdio.h>")
-	generator.emitter.EmitNewLine()
-	generator.emitter.EmitLine("extern void print(int value);")
-	generator.emitter.EmitNewLine()
+	headers.EmitLine("#include <stdio.h>")
+	headers.EmitLine("#include <stdint.h>")
+	if generator.needsStringConcat {
+		headers.EmitLine("#include <string.h>")
+		headers.EmitLine("#include <stdlib.h>")
+	}
+	headers.EmitNewLine()
+	headers.EmitLine("extern void print(int value);")
+	if generator.needsStringConcat {
+		generator.emitStringConcatHelper(headers)
+	}
+	headers.EmitNewLine()
+}
+
+// emitStringConcatHelper emits naviary_strcat, the C backend's
+// counterpart to the LLVM backend's naviary_string_concat runtime call:
+// both exist so `+` on two strings works without the language having
+// string values with a managed length or a GC to free the result.
+func (generator *CGenerator) emitStringConcatHelper(headers *Emitter) {
+	headers.EmitLine("static char *naviary_strcat(const char *left, const char *right) {")
+	headers.IncreaseIndent()
+	headers.EmitLine("char *result = malloc(strlen(left) + strlen(right) + 1);")
+	headers.EmitLine("strcpy(result, left);")
+	headers.EmitLine("strcat(result, right);")
+	headers.EmitLine("return result;")
+	headers.DecreaseIndent()
+	headers.EmitLine("}")
 }
 
 func (generator *CGenerator) generateStatement(statement ast.Statement) {
@@ -90,8 +122,8 @@ func (generator *CGenerator) generateReturnStatement(returnStmt *ast.ReturnState
 }
 
 func (generator *CGenerator) generateLet(let *ast.LetStatement) {
-	// TODO: type is only int for now
-	generator.emitter.Emit("int ")
+	generator.emitter.Emit(cTypeName(let.TypeAnnotation))
+	generator.emitter.Emit(" ")
 
 	generator.emitter.Emit(let.Name.Value)
 
@@ -102,6 +134,43 @@ func (generator *CGenerator) generateLet(let *ast.LetStatement) {
 	generator.emitter.Emit(";")
 }
 
+// cTypeName maps a let statement's type annotation to a C type name,
+// defaulting to "int" when there's no annotation (the language's unsized
+// int is 64-bit, but int is kept here to match generateFunction's
+// still-untyped "int" return type).
+func cTypeName(typeAnnotation *ast.TypeAnnotation) string {
+	if typeAnnotation == nil {
+		return "int"
+	}
+
+	switch typeAnnotation.Value {
+	case "i8":
+		return "int8_t"
+	case "i16":
+		return "int16_t"
+	case "i32":
+		return "int32_t"
+	case "i64":
+		return "int64_t"
+	case "u8":
+		return "uint8_t"
+	case "u16":
+		return "uint16_t"
+	case "u32":
+		return "uint32_t"
+	case "u64":
+		return "uint64_t"
+	case "f32":
+		return "float"
+	case "f64", "float":
+		return "double"
+	case "bool":
+		return "int"
+	default:
+		return "int"
+	}
+}
+
 func (generator *CGenerator) generateExpression(expr ast.Expression) {
 	switch expression := expr.(type) {
 	case *ast.IntegerLiteral:
@@ -109,6 +178,16 @@ func (generator *CGenerator) generateExpression(expr ast.Expression) {
 	case *ast.Identifier:
 		generator.emitter.Emit(expression.Value)
 	case *ast.BinaryExpression:
+		if expression.Operator == "+" && isStringLiteral(expression.Left) && isStringLiteral(expression.Right) {
+			generator.needsStringConcat = true
+			generator.emitter.Emit("naviary_strcat(")
+			generator.generateExpression(expression.Left)
+			generator.emitter.Emit(", ")
+			generator.generateExpression(expression.Right)
+			generator.emitter.Emit(")")
+			break
+		}
+
 		generator.generateExpression(expression.Left)
 		generator.emitter.Emit(" ")
 		generator.emitter.Emit(expression.Operator)
@@ -133,6 +212,13 @@ func (generator *CGenerator) generateExpression(expr ast.Expression) {
 	}
 }
 
+// isStringLiteral reports whether expr is a string literal, the only
+// case the C backend can tell is a string without a type checker's help.
+func isStringLiteral(expr ast.Expression) bool {
+	_, ok := expr.(*ast.StringLiteral)
+	return ok
+}
+
 func (generator *CGenerator) generateExpressionStatement(exprStmt *ast.ExpressionStatement) {
 	generator.generateExpression(exprStmt.Expression)
 	generator.emitter.Emit(";")
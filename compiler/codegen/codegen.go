@@ -6,21 +6,37 @@ import (
 	"naviary/compiler/ast"
 )
 
+// loopLabels is the (break, continue) target pair for one enclosing loop.
+type loopLabels struct {
+	breakLabel    string
+	continueLabel string
+}
+
 // CodeGenerator generates assembly code from AST
 type CodeGenerator struct {
 	instructions    []Instruction          // Generated instructions
-	emitter         Emitter                // Platform-specific emitter
+	emitter         InstructionEmitter     // Platform-specific emitter
 	currentFunction *ast.FunctionStatement // Current function being compiled
 	labelCounter    int                    // For unique label generation
+	loopStack       []loopLabels           // Innermost-last stack of enclosing loops' break/continue labels
+	stringLiterals  []string               // Interned string constants, indexed by their ".Lstr_N" label
+	optLevel        int                    // 0: naive Register0/Register1 strategy, 1: virtual registers + Allocate
+	nextVirtual     int                    // Next VirtualRegister id to hand out, reset per function
 }
 
-// New creates a new code generator
-func New(emitter Emitter) *CodeGenerator {
+// New creates a new code generator targeting emitter's platform; see
+// NewEmitterForTarget to pick one from a target triple. optLevel selects
+// the expression codegen strategy: 0 keeps the naive Register0/Register1
+// juggling (see generateBinaryExpression), 1 builds virtual registers
+// and runs Allocate over them, on targets that support it (see
+// allocateVirtual).
+func New(emitter InstructionEmitter, optLevel int) *CodeGenerator {
 	return &CodeGenerator{
 		instructions:    []Instruction{},
 		emitter:         emitter,
 		currentFunction: nil,
 		labelCounter:    0,
+		optLevel:        optLevel,
 	}
 }
 
@@ -71,7 +87,9 @@ func (generator *CodeGenerator) NewLabel() string {
 	return label
 }
 
-// GenerateAssembly converts all instructions to assembly string
+// GenerateAssembly converts all instructions to assembly string, followed
+// by a __cstring rodata section holding every string literal the
+// instructions reference by label (see internString).
 func (generator *CodeGenerator) GenerateAssembly() string {
 	var buffer bytes.Buffer
 
@@ -80,16 +98,41 @@ func (generator *CodeGenerator) GenerateAssembly() string {
 	buffer.WriteString(".align 2\n")
 	buffer.WriteString("\n")
 
-	// Convert each instruction
+	// Convert each instruction. Raw instructions are already-rendered
+	// assembly text (see MakeRaw) and bypass the emitter entirely; every
+	// other instruction goes through the target's EmitInstruction.
 	for _, instruction := range generator.instructions {
-		line := generator.emitter.EmitInstruction(instruction)
+		var line string
+		if instruction.Operation == Raw {
+			line = instruction.Comment
+		} else {
+			line = generator.emitter.EmitInstruction(instruction)
+		}
 		buffer.WriteString(line)
 		buffer.WriteString("\n")
 	}
 
+	if len(generator.stringLiterals) > 0 {
+		buffer.WriteString("\n")
+		buffer.WriteString(".section __TEXT,__cstring\n")
+		for index, value := range generator.stringLiterals {
+			buffer.WriteString(fmt.Sprintf(".Lstr_%d:\n", index))
+			buffer.WriteString(fmt.Sprintf("    .asciz %q\n", value))
+		}
+	}
+
 	return buffer.String()
 }
 
+// internString records value as a string constant to be emitted in the
+// __cstring section, returning the unique label generateStringLiteral
+// can load its address from.
+func (generator *CodeGenerator) internString(value string) string {
+	label := fmt.Sprintf(".Lstr_%d", len(generator.stringLiterals))
+	generator.stringLiterals = append(generator.stringLiterals, value)
+	return label
+}
+
 // Generate compiles the AST to assembly
 func (generator *CodeGenerator) Generate(program *ast.Program) {
 	// Process each statement in the program
@@ -105,6 +148,14 @@ func (generator *CodeGenerator) generateStatement(statement ast.Statement) {
 		generator.generateFunction(stmt)
 	case *ast.ExpressionStatement:
 		generator.generateExpression(stmt.Expression)
+	case *ast.IfStatement:
+		generator.generateIf(stmt)
+	case *ast.WhileStatement:
+		generator.generateWhile(stmt)
+	case *ast.ReturnStatement:
+		generator.generateReturn(stmt)
+	case *ast.BreakStatement:
+		generator.generateBreak()
 	default:
 		generator.EmitComment(fmt.Sprintf("TODO: %T", stmt))
 	}
@@ -116,19 +167,26 @@ func (generator *CodeGenerator) generateFunction(function *ast.FunctionStatement
 	generator.currentFunction = function
 	defer func() { generator.currentFunction = nil }()
 
+	mangledName := generator.emitter.MangleSymbol(function.Name.Value)
+
 	// Declare main function as global
 	if function.Name.Value == "main" {
-		generator.EmitGlobal("_main")
+		generator.EmitGlobal(mangledName)
 	}
 
-	// Function label (with macOS prefix)
-	generator.EmitLabel("_" + function.Name.Value)
+	// Function label
+	generator.EmitLabel(mangledName)
 
 	// Function prologue
 	generator.EmitComment("Function prologue")
 	generator.generatePrologue()
 
-	// Function body
+	// Function body. nextVirtual is per-function: allocateFunction runs
+	// Allocate once the whole body (and its epilogue copies) is down, so
+	// virtual register ids only need to be unique within one function.
+	bodyStart := len(generator.instructions)
+	generator.nextVirtual = 0
+
 	for _, statement := range function.Body.Statements {
 		generator.generateStatement(statement)
 	}
@@ -136,27 +194,23 @@ func (generator *CodeGenerator) generateFunction(function *ast.FunctionStatement
 	// Function epilogue
 	generator.EmitComment("Function epilogue")
 	generator.generateEpilogue()
+
+	if generator.optLevel >= 1 && generator.nextVirtual > 0 {
+		generator.allocateFunction(bodyStart)
+	}
 }
 
-// generatePrologue generates function entry code
+// generatePrologue generates function entry code by splicing in the
+// target's own entry sequence (see InstructionEmitter.EmitPrologue);
+// CodeGenerator has no ABI knowledge of its own.
 func (generator *CodeGenerator) generatePrologue() {
-	// Save frame pointer (x29) and link register (x30)
-	// stp x29, x30, [sp, #-16]!
-	generator.Emit(Instruction{
-		Operation: Store, // Special store pair (we'll handle in emitter)
-		Operands: []Operand{
-			FramePointer,
-			LinkRegister,
-			Memory{Base: StackPointer, Offset: -16},
-		},
-		Comment: "stp x29, x30, [sp, #-16]!",
-	})
-
-	// Set up new frame pointer
-	generator.EmitMoveRegister(FramePointer, StackPointer)
-}
-
-// generateEpilogue generates function exit code
+	for _, line := range generator.emitter.EmitPrologue() {
+		generator.Emit(MakeRaw(line))
+	}
+}
+
+// generateEpilogue generates function exit code: the implicit fallthrough
+// exit at the end of a function body.
 func (generator *CodeGenerator) generateEpilogue() {
 	// Return 0 for main function
 	if generator.currentFunction != nil &&
@@ -164,27 +218,112 @@ func (generator *CodeGenerator) generateEpilogue() {
 		generator.EmitMove(Register0, 0)
 	}
 
-	// Restore frame pointer and link register
-	// ldp x29, x30, [sp], #16
-	generator.Emit(Instruction{
-		Operation: Load, // Special load pair
-		Operands: []Operand{
-			FramePointer,
-			LinkRegister,
-			Memory{Base: StackPointer, Offset: 16},
-		},
-		Comment: "ldp x29, x30, [sp], #16",
-	})
+	generator.emitPopAndReturn()
+}
+
+// emitPopAndReturn emits the target's exit sequence, shared by a
+// function's implicit fallthrough exit (generateEpilogue) and every
+// explicit return statement inside it (generateReturn).
+func (generator *CodeGenerator) emitPopAndReturn() {
+	for _, line := range generator.emitter.EmitEpilogue() {
+		generator.Emit(MakeRaw(line))
+	}
+}
+
+// generateIf compiles an if/else statement: the condition is evaluated
+// into a register, compared against zero, and a conditional branch skips
+// straight to the alternative (or past the whole statement, if there is
+// no else) when it's false.
+func (generator *CodeGenerator) generateIf(statement *ast.IfStatement) {
+	elseLabel := generator.NewLabel()
+	endLabel := generator.NewLabel()
+
+	conditionReg := generator.generateExpression(statement.Condition)
+	generator.Emit(CompareImmediate(conditionReg, 0))
+	generator.Emit(BranchIfEqual(elseLabel))
+
+	for _, stmt := range statement.Consequence.Statements {
+		generator.generateStatement(stmt)
+	}
+	generator.Emit(BranchTo(endLabel))
+
+	generator.EmitLabel(elseLabel)
+	if statement.Alternative != nil {
+		for _, stmt := range statement.Alternative.Statements {
+			generator.generateStatement(stmt)
+		}
+	}
 
-	// Return to caller
-	generator.EmitReturn()
+	generator.EmitLabel(endLabel)
+}
+
+// generateWhile compiles a while loop: a head label re-tests the
+// condition on every iteration, branching to the end label once it's
+// false, with the body re-branching back to the head. The loop's
+// (break, continue) label pair is pushed onto loopStack for the duration
+// of the body so a nested generateBreak/generateContinue can target it.
+func (generator *CodeGenerator) generateWhile(statement *ast.WhileStatement) {
+	headLabel := generator.NewLabel()
+	endLabel := generator.NewLabel()
+
+	generator.loopStack = append(generator.loopStack, loopLabels{breakLabel: endLabel, continueLabel: headLabel})
+	defer func() { generator.loopStack = generator.loopStack[:len(generator.loopStack)-1] }()
+
+	generator.EmitLabel(headLabel)
+	conditionReg := generator.generateExpression(statement.Condition)
+	generator.Emit(CompareImmediate(conditionReg, 0))
+	generator.Emit(BranchIfEqual(endLabel))
+
+	for _, stmt := range statement.Body.Statements {
+		generator.generateStatement(stmt)
+	}
+	generator.Emit(BranchTo(headLabel))
+
+	generator.EmitLabel(endLabel)
+}
+
+// generateReturn compiles an explicit return statement: the return value
+// (if any) is evaluated into Register0, then the function epilogue's
+// pop/ret sequence is emitted directly rather than duplicated here.
+func (generator *CodeGenerator) generateReturn(statement *ast.ReturnStatement) {
+	if statement.ReturnValue != nil {
+		result := generator.generateExpression(statement.ReturnValue)
+		if result != Operand(Register0) {
+			generator.Emit(Instruction{Operation: Move, Operands: []Operand{Register0, result}})
+		}
+	}
+
+	generator.emitPopAndReturn()
+}
+
+// generateBreak compiles an ast.BreakStatement: a branch to the innermost
+// enclosing loop's break label.
+func (generator *CodeGenerator) generateBreak() {
+	if len(generator.loopStack) == 0 {
+		generator.EmitComment("ERROR: break outside of a loop")
+		return
+	}
+	generator.Emit(BranchTo(generator.loopStack[len(generator.loopStack)-1].breakLabel))
+}
+
+// generateContinue emits a branch to the innermost enclosing loop's
+// continue label. There is no continue statement in the AST yet, so
+// generateStatement has no case that calls it.
+func (generator *CodeGenerator) generateContinue() {
+	if len(generator.loopStack) == 0 {
+		generator.EmitComment("ERROR: continue outside of a loop")
+		return
+	}
+	generator.Emit(BranchTo(generator.loopStack[len(generator.loopStack)-1].continueLabel))
 }
 
 // generateExpression compiles an expression
-func (generator *CodeGenerator) generateExpression(expression ast.Expression) Register {
+func (generator *CodeGenerator) generateExpression(expression ast.Expression) Operand {
 	switch expr := expression.(type) {
 	case *ast.IntegerLiteral:
 		return generator.generateIntegerLiteral(expr)
+	case *ast.StringLiteral:
+		return generator.generateStringLiteral(expr)
 	case *ast.CallExpression:
 		return generator.generateCallExpression(expr)
 	case *ast.Identifier:
@@ -198,18 +337,27 @@ func (generator *CodeGenerator) generateExpression(expression ast.Expression) Re
 }
 
 // generateIntegerLiteral compiles an integer literal
-func (generator *CodeGenerator) generateIntegerLiteral(literal *ast.IntegerLiteral) Register {
+func (generator *CodeGenerator) generateIntegerLiteral(literal *ast.IntegerLiteral) Operand {
 	// Parse the integer value
 	value := int64(0)
 	fmt.Sscanf(literal.Value, "%d", &value)
 
-	// Move to register 0
-	generator.EmitMove(Register0, value)
-	return Register0
+	destination := generator.allocateVirtual()
+	generator.Emit(Instruction{Operation: Move, Operands: []Operand{destination, Immediate{value}}})
+	return destination
+}
+
+// generateStringLiteral compiles a string literal by interning its
+// contents into the __cstring rodata section and loading its address.
+func (generator *CodeGenerator) generateStringLiteral(literal *ast.StringLiteral) Operand {
+	label := generator.internString(literal.Value)
+	destination := generator.allocateVirtual()
+	generator.Emit(Instruction{Operation: LoadAddress, Operands: []Operand{destination, Label{label}}})
+	return destination
 }
 
 // generateCallExpression compiles a function call
-func (generator *CodeGenerator) generateCallExpression(call *ast.CallExpression) Register {
+func (generator *CodeGenerator) generateCallExpression(call *ast.CallExpression) Operand {
 	// Get function name
 	funcIdent, ok := call.Function.(*ast.Identifier)
 	if !ok {
@@ -217,40 +365,56 @@ func (generator *CodeGenerator) generateCallExpression(call *ast.CallExpression)
 		return Register0
 	}
 
-	// Evaluate arguments and put in argument registers
+	// Evaluate arguments and put in argument registers, per the target's
+	// own calling convention (see InstructionEmitter.ArgumentRegister).
 	argRegisters := []Register{}
 	for i, arg := range call.Arguments {
-		if i >= 4 {
-			// TODO: Handle more than 4 arguments (need stack)
-			generator.EmitComment("WARNING: only first 4 arguments supported")
+		argReg, ok := generator.emitter.ArgumentRegister(i)
+		if !ok {
+			generator.EmitComment("WARNING: ran out of argument registers (need stack-passed arguments)")
 			break
 		}
 
 		// Evaluate argument
-		resultReg := generator.generateExpression(arg)
+		result := generator.generateExpression(arg)
 
 		// Move to argument register if not already there
-		argReg := Register(i) // Register0, Register1, etc.
-		if resultReg != argReg {
-			generator.EmitMoveRegister(argReg, resultReg)
+		if result != Operand(argReg) {
+			generator.Emit(Instruction{Operation: Move, Operands: []Operand{argReg, result}})
 		}
 		argRegisters = append(argRegisters, argReg)
 	}
 
 	// Map built-in function names to runtime equivalents
-	functionName := generator.mapBuiltinFunction(funcIdent.Value)
+	functionName := generator.mapBuiltinFunction(funcIdent.Value, call.Arguments)
 
 	// Call the function
 	generator.EmitCall(functionName, argRegisters...)
 
-	// Result is in Register0
-	return Register0
+	// The result lands in Register0 per the ABI; copy it into a fresh
+	// virtual so the allocator is free to keep it anywhere for the rest
+	// of the expression tree (a no-op under -O0, which allocateVirtual
+	// hands back Register0 for anyway).
+	destination := generator.allocateVirtual()
+	if destination != Operand(Register0) {
+		generator.Emit(Instruction{Operation: Move, Operands: []Operand{destination, Register0}})
+	}
+	return destination
 }
 
-// mapBuiltinFunction maps built-in function names to their runtime equivalents
-func (generator *CodeGenerator) mapBuiltinFunction(name string) string {
+// mapBuiltinFunction maps built-in function names to their runtime
+// equivalents. print dispatches on its single argument's shape, since
+// this generator has no type system to consult (see generateIdentifier's
+// TODO): a string literal argument calls navi_print_string, anything
+// else falls back to the existing navi_print_int.
+func (generator *CodeGenerator) mapBuiltinFunction(name string, arguments []ast.Expression) string {
 	switch name {
 	case "print":
+		if len(arguments) == 1 {
+			if _, ok := arguments[0].(*ast.StringLiteral); ok {
+				return "navi_print_string"
+			}
+		}
 		return "navi_print_int"
 	default:
 		return name
@@ -258,38 +422,64 @@ func (generator *CodeGenerator) mapBuiltinFunction(name string) string {
 }
 
 // generateIdentifier compiles an identifier reference
-func (generator *CodeGenerator) generateIdentifier(ident *ast.Identifier) Register {
+func (generator *CodeGenerator) generateIdentifier(ident *ast.Identifier) Operand {
 	// TODO: Implement variable lookup
 	generator.EmitComment(fmt.Sprintf("TODO: load variable %s", ident.Value))
 	return Register0
 }
 
-// generateBinaryExpression compiles a binary operation
-func (generator *CodeGenerator) generateBinaryExpression(binary *ast.BinaryExpression) Register {
-	// Evaluate left side
-	leftReg := generator.generateExpression(binary.Left)
+// generateBinaryExpression compiles a binary operation. Under -O0 it
+// keeps the historic naive strategy: Register0 is the only scratch
+// register in play, so a left operand landing there has to be saved off
+// to Register1 before the right side clobbers it - which breaks for
+// anything deeper than one level of nesting, e.g. (a+b)+(c+d). Under -O1
+// (on a target Allocate supports) every subexpression gets its own
+// virtual register instead, so nothing aliases and the allocator sorts
+// out physical registers - and spills - once the whole function is down.
+func (generator *CodeGenerator) generateBinaryExpression(binary *ast.BinaryExpression) Operand {
+	left := generator.generateExpression(binary.Left)
+
+	if generator.optLevel == 0 || !generator.supportsAllocation() {
+		leftReg, ok := left.(Register)
+		if !ok {
+			generator.EmitComment(fmt.Sprintf("TODO: non-register operand %T in -O0 binary expression", left))
+			leftReg = Register0
+		}
+		if leftReg == Register0 {
+			generator.EmitMoveRegister(Register1, Register0)
+			leftReg = Register1
+		}
+
+		right := generator.generateExpression(binary.Right)
+		rightReg, ok := right.(Register)
+		if !ok {
+			generator.EmitComment(fmt.Sprintf("TODO: non-register operand %T in -O0 binary expression", right))
+			rightReg = Register0
+		}
 
-	// Save left result if needed (using a temp register)
-	if leftReg == Register0 {
-		generator.EmitMoveRegister(Register1, Register0)
-		leftReg = Register1
+		switch binary.Operator {
+		case "+":
+			generator.Emit(AddRegisters(Register0, leftReg, rightReg))
+		case "-":
+			generator.Emit(Instruction{Operation: Subtract, Operands: []Operand{Register0, leftReg, rightReg}})
+		default:
+			generator.EmitComment(fmt.Sprintf("TODO: operator %s", binary.Operator))
+		}
+
+		return Register0
 	}
 
-	// Evaluate right side
-	rightReg := generator.generateExpression(binary.Right)
+	right := generator.generateExpression(binary.Right)
+	destination := generator.allocateVirtual()
 
-	// Perform operation
 	switch binary.Operator {
 	case "+":
-		generator.Emit(AddRegisters(Register0, leftReg, rightReg))
+		generator.Emit(Instruction{Operation: Add, Operands: []Operand{destination, left, right}})
 	case "-":
-		generator.Emit(Instruction{
-			Operation: Subtract,
-			Operands:  []Operand{Register0, leftReg, rightReg},
-		})
+		generator.Emit(Instruction{Operation: Subtract, Operands: []Operand{destination, left, right}})
 	default:
 		generator.EmitComment(fmt.Sprintf("TODO: operator %s", binary.Operator))
 	}
 
-	return Register0
+	return destination
 }
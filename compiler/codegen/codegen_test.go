@@ -41,7 +41,7 @@ func TestGenerateSimpleMain(t *testing.T) {
 
 	// Generate assembly
 	emitter := NewDarwinARM64Emitter()
-	generator := New(emitter)
+	generator := New(emitter, 0)
 	generator.Generate(program)
 
 	assembly := generator.GenerateAssembly()
@@ -66,3 +66,280 @@ func TestGenerateSimpleMain(t *testing.T) {
 	// Print for manual inspection
 	t.Logf("Generated assembly:\n%s", assembly)
 }
+
+func TestGenerateIfElse(t *testing.T) {
+	// func main() { if 1 { print(10) } else { print(20) } }
+	program := &ast.Program{
+		Statements: []ast.Statement{
+			&ast.FunctionStatement{
+				Name:       &ast.Identifier{Value: "main"},
+				Parameters: []*ast.FunctionParameter{},
+				Body: &ast.BlockStatement{
+					Statements: []ast.Statement{
+						&ast.IfStatement{
+							Condition: &ast.IntegerLiteral{Value: "1"},
+							Consequence: &ast.BlockStatement{
+								Statements: []ast.Statement{
+									&ast.ExpressionStatement{
+										Expression: &ast.CallExpression{
+											Function:  &ast.Identifier{Value: "navi_print_int"},
+											Arguments: []ast.Expression{&ast.IntegerLiteral{Value: "10"}},
+										},
+									},
+								},
+							},
+							Alternative: &ast.BlockStatement{
+								Statements: []ast.Statement{
+									&ast.ExpressionStatement{
+										Expression: &ast.CallExpression{
+											Function:  &ast.Identifier{Value: "navi_print_int"},
+											Arguments: []ast.Expression{&ast.IntegerLiteral{Value: "20"}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	emitter := NewDarwinARM64Emitter()
+	generator := New(emitter, 0)
+	generator.Generate(program)
+
+	assembly := generator.GenerateAssembly()
+
+	if !strings.Contains(assembly, "cmp x0, #0") {
+		t.Errorf("Missing condition compare")
+	}
+	if !strings.Contains(assembly, "b.eq .L") {
+		t.Errorf("Missing conditional branch to the else label")
+	}
+	if strings.Count(assembly, "mov x0, #10") != 1 || strings.Count(assembly, "mov x0, #20") != 1 {
+		t.Errorf("Expected both branches of the if/else to be generated exactly once")
+	}
+
+	t.Logf("Generated assembly:\n%s", assembly)
+}
+
+func TestGenerateWhile(t *testing.T) {
+	// func main() { while 1 { print(1) } }
+	program := &ast.Program{
+		Statements: []ast.Statement{
+			&ast.FunctionStatement{
+				Name:       &ast.Identifier{Value: "main"},
+				Parameters: []*ast.FunctionParameter{},
+				Body: &ast.BlockStatement{
+					Statements: []ast.Statement{
+						&ast.WhileStatement{
+							Condition: &ast.IntegerLiteral{Value: "1"},
+							Body: &ast.BlockStatement{
+								Statements: []ast.Statement{
+									&ast.ExpressionStatement{
+										Expression: &ast.CallExpression{
+											Function:  &ast.Identifier{Value: "navi_print_int"},
+											Arguments: []ast.Expression{&ast.IntegerLiteral{Value: "1"}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	emitter := NewDarwinARM64Emitter()
+	generator := New(emitter, 0)
+	generator.Generate(program)
+
+	assembly := generator.GenerateAssembly()
+
+	if !strings.Contains(assembly, "cmp x0, #0") {
+		t.Errorf("Missing condition compare")
+	}
+	if !strings.Contains(assembly, "b.eq .L") {
+		t.Errorf("Missing conditional branch to the end label")
+	}
+	if !strings.Contains(assembly, "b .L") {
+		t.Errorf("Missing unconditional branch back to the head label")
+	}
+
+	t.Logf("Generated assembly:\n%s", assembly)
+}
+
+func TestGenerateReturnValue(t *testing.T) {
+	// func main() { return 7 }
+	program := &ast.Program{
+		Statements: []ast.Statement{
+			&ast.FunctionStatement{
+				Name:       &ast.Identifier{Value: "main"},
+				Parameters: []*ast.FunctionParameter{},
+				Body: &ast.BlockStatement{
+					Statements: []ast.Statement{
+						&ast.ReturnStatement{
+							ReturnValue: &ast.IntegerLiteral{Value: "7"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	emitter := NewDarwinARM64Emitter()
+	generator := New(emitter, 0)
+	generator.Generate(program)
+
+	assembly := generator.GenerateAssembly()
+
+	if !strings.Contains(assembly, "mov x0, #7") {
+		t.Errorf("Missing return value move")
+	}
+	// generateFunction always appends the implicit fallthrough epilogue
+	// after the body, whether or not it already returned explicitly, so
+	// the pop sequence shows up twice here: once from generateReturn and
+	// once from that trailing epilogue. Both must come from the shared
+	// emitPopAndReturn sequence rather than a second, hand-written copy.
+	if strings.Count(assembly, "ldp x29, x30, [sp], #16") != 2 {
+		t.Errorf("Expected the shared pop sequence from both the explicit return and the trailing epilogue")
+	}
+	if strings.Count(assembly, "ret") != 2 {
+		t.Errorf("Expected a ret for both the explicit return and the trailing epilogue")
+	}
+
+	t.Logf("Generated assembly:\n%s", assembly)
+}
+
+func TestGenerateStringLiteralPrint(t *testing.T) {
+	// func main() { print("hi") }
+	program := &ast.Program{
+		Statements: []ast.Statement{
+			&ast.FunctionStatement{
+				Name:       &ast.Identifier{Value: "main"},
+				Parameters: []*ast.FunctionParameter{},
+				Body: &ast.BlockStatement{
+					Statements: []ast.Statement{
+						&ast.ExpressionStatement{
+							Expression: &ast.CallExpression{
+								Function:  &ast.Identifier{Value: "print"},
+								Arguments: []ast.Expression{&ast.StringLiteral{Value: "hi"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	emitter := NewDarwinARM64Emitter()
+	generator := New(emitter, 0)
+	generator.Generate(program)
+
+	assembly := generator.GenerateAssembly()
+
+	if !strings.Contains(assembly, "adrp x0, .Lstr_0@PAGE") || !strings.Contains(assembly, "add x0, x0, .Lstr_0@PAGEOFF") {
+		t.Errorf("Missing adrp/add address load for the interned string")
+	}
+	if !strings.Contains(assembly, "bl _navi_print_string") {
+		t.Errorf("Expected print(\"...\") to call navi_print_string")
+	}
+	if !strings.Contains(assembly, ".section __TEXT,__cstring") || !strings.Contains(assembly, ".Lstr_0:") || !strings.Contains(assembly, `.asciz "hi"`) {
+		t.Errorf("Missing __cstring rodata entry for the interned string")
+	}
+
+	t.Logf("Generated assembly:\n%s", assembly)
+}
+
+// rightNestedSum builds term[0] + (term[1] + (term[2] + ... )), a shape
+// that keeps each left operand live across the whole of its right
+// subtree's evaluation - exactly the case generateBinaryExpression's
+// naive -O0 strategy can't handle (every left operand wants Register0
+// the moment it's produced, and only one can have it).
+func rightNestedSum(terms ...string) ast.Expression {
+	expr := ast.Expression(&ast.IntegerLiteral{Value: terms[len(terms)-1]})
+	for i := len(terms) - 2; i >= 0; i-- {
+		expr = &ast.BinaryExpression{
+			Left:     &ast.IntegerLiteral{Value: terms[i]},
+			Operator: "+",
+			Right:    expr,
+		}
+	}
+	return expr
+}
+
+func mainReturning(value ast.Expression) *ast.Program {
+	return &ast.Program{
+		Statements: []ast.Statement{
+			&ast.FunctionStatement{
+				Name:       &ast.Identifier{Value: "main"},
+				Parameters: []*ast.FunctionParameter{},
+				Body: &ast.BlockStatement{
+					Statements: []ast.Statement{
+						&ast.ReturnStatement{ReturnValue: value},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGenerateNestedBinaryExpressionO1AvoidsAliasing(t *testing.T) {
+	// func main() { return 1 + (2 + (3 + 4)) } - under -O0 this is the
+	// exact shape that breaks: evaluating "3 + 4" lands in Register0, so
+	// does "2 + (3+4)", clobbering the left operand "1" moved there
+	// first. -O1 gives every partial sum its own virtual register, so
+	// Allocate has to place "1" and "2" in distinct physical registers
+	// for the whole nested evaluation.
+	program := mainReturning(rightNestedSum("1", "2", "3", "4"))
+
+	emitter := NewDarwinARM64Emitter()
+	generator := New(emitter, 1)
+	generator.Generate(program)
+
+	assembly := generator.GenerateAssembly()
+
+	if strings.Count(assembly, "add ") != 3 {
+		t.Errorf("Expected exactly 3 add instructions for 3 nested +, got:\n%s", assembly)
+	}
+	// The -O0 strategy this is fixing always lands the first operand in
+	// Register0 and then has to rescue it into Register1 before the
+	// right side clobbers it; under -O1 every literal gets its own
+	// virtual register from Allocate's x9-x14 range, so neither telltale
+	// line should appear.
+	if strings.Contains(assembly, "mov x1, x0") {
+		t.Errorf("Fell back to the naive Register0/Register1 juggle, got:\n%s", assembly)
+	}
+	if strings.Contains(assembly, "mov x0, #1") {
+		t.Errorf("First literal landed in Register0 instead of an allocated virtual, got:\n%s", assembly)
+	}
+
+	t.Logf("Generated assembly:\n%s", assembly)
+}
+
+func TestGenerateNestedBinaryExpressionO1Spills(t *testing.T) {
+	// A right-nested chain of 9 terms keeps 9 values alive at once just
+	// before the innermost add (the 8 pending left operands and the
+	// innermost right one), more than the 6 registers (x9-x14) Allocate
+	// has to work with, so some of them must spill.
+	program := mainReturning(rightNestedSum("1", "2", "3", "4", "5", "6", "7", "8", "9"))
+
+	emitter := NewDarwinARM64Emitter()
+	generator := New(emitter, 1)
+	generator.Generate(program)
+
+	assembly := generator.GenerateAssembly()
+
+	subIndex := strings.Index(assembly, "sub sp, sp, #")
+	addIndex := strings.Index(assembly, "add sp, sp, #")
+	if subIndex == -1 || addIndex == -1 {
+		t.Fatalf("Expected a matching sub/add sp pair reserving spill stack space, got:\n%s", assembly)
+	}
+	if !strings.Contains(assembly, "spill") {
+		t.Errorf("Expected an annotated spill decision, got:\n%s", assembly)
+	}
+
+	t.Logf("Generated assembly:\n%s", assembly)
+}
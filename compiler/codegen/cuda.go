@@ -0,0 +1,183 @@
+package codegen
+
+import (
+	"compiler/ast"
+	"compiler/errors"
+)
+
+// CUDAGenerator emits CUDA C++ kernel source for every function declared
+// `kernel` in the AST. It mirrors OpenCLGenerator closely; the two differ
+// mainly in the qualifier syntax and thread-index intrinsics.
+type CUDAGenerator struct {
+	emitter        *Emitter
+	errorCollector *errors.ErrorCollector
+}
+
+func NewCUDAGenerator(errorCollector *errors.ErrorCollector) *CUDAGenerator {
+	return &CUDAGenerator{
+		emitter:        NewEmitter(),
+		errorCollector: errorCollector,
+	}
+}
+
+func (generator *CUDAGenerator) Generate(program *ast.Program) string {
+	for _, kernel := range collectKernelFunctions(program) {
+		generator.generateKernel(kernel)
+		generator.emitter.EmitNewLine()
+	}
+
+	return generator.emitter.GetOutput()
+}
+
+func (generator *CUDAGenerator) generateKernel(function *ast.FunctionStatement) {
+	generator.emitter.Emit("__global__ void %s(", function.Name.Value)
+
+	for i, param := range function.Parameters {
+		if i > 0 {
+			generator.emitter.Emit(", ")
+		}
+		generator.emitter.Emit("%s%s %s", memorySpaceQualifierCUDA(param.MemorySpace), cudaType(param.Type.Value), param.Name.Value)
+	}
+
+	generator.emitter.EmitLine(") {")
+	generator.emitter.IncreaseIndent()
+
+	for _, param := range function.Parameters {
+		// CUDA has no per-parameter address-space keyword for `shared`:
+		// shared memory is declared inside the kernel body instead, so a
+		// `shared` parameter stays a plain pointer and the body also gets a
+		// __shared__ variable of its element type, named <param>_shared.
+		// That variable is not initialized from the incoming argument.
+		if param.MemorySpace == "shared" {
+			generator.emitter.EmitLine("__shared__ %s %s_shared;", cudaScalarType(param.Type.Value), param.Name.Value)
+		}
+	}
+
+	for _, statement := range function.Body.Statements {
+		generator.generateStatement(statement)
+	}
+
+	generator.emitter.DecreaseIndent()
+	generator.emitter.EmitLine("}")
+}
+
+// memorySpaceQualifierCUDA maps a Naviary kernel parameter qualifier to a
+// CUDA pointer-parameter prefix. CUDA has no __global/__local keywords on
+// parameters: "global" pointers (device memory) and "private" (per-thread)
+// ones are both written as plain pointers, and "shared" is handled by
+// declaring a __shared__ local in the kernel body (see generateKernel).
+func memorySpaceQualifierCUDA(memorySpace string) string {
+	return ""
+}
+
+func cudaType(typeName string) string {
+	switch typeName {
+	case "int":
+		return "int*"
+	case "float", "float32":
+		return "float*"
+	case "float64", "double":
+		return "double*"
+	case "bool":
+		return "bool*"
+	default:
+		return typeName + "*"
+	}
+}
+
+func cudaScalarType(typeName string) string {
+	switch typeName {
+	case "float", "float32":
+		return "float"
+	case "float64", "double":
+		return "double"
+	case "bool":
+		return "bool"
+	case "":
+		return "int"
+	default:
+		return typeName
+	}
+}
+
+func (generator *CUDAGenerator) generateStatement(statement ast.Statement) {
+	switch stmt := statement.(type) {
+	case *ast.ReturnStatement:
+		generator.generateReturnStatement(stmt)
+	case *ast.LetStatement:
+		generator.generateLetStatement(stmt)
+	case *ast.ExpressionStatement:
+		generator.generateExpression(stmt.Expression)
+		generator.emitter.EmitLine(";")
+	default:
+		generator.errorCollector.Add(
+			errors.SyntaxError,
+			0, 0, 0,
+			"CUDAGenerator: unsupported statement type %T",
+			stmt,
+		)
+	}
+}
+
+func (generator *CUDAGenerator) generateReturnStatement(returnStmt *ast.ReturnStatement) {
+	generator.emitter.Emit("return")
+	if returnStmt.ReturnValue != nil {
+		generator.emitter.Emit(" ")
+		generator.generateExpression(returnStmt.ReturnValue)
+	}
+	generator.emitter.EmitLine(";")
+}
+
+func (generator *CUDAGenerator) generateLetStatement(let *ast.LetStatement) {
+	typeName := ""
+	if let.TypeAnnotation != nil {
+		typeName = let.TypeAnnotation.Value
+	}
+
+	generator.emitter.Emit("%s %s = ", cudaScalarType(typeName), let.Name.Value)
+	generator.generateExpression(let.Value)
+	generator.emitter.EmitLine(";")
+}
+
+func (generator *CUDAGenerator) generateExpression(expression ast.Expression) {
+	switch expr := expression.(type) {
+	case *ast.IntegerLiteral:
+		generator.emitter.Emit(expr.Value)
+	case *ast.FloatLiteral:
+		generator.emitter.Emit(expr.Value)
+	case *ast.Identifier:
+		generator.emitter.Emit(expr.Value)
+	case *ast.BinaryExpression:
+		generator.generateExpression(expr.Left)
+		generator.emitter.Emit(" %s ", expr.Operator)
+		generator.generateExpression(expr.Right)
+	case *ast.CallExpression:
+		generator.generateCallExpression(expr)
+	default:
+		generator.errorCollector.Add(
+			errors.SyntaxError,
+			0, 0, 0,
+			"CUDAGenerator: unsupported expression type %T",
+			expr,
+		)
+	}
+}
+
+func (generator *CUDAGenerator) generateCallExpression(call *ast.CallExpression) {
+	if identifier, ok := call.Function.(*ast.Identifier); ok {
+		if intrinsic, isIntrinsic := threadIndexIntrinsics[identifier.Value]; isIntrinsic {
+			generator.emitter.Emit(intrinsic.cuda)
+			return
+		}
+	}
+
+	generator.generateExpression(call.Function)
+	generator.emitter.Emit("(")
+	for i, argument := range call.Arguments {
+		if i > 0 {
+			generator.emitter.Emit(", ")
+		}
+		generator.generateExpression(argument)
+	}
+	generator.emitter.Emit(")")
+}
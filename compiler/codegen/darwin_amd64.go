@@ -0,0 +1,181 @@
+package codegen
+
+import "fmt"
+
+// DarwinAMD64Emitter emits assembly for macOS on x86-64 (SysV calling
+// convention with the Darwin underscore-prefixed symbol rule).
+type DarwinAMD64Emitter struct{}
+
+// NewDarwinAMD64Emitter creates a new emitter for macOS x86-64.
+func NewDarwinAMD64Emitter() *DarwinAMD64Emitter {
+	return &DarwinAMD64Emitter{}
+}
+
+// GetPlatformName returns the platform identifier
+func (emitter *DarwinAMD64Emitter) GetPlatformName() string {
+	return "darwin-amd64"
+}
+
+// MapRegister maps abstract register to an x86-64 register name
+func (emitter *DarwinAMD64Emitter) MapRegister(register Register) string {
+	switch register {
+	case Register0:
+		return "rax"
+	case Register1:
+		return "rdi"
+	case Register2:
+		return "rsi"
+	case Register3:
+		return "rdx"
+	case Register4:
+		return "rcx"
+	case Register5:
+		return "r8"
+	case Register6:
+		return "r9"
+	case StackPointer:
+		return "rsp"
+	case FramePointer:
+		return "rbp"
+	default:
+		panic("unknown register")
+	}
+}
+
+// MangleSymbol prefixes name with the underscore Mach-O requires for
+// every C-visible symbol.
+func (emitter *DarwinAMD64Emitter) MangleSymbol(name string) string {
+	return "_" + name
+}
+
+// ArgumentRegister follows the System V AMD64 ABI: the first six
+// arguments go in rdi, rsi, rdx, rcx, r8, r9 - Register1 through
+// Register6, since Register0 (rax) is reserved for the return value.
+func (emitter *DarwinAMD64Emitter) ArgumentRegister(index int) (Register, bool) {
+	if index < 0 || index > int(Register6)-1 {
+		return 0, false
+	}
+	return Register(index + 1), true
+}
+
+// EmitInstruction lowers an abstract instruction to x86-64 AT&T assembly
+func (emitter *DarwinAMD64Emitter) EmitInstruction(instruction Instruction) string {
+	switch instruction.Operation {
+	case Move:
+		return emitter.emitMove(instruction)
+	case Load:
+		return emitter.emitLoad(instruction)
+	case Store:
+		return emitter.emitStore(instruction)
+	case Add:
+		return emitter.emitAdd(instruction)
+	case Subtract:
+		return emitter.emitSubtract(instruction)
+	case Call:
+		return emitter.emitCall(instruction)
+	case Return:
+		return emitter.emitReturn(instruction)
+	case DefineLabel:
+		return emitter.emitDefineLabel(instruction)
+	case Global:
+		return emitter.emitGlobal(instruction)
+	case Comment:
+		return emitter.emitComment(instruction)
+	default:
+		return fmt.Sprintf("    # TODO: %v", instruction.Operation)
+	}
+}
+
+func (emitter *DarwinAMD64Emitter) emitDefineLabel(instruction Instruction) string {
+	label, ok := instruction.Operands[0].(Label)
+	if !ok {
+		panic("DefineLabel operand must be a Label")
+	}
+	return fmt.Sprintf("%s:", label.Name)
+}
+
+func (emitter *DarwinAMD64Emitter) emitGlobal(instruction Instruction) string {
+	label, ok := instruction.Operands[0].(Label)
+	if !ok {
+		panic("Global operand must be a Label")
+	}
+	return fmt.Sprintf("    .globl %s", label.Name)
+}
+
+func (emitter *DarwinAMD64Emitter) emitComment(instruction Instruction) string {
+	return fmt.Sprintf("    # %s", instruction.Comment)
+}
+
+func (emitter *DarwinAMD64Emitter) emitReturn(instruction Instruction) string {
+	return "    ret"
+}
+
+func (emitter *DarwinAMD64Emitter) emitCall(instruction Instruction) string {
+	label, ok := instruction.Operands[0].(Label)
+	if !ok {
+		panic("Call first operand must be a Label")
+	}
+
+	return fmt.Sprintf("    callq %s", emitter.MangleSymbol(label.Name))
+}
+
+func (emitter *DarwinAMD64Emitter) emitMove(instruction Instruction) string {
+	destReg, ok := instruction.Operands[0].(Register)
+	if !ok {
+		panic("Move destination must be a register")
+	}
+	destName := emitter.MapRegister(destReg)
+
+	switch src := instruction.Operands[1].(type) {
+	case Register:
+		return fmt.Sprintf("    movq %%%s, %%%s", emitter.MapRegister(src), destName)
+	case Immediate:
+		return fmt.Sprintf("    movq $%d, %%%s", src.Value, destName)
+	default:
+		panic(fmt.Sprintf("Invalid source type for Move: %T", src))
+	}
+}
+
+func (emitter *DarwinAMD64Emitter) emitAdd(instruction Instruction) string {
+	destReg := instruction.Operands[0].(Register)
+	src2Reg := instruction.Operands[2].(Register)
+
+	// x86-64 add is two-operand (dest += src); Operands[1] should already
+	// equal dest by the time it reaches the emitter.
+	return fmt.Sprintf("    addq %%%s, %%%s", emitter.MapRegister(src2Reg), emitter.MapRegister(destReg))
+}
+
+func (emitter *DarwinAMD64Emitter) emitSubtract(instruction Instruction) string {
+	destReg := instruction.Operands[0].(Register)
+	src2Reg := instruction.Operands[2].(Register)
+
+	return fmt.Sprintf("    subq %%%s, %%%s", emitter.MapRegister(src2Reg), emitter.MapRegister(destReg))
+}
+
+func (emitter *DarwinAMD64Emitter) emitLoad(instruction Instruction) string {
+	destReg := instruction.Operands[0].(Register)
+	memory := instruction.Operands[1].(Memory)
+	return fmt.Sprintf("    movq %d(%%%s), %%%s", memory.Offset, emitter.MapRegister(memory.Base), emitter.MapRegister(destReg))
+}
+
+func (emitter *DarwinAMD64Emitter) emitStore(instruction Instruction) string {
+	srcReg := instruction.Operands[0].(Register)
+	memory := instruction.Operands[1].(Memory)
+	return fmt.Sprintf("    movq %%%s, %d(%%%s)", emitter.MapRegister(srcReg), memory.Offset, emitter.MapRegister(memory.Base))
+}
+
+// EmitPrologue emits the x86-64 function entry sequence.
+func (emitter *DarwinAMD64Emitter) EmitPrologue() []string {
+	return []string{
+		"    pushq %rbp",
+		"    movq %rsp, %rbp",
+	}
+}
+
+// EmitEpilogue emits the x86-64 function exit sequence.
+func (emitter *DarwinAMD64Emitter) EmitEpilogue() []string {
+	return []string{
+		"    popq %rbp",
+		"    ret",
+	}
+}
@@ -15,6 +15,39 @@ func (emitter *DarwinARM64Emitter) GetPlatformName() string {
 	return "darwin-arm64"
 }
 
+// MangleSymbol prefixes name with the underscore Mach-O requires for
+// every C-visible symbol.
+func (emitter *DarwinARM64Emitter) MangleSymbol(name string) string {
+	return "_" + name
+}
+
+// EmitPrologue saves the frame pointer and link register (x29/x30) with a
+// pre-indexed store pair, then establishes the new frame pointer.
+func (emitter *DarwinARM64Emitter) EmitPrologue() []string {
+	return []string{
+		"    stp x29, x30, [sp, #-16]!",
+		"    mov x29, sp",
+	}
+}
+
+// EmitEpilogue restores x29/x30 with the matching post-indexed load pair
+// and returns to the link register.
+func (emitter *DarwinARM64Emitter) EmitEpilogue() []string {
+	return []string{
+		"    ldp x29, x30, [sp], #16",
+		"    ret",
+	}
+}
+
+// ArgumentRegister follows AAPCS64: the first eight arguments go in
+// x0-x7, the same registers Register0-Register7 already map to.
+func (emitter *DarwinARM64Emitter) ArgumentRegister(index int) (Register, bool) {
+	if index < 0 || index > int(Register7) {
+		return 0, false
+	}
+	return Register(index), true
+}
+
 // MapRegister maps abstract register to ARM64 register name
 func (emitter *DarwinARM64Emitter) MapRegister(register Register) string {
 	switch register {
@@ -26,18 +59,52 @@ func (emitter *DarwinARM64Emitter) MapRegister(register Register) string {
 		return "x2"
 	case Register3:
 		return "x3"
+	case Register4:
+		return "x4"
+	case Register5:
+		return "x5"
+	case Register6:
+		return "x6"
+	case Register7:
+		return "x7"
 	case StackPointer:
 		return "sp"
 	case FramePointer:
 		return "x29" // ARM64 frame pointer
 	case LinkRegister:
 		return "x30" // ARM64 link register
+	case Register8:
+		return "x9"
+	case Register9:
+		return "x10"
+	case Register10:
+		return "x11"
+	case Register11:
+		return "x12"
+	case Register12:
+		return "x13"
+	case Register13:
+		return "x14"
+	case Register14:
+		return "x15"
 	default:
 		// Should not happen if we defined all registers
 		panic("unknown register")
 	}
 }
 
+// AdjustStack reserves bytes of extra stack space below the frame
+// pointer for the register allocator's spill slots (see Allocate and
+// CodeGenerator.allocateFunction), returning the entry-side and
+// exit-side instruction text. bytes is assumed already 16-byte aligned;
+// a zero bytes is a no-op pair, since most functions spill nothing.
+func (emitter *DarwinARM64Emitter) AdjustStack(bytes int64) (entry string, exit string) {
+	if bytes == 0 {
+		return "", ""
+	}
+	return fmt.Sprintf("    sub sp, sp, #%d", bytes), fmt.Sprintf("    add sp, sp, #%d", bytes)
+}
+
 // EmitInstruction에 DefineLabel과 Comment 추가
 func (emitter *DarwinARM64Emitter) EmitInstruction(instruction Instruction) string {
 	switch instruction.Operation {
@@ -45,6 +112,8 @@ func (emitter *DarwinARM64Emitter) EmitInstruction(instruction Instruction) stri
 		return emitter.emitMove(instruction)
 	case Load:
 		return emitter.emitLoad(instruction)
+	case LoadAddress:
+		return emitter.emitLoadAddress(instruction)
 	case Store:
 		return emitter.emitStore(instruction)
 	case Add:
@@ -61,6 +130,12 @@ func (emitter *DarwinARM64Emitter) EmitInstruction(instruction Instruction) stri
 		return emitter.emitComment(instruction)
 	case Subtract:
 		return emitter.emitSubtract(instruction)
+	case Branch:
+		return emitter.emitBranch(instruction)
+	case BranchConditional:
+		return emitter.emitBranchConditional(instruction)
+	case Compare:
+		return emitter.emitCompare(instruction)
 	default:
 		return fmt.Sprintf("    # TODO: %v", instruction.Operation)
 	}
@@ -123,11 +198,8 @@ func (emitter *DarwinARM64Emitter) emitCall(instruction Instruction) string {
 		panic("Call first operand must be a Label")
 	}
 
-	// macOS requires underscore prefix for C functions
-	functionName := "_" + label.Name
-
 	// ARM64 uses 'bl' (Branch with Link) for function calls
-	return fmt.Sprintf("    bl %s", functionName)
+	return fmt.Sprintf("    bl %s", emitter.MangleSymbol(label.Name))
 }
 
 // emitAdd handles Add instruction
@@ -163,6 +235,59 @@ func (emitter *DarwinARM64Emitter) emitAdd(instruction Instruction) string {
 	return fmt.Sprintf("    add %s, %s, %s", destName, src1Name, src2Name)
 }
 
+// emitBranch handles Branch instruction
+func (emitter *DarwinARM64Emitter) emitBranch(instruction Instruction) string {
+	if len(instruction.Operands) != 1 {
+		panic("Branch requires exactly 1 operand")
+	}
+
+	label, ok := instruction.Operands[0].(Label)
+	if !ok {
+		panic("Branch operand must be a Label")
+	}
+
+	return fmt.Sprintf("    b %s", label.Name)
+}
+
+// emitBranchConditional handles BranchConditional instruction
+func (emitter *DarwinARM64Emitter) emitBranchConditional(instruction Instruction) string {
+	if len(instruction.Operands) != 1 {
+		panic("BranchConditional requires exactly 1 operand")
+	}
+	if instruction.Condition == "" {
+		panic("BranchConditional requires a Condition")
+	}
+
+	label, ok := instruction.Operands[0].(Label)
+	if !ok {
+		panic("BranchConditional operand must be a Label")
+	}
+
+	return fmt.Sprintf("    b.%s %s", instruction.Condition, label.Name)
+}
+
+// emitCompare handles Compare instruction
+func (emitter *DarwinARM64Emitter) emitCompare(instruction Instruction) string {
+	if len(instruction.Operands) != 2 {
+		panic("Compare requires exactly 2 operands")
+	}
+
+	leftReg, ok := instruction.Operands[0].(Register)
+	if !ok {
+		panic("Compare left operand must be a register")
+	}
+	leftName := emitter.MapRegister(leftReg)
+
+	switch right := instruction.Operands[1].(type) {
+	case Register:
+		return fmt.Sprintf("    cmp %s, %s", leftName, emitter.MapRegister(right))
+	case Immediate:
+		return fmt.Sprintf("    cmp %s, #%d", leftName, right.Value)
+	default:
+		panic(fmt.Sprintf("Invalid right operand type for Compare: %T", right))
+	}
+}
+
 // emitMove handles Move instruction
 func (emitter *DarwinARM64Emitter) emitMove(instruction Instruction) string {
 	if len(instruction.Operands) != 2 {
@@ -197,11 +322,6 @@ func (emitter *DarwinARM64Emitter) emitMove(instruction Instruction) string {
 
 // emitLoad handles Load instruction
 func (emitter *DarwinARM64Emitter) emitLoad(instruction Instruction) string {
-	// Check for load pair (special case for epilogue)
-	if instruction.Comment == "ldp x29, x30, [sp], #16" {
-		return "    ldp x29, x30, [sp], #16"
-	}
-
 	// Normal load requires exactly 2 operands
 	if len(instruction.Operands) != 2 {
 		panic("Load requires exactly 2 operands")
@@ -231,13 +351,30 @@ func (emitter *DarwinARM64Emitter) emitLoad(instruction Instruction) string {
 	return fmt.Sprintf("    ldr %s, [%s, #%d]", destName, baseName, memory.Offset)
 }
 
-// emitStore handles Store instruction
-func (emitter *DarwinARM64Emitter) emitStore(instruction Instruction) string {
-	// Check for store pair (special case for prologue)
-	if instruction.Comment == "stp x29, x30, [sp, #-16]!" {
-		return "    stp x29, x30, [sp, #-16]!"
+// emitLoadAddress handles LoadAddress, materializing a label's address
+// with the standard ARM64 adrp/add page-relative pair (the same pattern
+// clang emits for a string constant's address on Darwin).
+func (emitter *DarwinARM64Emitter) emitLoadAddress(instruction Instruction) string {
+	if len(instruction.Operands) != 2 {
+		panic("LoadAddress requires exactly 2 operands")
 	}
 
+	destReg, ok := instruction.Operands[0].(Register)
+	if !ok {
+		panic("LoadAddress destination must be a register")
+	}
+	label, ok := instruction.Operands[1].(Label)
+	if !ok {
+		panic("LoadAddress source must be a Label")
+	}
+
+	destName := emitter.MapRegister(destReg)
+	return fmt.Sprintf("    adrp %s, %s@PAGE\n    add %s, %s, %s@PAGEOFF",
+		destName, label.Name, destName, destName, label.Name)
+}
+
+// emitStore handles Store instruction
+func (emitter *DarwinARM64Emitter) emitStore(instruction Instruction) string {
 	// Normal store requires exactly 2 operands
 	if len(instruction.Operands) != 2 {
 		panic("Store requires exactly 2 operands")
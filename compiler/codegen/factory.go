@@ -0,0 +1,53 @@
+package codegen
+
+import "fmt"
+
+// InstructionEmitter is satisfied by every platform-specific emitter
+// (DarwinARM64Emitter, DarwinAMD64Emitter, LinuxAMD64Emitter,
+// LinuxRISCV64Emitter, ...). It captures the method set the compiler
+// driver needs to lower abstract Instructions to real assembly,
+// independent of target architecture: instruction rendering and register
+// naming (EmitInstruction, MapRegister), plus the ABI/ISA specifics that
+// CodeGenerator itself must stay agnostic to - the function entry/exit
+// sequence (EmitPrologue, EmitEpilogue), which abstract Register holds
+// the call argument at a given position (ArgumentRegister), and how a
+// Naviary symbol name is spelled in the object file (MangleSymbol).
+type InstructionEmitter interface {
+	GetPlatformName() string
+	MapRegister(register Register) string
+	EmitInstruction(instruction Instruction) string
+
+	// EmitPrologue/EmitEpilogue return the target's function entry/exit
+	// sequence as already-rendered assembly lines, for CodeGenerator to
+	// splice into the instruction stream via MakeRaw.
+	EmitPrologue() []string
+	EmitEpilogue() []string
+
+	// ArgumentRegister returns the abstract Register holding the
+	// index'th call argument (0-indexed) under this target's calling
+	// convention, or ok == false if the convention has run out of
+	// argument registers.
+	ArgumentRegister(index int) (register Register, ok bool)
+
+	// MangleSymbol returns name as it must appear in emitted assembly,
+	// e.g. with the leading underscore Mach-O requires.
+	MangleSymbol(name string) string
+}
+
+// NewEmitterForTarget selects the right InstructionEmitter for a target
+// triple such as "arm64-apple-darwin", "x86_64-apple-darwin",
+// "x86_64-linux-gnu", or "riscv64-linux-gnu".
+func NewEmitterForTarget(triple string) (InstructionEmitter, error) {
+	switch triple {
+	case "arm64-apple-darwin":
+		return NewDarwinARM64Emitter(), nil
+	case "x86_64-apple-darwin":
+		return NewDarwinAMD64Emitter(), nil
+	case "x86_64-linux-gnu":
+		return NewLinuxAMD64Emitter(), nil
+	case "riscv64-linux-gnu":
+		return NewLinuxRISCV64Emitter(), nil
+	default:
+		return nil, fmt.Errorf("unsupported target triple: %s", triple)
+	}
+}
@@ -0,0 +1,77 @@
+package codegen
+
+import (
+	"compiler/ast"
+	"strings"
+)
+
+// Extension identifies an optional GPU floating-point capability a kernel
+// may require. It is computed from the declared types of a kernel
+// function's parameters and return type, and drives pragma emission in
+// OpenCLGenerator (CUDAGenerator needs no equivalent pragma: nvcc enables
+// double precision unconditionally).
+type Extension int
+
+const (
+	Float32 Extension = iota
+	Float64
+)
+
+func (extension Extension) String() string {
+	switch extension {
+	case Float64:
+		return "float64"
+	default:
+		return "float32"
+	}
+}
+
+// extensionForTypeName maps a source-level type annotation to the
+// floating-point extension it requires. Types other than float32/float64
+// (int, string, bool, ...) don't need an extension at all, so callers
+// should only consult this for types that look like a float annotation.
+func extensionForTypeName(name string) Extension {
+	if name == "float64" || name == "double" {
+		return Float64
+	}
+	return Float32
+}
+
+// requiresFloat64 reports whether any parameter or return type of a
+// kernel function is double precision, which OpenCL needs an explicit
+// pragma to unlock.
+func requiresFloat64(function *ast.FunctionStatement) bool {
+	for _, param := range function.Parameters {
+		if isFloatTypeName(param.Type.Value) && extensionForTypeName(param.Type.Value) == Float64 {
+			return true
+		}
+	}
+
+	if function.ReturnType != nil && isFloatTypeName(function.ReturnType.Value) &&
+		extensionForTypeName(function.ReturnType.Value) == Float64 {
+		return true
+	}
+
+	for _, returnType := range function.ReturnTypes {
+		if isFloatTypeName(returnType.Value) && extensionForTypeName(returnType.Value) == Float64 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isFloatTypeName(name string) bool {
+	return strings.HasPrefix(name, "float") || name == "double"
+}
+
+// threadIndexIntrinsics maps the Naviary GPU builtin names a kernel body
+// may call to their target-specific lowering. Both generators only
+// support the single-dimension (x) form for now.
+var threadIndexIntrinsics = map[string]struct {
+	openCL string
+	cuda   string
+}{
+	"thread_id": {openCL: "get_global_id(0)", cuda: "(blockIdx.x * blockDim.x + threadIdx.x)"},
+	"block_id":  {openCL: "get_group_id(0)", cuda: "blockIdx.x"},
+}
@@ -5,22 +5,27 @@ type OperationCode int
 
 const (
 	// Data movement operations
-	Move  OperationCode = iota // Move data between registers or load immediate
-	Load                       // Load from memory to register
-	Store                      // Store from register to memory
+	Move        OperationCode = iota // Move data between registers or load immediate
+	Load                             // Load from memory to register
+	Store                            // Store from register to memory
+	LoadAddress                      // Load a label's address (e.g. a string constant) into a register
 
 	// Arithmetic operations
 	Add      // Addition
 	Subtract // Subtraction
 
 	// Control flow
-	Call   // Function call
-	Return // Return from function
+	Call              // Function call
+	Return            // Return from function
+	Branch            // Unconditional jump to a label
+	BranchConditional // Jump to a label when Instruction.Condition holds, as set by a preceding Compare
+	Compare           // Compare two operands, setting flags for a following BranchConditional
 
 	// Meta operations
 	DefineLabel // Define a label for jumps
 	Global      // Global symbol declaration (.globl)
 	Comment     // Comment in assembly
+	Raw         // Pre-rendered assembly text, emitted verbatim (see MakeRaw)
 )
 
 // Operand represents an instruction operand (what the operation works on)
@@ -37,13 +42,38 @@ const (
 	Register1
 	Register2
 	Register3
+	Register4
+	Register5
+	Register6
+	Register7
 
 	// Special purpose registers
 	StackPointer
 	FramePointer
 	LinkRegister
+
+	// Register8 through Register14 are the scratch range the linear-scan
+	// allocator (see Allocate) hands out on ARM64: x9-x14 for allocated
+	// live values, x15 reserved as Allocate's own spill scratch register.
+	// No other target drives the allocator yet, so only
+	// DarwinARM64Emitter's MapRegister knows these.
+	Register8
+	Register9
+	Register10
+	Register11
+	Register12
+	Register13
+	Register14
 )
 
+// VirtualRegister is an unbounded register name assigned during
+// instruction selection, to be lowered to a physical Register by the
+// allocator (see Allocate) before any Emitter sees it. By convention an
+// Instruction's operand 0 is its destination when it produces a value,
+// and every other operand is a source - that's the shape the liveness
+// pass scans for.
+type VirtualRegister int
+
 type Immediate struct {
 	Value int64
 }
@@ -53,20 +83,53 @@ type Label struct {
 	Name string
 }
 
+// MemorySpace identifies which GPU address space a Memory operand lives
+// in. The CPU emitters only ever address the stack and ignore it, and
+// OpenCLGenerator and CUDAGenerator do not read it either: they take a
+// kernel parameter's qualifier from the AST parameter's MemorySpace
+// string.
+type MemorySpace int
+
+const (
+	// Private is the default: per-thread local storage, e.g. a normal
+	// stack slot. It is the zero value so existing Memory{} literals
+	// that predate memory spaces keep their original meaning.
+	Private MemorySpace = iota
+	Global             // device-wide memory, visible to every thread
+	Local              // OpenCL work-group-local memory (CUDA: shared)
+	Shared             // explicitly shared memory within a work-group/block
+)
+
+func (space MemorySpace) String() string {
+	switch space {
+	case Global:
+		return "global"
+	case Local:
+		return "local"
+	case Shared:
+		return "shared"
+	default:
+		return "private"
+	}
+}
+
 // Memory represents a memory address
 type Memory struct {
-	Base   Register // base register (usually stack pointer)
-	Offset int64    // offset from base register
+	Base        Register    // base register (usually stack pointer)
+	Offset      int64       // offset from base register
+	MemorySpace MemorySpace // address space the operand lives in (GPU backends only)
 }
 
-func (r Register) operandMarker()  {}
-func (i Immediate) operandMarker() {}
-func (l Label) operandMarker()     {}
-func (m Memory) operandMarker()    {}
+func (r Register) operandMarker()        {}
+func (v VirtualRegister) operandMarker() {}
+func (i Immediate) operandMarker()       {}
+func (l Label) operandMarker()           {}
+func (m Memory) operandMarker()          {}
 
 type Instruction struct {
 	Operation OperationCode
 	Operands  []Operand // First operand is usually destination
+	Condition string    // ARM64 condition code (e.g. "eq"); only set for BranchConditional
 	Comment   string    // Optional comment for debugging
 }
 
@@ -128,6 +191,43 @@ func ReturnValue(value Register) Instruction {
 	}
 }
 
+// BranchTo creates an unconditional branch to the named label.
+func BranchTo(label string) Instruction {
+	return Instruction{
+		Operation: Branch,
+		Operands:  []Operand{Label{label}},
+	}
+}
+
+// BranchIfEqual creates a branch to the named label, taken when a
+// preceding Compare found its operands equal (ARM64 condition "eq").
+func BranchIfEqual(label string) Instruction {
+	return Instruction{
+		Operation: BranchConditional,
+		Operands:  []Operand{Label{label}},
+		Condition: "eq",
+	}
+}
+
+// CompareImmediate creates a comparison between an operand (a physical
+// or, pending allocation, virtual register) and an immediate value,
+// setting flags for a following BranchConditional.
+func CompareImmediate(operand Operand, value int64) Instruction {
+	return Instruction{
+		Operation: Compare,
+		Operands:  []Operand{operand, Immediate{value}},
+	}
+}
+
+// LoadAddressOf creates an instruction loading label's address into
+// destination (e.g. a string constant interned by CodeGenerator.internString).
+func LoadAddressOf(destination Register, label string) Instruction {
+	return Instruction{
+		Operation: LoadAddress,
+		Operands:  []Operand{destination, Label{label}},
+	}
+}
+
 // LoadFromMemory creates a load instruction
 func LoadFromMemory(destination Register, base Register, offset int64) Instruction {
 	return Instruction{
@@ -174,3 +274,15 @@ func MakeComment(text string) Instruction {
 		Comment:   text,
 	}
 }
+
+// MakeRaw wraps an already-rendered line of assembly (as returned by an
+// emitter's EmitPrologue/EmitEpilogue) so it flows through the generic
+// instruction stream unchanged, rather than back through EmitInstruction.
+// CodeGenerator.GenerateAssembly recognizes Raw and writes line as-is.
+func MakeRaw(line string) Instruction {
+	return Instruction{
+		Operation: Raw,
+		Operands:  []Operand{},
+		Comment:   line,
+	}
+}
@@ -0,0 +1,43 @@
+package codegen
+
+import "fmt"
+
+// LinuxAMD64Emitter emits assembly for Linux on x86-64 (SysV calling
+// convention). It differs from DarwinAMD64Emitter only in that Linux ELF
+// symbols aren't underscore-prefixed.
+type LinuxAMD64Emitter struct {
+	DarwinAMD64Emitter
+}
+
+// NewLinuxAMD64Emitter creates a new emitter for Linux x86-64.
+func NewLinuxAMD64Emitter() *LinuxAMD64Emitter {
+	return &LinuxAMD64Emitter{}
+}
+
+// GetPlatformName returns the platform identifier
+func (emitter *LinuxAMD64Emitter) GetPlatformName() string {
+	return "linux-amd64"
+}
+
+// MangleSymbol returns name unchanged: ELF doesn't underscore-prefix
+// symbols the way Mach-O does.
+func (emitter *LinuxAMD64Emitter) MangleSymbol(name string) string {
+	return name
+}
+
+// EmitInstruction lowers an abstract instruction to x86-64 AT&T assembly,
+// overriding only the Call case to drop the Darwin underscore prefix.
+func (emitter *LinuxAMD64Emitter) EmitInstruction(instruction Instruction) string {
+	if instruction.Operation == Call {
+		return emitter.emitCall(instruction)
+	}
+	return emitter.DarwinAMD64Emitter.EmitInstruction(instruction)
+}
+
+func (emitter *LinuxAMD64Emitter) emitCall(instruction Instruction) string {
+	label, ok := instruction.Operands[0].(Label)
+	if !ok {
+		panic("Call first operand must be a Label")
+	}
+	return fmt.Sprintf("    callq %s", emitter.MangleSymbol(label.Name))
+}
@@ -0,0 +1,187 @@
+package codegen
+
+import "fmt"
+
+// LinuxRISCV64Emitter emits assembly for Linux on RISC-V (RV64, the
+// standard integer calling convention).
+type LinuxRISCV64Emitter struct{}
+
+// NewLinuxRISCV64Emitter creates a new emitter for Linux RISC-V 64.
+func NewLinuxRISCV64Emitter() *LinuxRISCV64Emitter {
+	return &LinuxRISCV64Emitter{}
+}
+
+// GetPlatformName returns the platform identifier
+func (emitter *LinuxRISCV64Emitter) GetPlatformName() string {
+	return "linux-riscv64"
+}
+
+// MapRegister maps abstract register to an RV64 register name
+func (emitter *LinuxRISCV64Emitter) MapRegister(register Register) string {
+	switch register {
+	case Register0:
+		return "a0"
+	case Register1:
+		return "a1"
+	case Register2:
+		return "a2"
+	case Register3:
+		return "a3"
+	case Register4:
+		return "a4"
+	case Register5:
+		return "a5"
+	case Register6:
+		return "a6"
+	case Register7:
+		return "a7"
+	case StackPointer:
+		return "sp"
+	case FramePointer:
+		return "s0"
+	case LinkRegister:
+		return "ra"
+	default:
+		panic("unknown register")
+	}
+}
+
+// MangleSymbol returns name unchanged: ELF doesn't underscore-prefix
+// symbols the way Mach-O does.
+func (emitter *LinuxRISCV64Emitter) MangleSymbol(name string) string {
+	return name
+}
+
+// EmitPrologue allocates a 16-byte frame and saves the return address
+// and the old frame pointer, then establishes the new one.
+func (emitter *LinuxRISCV64Emitter) EmitPrologue() []string {
+	return []string{
+		"    addi sp, sp, -16",
+		"    sd ra, 8(sp)",
+		"    sd s0, 0(sp)",
+		"    mv s0, sp",
+	}
+}
+
+// EmitEpilogue restores ra/s0 and releases the frame before returning.
+func (emitter *LinuxRISCV64Emitter) EmitEpilogue() []string {
+	return []string{
+		"    ld ra, 8(sp)",
+		"    ld s0, 0(sp)",
+		"    addi sp, sp, 16",
+		"    ret",
+	}
+}
+
+// ArgumentRegister follows the RISC-V integer calling convention: the
+// first eight arguments go in a0-a7, the same registers Register0-
+// Register7 already map to.
+func (emitter *LinuxRISCV64Emitter) ArgumentRegister(index int) (Register, bool) {
+	if index < 0 || index > int(Register7) {
+		return 0, false
+	}
+	return Register(index), true
+}
+
+// EmitInstruction lowers an abstract instruction to RV64 assembly
+func (emitter *LinuxRISCV64Emitter) EmitInstruction(instruction Instruction) string {
+	switch instruction.Operation {
+	case Move:
+		return emitter.emitMove(instruction)
+	case Load:
+		return emitter.emitLoad(instruction)
+	case Store:
+		return emitter.emitStore(instruction)
+	case Add:
+		return emitter.emitAdd(instruction)
+	case Subtract:
+		return emitter.emitSubtract(instruction)
+	case Call:
+		return emitter.emitCall(instruction)
+	case Return:
+		return emitter.emitReturn(instruction)
+	case DefineLabel:
+		return emitter.emitDefineLabel(instruction)
+	case Global:
+		return emitter.emitGlobal(instruction)
+	case Comment:
+		return emitter.emitComment(instruction)
+	default:
+		return fmt.Sprintf("    # TODO: %v", instruction.Operation)
+	}
+}
+
+func (emitter *LinuxRISCV64Emitter) emitDefineLabel(instruction Instruction) string {
+	label, ok := instruction.Operands[0].(Label)
+	if !ok {
+		panic("DefineLabel operand must be a Label")
+	}
+	return fmt.Sprintf("%s:", label.Name)
+}
+
+func (emitter *LinuxRISCV64Emitter) emitGlobal(instruction Instruction) string {
+	label, ok := instruction.Operands[0].(Label)
+	if !ok {
+		panic("Global operand must be a Label")
+	}
+	return fmt.Sprintf("    .globl %s", label.Name)
+}
+
+func (emitter *LinuxRISCV64Emitter) emitComment(instruction Instruction) string {
+	return fmt.Sprintf("    # %s", instruction.Comment)
+}
+
+func (emitter *LinuxRISCV64Emitter) emitReturn(instruction Instruction) string {
+	return "    ret"
+}
+
+func (emitter *LinuxRISCV64Emitter) emitCall(instruction Instruction) string {
+	label, ok := instruction.Operands[0].(Label)
+	if !ok {
+		panic("Call first operand must be a Label")
+	}
+	return fmt.Sprintf("    call %s", emitter.MangleSymbol(label.Name))
+}
+
+func (emitter *LinuxRISCV64Emitter) emitMove(instruction Instruction) string {
+	destReg, ok := instruction.Operands[0].(Register)
+	if !ok {
+		panic("Move destination must be a register")
+	}
+	destName := emitter.MapRegister(destReg)
+
+	switch src := instruction.Operands[1].(type) {
+	case Register:
+		return fmt.Sprintf("    mv %s, %s", destName, emitter.MapRegister(src))
+	case Immediate:
+		return fmt.Sprintf("    li %s, %d", destName, src.Value)
+	default:
+		panic(fmt.Sprintf("Invalid source type for Move: %T", src))
+	}
+}
+
+func (emitter *LinuxRISCV64Emitter) emitAdd(instruction Instruction) string {
+	destReg := instruction.Operands[0].(Register)
+	src1Reg := instruction.Operands[1].(Register)
+	src2Reg := instruction.Operands[2].(Register)
+	return fmt.Sprintf("    add %s, %s, %s", emitter.MapRegister(destReg), emitter.MapRegister(src1Reg), emitter.MapRegister(src2Reg))
+}
+
+func (emitter *LinuxRISCV64Emitter) emitSubtract(instruction Instruction) string {
+	destReg := instruction.Operands[0].(Register)
+	src1Reg := instruction.Operands[1].(Register)
+	src2Reg := instruction.Operands[2].(Register)
+	return fmt.Sprintf("    sub %s, %s, %s", emitter.MapRegister(destReg), emitter.MapRegister(src1Reg), emitter.MapRegister(src2Reg))
+}
+
+func (emitter *LinuxRISCV64Emitter) emitLoad(instruction Instruction) string {
+	destReg := instruction.Operands[0].(Register)
+	memory := instruction.Operands[1].(Memory)
+	return fmt.Sprintf("    ld %s, %d(%s)", emitter.MapRegister(destReg), memory.Offset, emitter.MapRegister(memory.Base))
+}
+
+func (emitter *LinuxRISCV64Emitter) emitStore(instruction Instruction) string {
+	srcReg := instruction.Operands[0].(Register)
+	memory := instruction.Operands[1].(Memory)
+	return fmt.Sprintf("    sd %s, %d(%s)", emitter.MapRegister(srcReg), memory.Offset, emitter.MapRegister(memory.Base))
+}
@@ -0,0 +1,158 @@
+package codegen
+
+import "compiler/codegen/bitvec"
+
+// block is a maximal straight-line run of instructions between
+// DefineLabel markers. Control leaves a block by falling through to the
+// next one or through a Branch/BranchConditional inside it; see
+// blockSuccessors.
+type block struct {
+	instructions []Instruction
+	start        int // index of instructions[0] within the full instruction stream
+}
+
+func splitBlocks(instructions []Instruction) []*block {
+	var blocks []*block
+	current := &block{start: 0}
+
+	for i, inst := range instructions {
+		if inst.Operation == DefineLabel && len(current.instructions) > 0 {
+			blocks = append(blocks, current)
+			current = &block{start: i}
+		}
+		current.instructions = append(current.instructions, inst)
+	}
+	blocks = append(blocks, current)
+
+	return blocks
+}
+
+// blockSuccessors returns the blocks control can reach from
+// blocks[index]: the block defining each label a Branch or
+// BranchConditional in it targets, plus the next block unless the block
+// ends in an unconditional Branch.
+func blockSuccessors(blocks []*block, index int) []int {
+	var successors []int
+	fallsThrough := true
+	for _, inst := range blocks[index].instructions {
+		fallsThrough = inst.Operation != Branch
+		if inst.Operation == Branch || inst.Operation == BranchConditional {
+			for i, b := range blocks {
+				if first := b.instructions[0]; first.Operation == DefineLabel && first.Operands[0] == inst.Operands[0] {
+					successors = append(successors, i)
+				}
+			}
+		}
+	}
+	if fallsThrough && index+1 < len(blocks) {
+		successors = append(successors, index+1)
+	}
+	return successors
+}
+
+// useDef reports the virtual register inst defines (if any, via operand
+// 0) and the virtual registers it reads (every other operand).
+func useDef(inst Instruction) (def *int, uses []int) {
+	for i, operand := range inst.Operands {
+		vreg, ok := operand.(VirtualRegister)
+		if !ok {
+			continue
+		}
+		if i == 0 && definesResult(inst.Operation) {
+			id := int(vreg)
+			def = &id
+		} else {
+			uses = append(uses, int(vreg))
+		}
+	}
+	return def, uses
+}
+
+// definesResult reports whether operation's operand 0 is a destination
+// rather than another source (e.g. Store's operand 0 is the value being
+// written, not a destination register).
+func definesResult(operation OperationCode) bool {
+	switch operation {
+	case Move, Load, LoadAddress, Add, Subtract:
+		return true
+	default:
+		return false
+	}
+}
+
+// Liveness holds the live-in/live-out virtual-register sets computed for
+// every block of a function's instruction stream.
+type Liveness struct {
+	blocks  []*block
+	liveIn  []*bitvec.BV
+	liveOut []*bitvec.BV
+}
+
+// ComputeLiveness runs the standard live_in = use ∪ (live_out − def),
+// live_out = ∪ live_in(successors) dataflow to a fixed point over
+// instructions' blocks. numVirtual must be at least one past the
+// largest VirtualRegister index appearing in instructions.
+func ComputeLiveness(instructions []Instruction, numVirtual int) *Liveness {
+	blocks := splitBlocks(instructions)
+
+	liveness := &Liveness{
+		blocks:  blocks,
+		liveIn:  make([]*bitvec.BV, len(blocks)),
+		liveOut: make([]*bitvec.BV, len(blocks)),
+	}
+	for i := range blocks {
+		liveness.liveIn[i] = bitvec.New(numVirtual)
+		liveness.liveOut[i] = bitvec.New(numVirtual)
+	}
+
+	use, def := make([]*bitvec.BV, len(blocks)), make([]*bitvec.BV, len(blocks))
+	for i, b := range blocks {
+		use[i], def[i] = bitvec.New(numVirtual), bitvec.New(numVirtual)
+		// Walk the block backwards so a register used before being
+		// redefined later in the same block is still counted as a use.
+		for j := len(b.instructions) - 1; j >= 0; j-- {
+			blockDef, blockUses := useDef(b.instructions[j])
+			for _, v := range blockUses {
+				use[i].Set(v)
+			}
+			if blockDef != nil {
+				use[i].Clear(*blockDef)
+				def[i].Set(*blockDef)
+			}
+		}
+	}
+
+	for {
+		changed := false
+
+		for i := len(blocks) - 1; i >= 0; i-- {
+			for _, successor := range blockSuccessors(blocks, i) {
+				if liveness.liveOut[i].Union(liveness.liveIn[successor]) {
+					changed = true
+				}
+			}
+
+			// live_in = use ∪ (live_out − def): start from live_out,
+			// kill anything this block (re)defines, then reinstate the
+			// block's own upward-exposed uses.
+			newLiveIn := liveness.liveOut[i].Clone()
+			for v := 0; v < numVirtual; v++ {
+				if def[i].Has(v) {
+					newLiveIn.Clear(v)
+				}
+			}
+			newLiveIn.Union(use[i])
+
+			if !newLiveIn.Equal(liveness.liveIn[i]) {
+				liveness.liveIn[i] = newLiveIn
+				changed = true
+			}
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	return liveness
+}
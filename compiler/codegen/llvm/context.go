@@ -4,11 +4,18 @@ import "tinygo.org/x/go-llvm"
 
 type Context struct {
 	context llvm.Context
+
+	// namedStructTypes caches each user-defined struct's llvm.StructType
+	// by name, so every reference to e.g. struct Point converts to the
+	// same named LLVM type rather than a fresh structurally-equal one
+	// each time (see TypeConverter.convertStructType).
+	namedStructTypes map[string]llvm.Type
 }
 
 func NewContext() *Context {
 	return &Context{
-		context: llvm.NewContext(),
+		context:          llvm.NewContext(),
+		namedStructTypes: make(map[string]llvm.Type),
 	}
 }
 
@@ -19,3 +26,18 @@ func (ctx *Context) Dispose() {
 func (ctx *Context) GetRawContext() llvm.Context {
 	return ctx.context
 }
+
+// NamedStructType returns the cached llvm.StructType for name, or creates
+// and caches one (opaque, via StructCreateNamed) if this is the first
+// reference to it.
+func (ctx *Context) NamedStructType(name string) (llvm.Type, bool) {
+	structType, ok := ctx.namedStructTypes[name]
+	if ok {
+		return structType, true
+	}
+
+	structType = ctx.context.StructCreateNamed(name)
+	ctx.namedStructTypes[name] = structType
+
+	return structType, false
+}
@@ -0,0 +1,100 @@
+// Package debuginfo attaches DWARF debug metadata to the LLVM IR produced
+// by codegen/llvm, so a Naviary binary can be stepped through in lldb/gdb.
+package debuginfo
+
+import (
+	"compiler/nir"
+	"path/filepath"
+
+	"tinygo.org/x/go-llvm"
+)
+
+// Builder wraps an llvm.DIBuilder and the compile-unit-level metadata every
+// function and instruction needs to reference.
+type Builder struct {
+	module      llvm.Module
+	diBuilder   llvm.DIBuilder
+	file        llvm.Metadata
+	compileUnit llvm.Metadata
+}
+
+// New creates a Builder for sourcePath and emits the top-level !DIFile and
+// !DICompileUnit nodes. Call Finalize once every function has been
+// converted.
+func New(module llvm.Module, sourcePath string) *Builder {
+	diBuilder := llvm.NewDIBuilder(module)
+
+	file := diBuilder.CreateFile(filepath.Base(sourcePath), filepath.Dir(sourcePath))
+
+	compileUnit := diBuilder.CreateCompileUnit(llvm.DICompileUnit{
+		Language:  llvm.DW_LANG_C, // closest match until Naviary registers its own DWARF language code
+		File:      filepath.Base(sourcePath),
+		Dir:       filepath.Dir(sourcePath),
+		Producer:  "naviary",
+		Optimized: false,
+	})
+
+	return &Builder{
+		module:      module,
+		diBuilder:   diBuilder,
+		file:        file,
+		compileUnit: compileUnit,
+	}
+}
+
+// Finalize must be called after every function has been converted; it
+// resolves forward-declared debug metadata.
+func (builder *Builder) Finalize() {
+	builder.diBuilder.Finalize()
+}
+
+// CreateFunction emits a !DISubprogram for a Naviary function, anchored at
+// declLine (the 'func' keyword's source line).
+func (builder *Builder) CreateFunction(name string, declLine int, isLocalToUnit bool) llvm.Metadata {
+	subroutineType := builder.diBuilder.CreateSubroutineType(llvm.DISubroutineType{
+		File: builder.file,
+	})
+
+	return builder.diBuilder.CreateFunction(builder.file, llvm.DIFunction{
+		Name:         name,
+		LinkageName:  name,
+		File:         builder.file,
+		Line:         declLine,
+		Type:         subroutineType,
+		LocalToUnit:  isLocalToUnit,
+		IsDefinition: true,
+		ScopeLine:    declLine,
+	})
+}
+
+// Location builds a !DILocation for loc scoped to scope (the enclosing
+// !DISubprogram), suitable for llvmBuilder.SetCurrentDebugLocation.
+func (builder *Builder) Location(loc nir.SourceLoc, scope llvm.Metadata) llvm.Metadata {
+	return builder.diBuilder.CreateDebugLocation(loc.Line, loc.Column, scope, llvm.Metadata{})
+}
+
+// DeclareLocalVariable emits a !DILocalVariable plus an llvm.dbg.declare
+// call for a stack-allocated (Alloc) variable.
+func (builder *Builder) DeclareLocalVariable(
+	irBuilder llvm.Builder,
+	scope llvm.Metadata,
+	name string,
+	declLine int,
+	diType llvm.Metadata,
+	storage llvm.Value,
+) {
+	localVariable := builder.diBuilder.CreateAutoVariable(scope, llvm.DIAutoVariable{
+		Name: name,
+		File: builder.file,
+		Line: declLine,
+		Type: diType,
+	})
+
+	builder.diBuilder.InsertDeclareAtEnd(
+		storage,
+		localVariable,
+		builder.diBuilder.CreateExpression(nil),
+		builder.Location(nir.SourceLoc{Line: declLine}, scope),
+		irBuilder.GetInsertBlock(),
+	)
+}
@@ -1,7 +1,9 @@
 package llvm
 
 import (
+	"compiler/codegen/llvm/debuginfo"
 	llvmvalue "compiler/codegen/llvm/value"
+	"compiler/errors"
 	"compiler/nir"
 	nirinstruction "compiler/nir/instruction"
 	nirvalue "compiler/nir/value"
@@ -16,6 +18,11 @@ type FunctionConverter struct {
 	typeConverter        *TypeConverter
 	valueConverter       *llvmvalue.Converter
 	instructionConverter *InstructionConverter
+	errorCollector       *errors.ErrorCollector
+
+	// debugInfo is non-nil only when the module is being converted via
+	// ModuleConverter.ConvertWithDebugInfo.
+	debugInfo *debuginfo.Builder
 }
 
 func NewFunctionConverter(
@@ -23,6 +30,7 @@ func NewFunctionConverter(
 	module llvm.Module,
 	typeConverter *TypeConverter,
 	valueConverter *llvmvalue.Converter,
+	errorCollector *errors.ErrorCollector,
 ) *FunctionConverter {
 	return &FunctionConverter{
 		context:              context,
@@ -30,32 +38,60 @@ func NewFunctionConverter(
 		typeConverter:        typeConverter,
 		valueConverter:       valueConverter,
 		instructionConverter: nil,
+		errorCollector:       errorCollector,
+	}
+}
+
+// reportError records a structured diagnostic for err at line, so it's
+// displayed with the caret-underlined source snippet the lexer/parser
+// already produce instead of only as a bare "failed to ...: ..." string.
+// It's a no-op when no errorCollector was supplied (e.g. a caller that
+// only cares about the returned error).
+func (converter *FunctionConverter) reportError(line int, err error) {
+	if converter.errorCollector == nil {
+		return
 	}
+	converter.errorCollector.Add(errors.CodegenError, line, 0, 0, "%s", err)
 }
 
 func (converter *FunctionConverter) Convert(nirFunction *nir.Function) error {
-	parameterTypes, err := converter.convertParameterTypes(nirFunction.Parameters)
+	parameterTypes, err := converter.convertParameterTypes(nirFunction)
 	if err != nil {
+		converter.reportError(nirFunction.DeclLine(), err)
 		return fmt.Errorf("failed to convert parameter types: %w", err)
 	}
 
 	returnType, err := converter.typeConverter.Convert(nirFunction.ReturnType)
 	if err != nil {
+		converter.reportError(nirFunction.DeclLine(), err)
 		return fmt.Errorf("failed to convert return type: %w", err)
 	}
 
-	functionType := llvm.FunctionType(returnType, parameterTypes, false)
+	functionType := llvm.FunctionType(returnType, parameterTypes, nirFunction.IsVariadic)
 
 	llvmFunction := llvm.AddFunction(converter.module, nirFunction.Name, functionType)
 
+	// Extern functions are declarations only: no body or entry block, and
+	// nothing to register debug info or parameter allocas for.
+	if nirFunction.IsExtern {
+		return nil
+	}
+
+	var debugScope llvm.Metadata
+	if converter.debugInfo != nil {
+		debugScope = converter.debugInfo.CreateFunction(nirFunction.Name, nirFunction.DeclLine(), false)
+		llvmFunction.SetSubprogram(debugScope)
+	}
+
 	converter.valueConverter.Reset()
 
 	err = converter.registerParameters(nirFunction, llvmFunction)
 	if err != nil {
+		converter.reportError(nirFunction.DeclLine(), err)
 		return fmt.Errorf("failed to register parameters: %w", err)
 	}
 
-	err = converter.convertBasicBlocks(nirFunction, llvmFunction)
+	err = converter.convertBasicBlocks(nirFunction, llvmFunction, debugScope)
 	if err != nil {
 		return fmt.Errorf("failed to convert basic blocks: %w", err)
 	}
@@ -63,22 +99,33 @@ func (converter *FunctionConverter) Convert(nirFunction *nir.Function) error {
 	return nil
 }
 
-func (converter *FunctionConverter) convertParameterTypes(parameters []nir.Parameter) ([]llvm.Type, error) {
-	llvmTypes := make([]llvm.Type, len(parameters))
+// convertParameterTypes converts nirFunction's parameter types, prepending
+// a pointer to its receiver's class (see nir.Function.Receiver) as the
+// implicit first parameter ("this") for a method.
+func (converter *FunctionConverter) convertParameterTypes(nirFunction *nir.Function) ([]llvm.Type, error) {
+	llvmTypes := make([]llvm.Type, 0, len(nirFunction.Parameters)+1)
 
-	for i, param := range parameters {
+	if nirFunction.Receiver != nil {
+		receiverType, err := converter.typeConverter.Convert(nirFunction.Receiver.Type)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert receiver type: %w", err)
+		}
+		llvmTypes = append(llvmTypes, llvm.PointerType(receiverType, 0))
+	}
+
+	for i, param := range nirFunction.Parameters {
 		llvmType, err := converter.typeConverter.Convert(param.Type)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert parameter type %d: %w", i, err)
 		}
-		llvmTypes[i] = llvmType
+		llvmTypes = append(llvmTypes, llvmType)
 	}
 
 	return llvmTypes, nil
 }
 
 func (converter *FunctionConverter) registerParameters(nirFunction *nir.Function, llvmFunction llvm.Value) error {
-	if len(nirFunction.Parameters) == 0 {
+	if len(nirFunction.Parameters) == 0 && nirFunction.Receiver == nil {
 		return nil
 	}
 
@@ -93,8 +140,20 @@ func (converter *FunctionConverter) registerParameters(nirFunction *nir.Function
 	llvmEntryBlock := llvm.AddBasicBlock(llvmFunction, entryBlock.Name)
 	builder.SetInsertPointAtEnd(llvmEntryBlock)
 
-	for i, param := range nirFunction.Parameters {
-		llvmParam := llvmFunction.Param(i)
+	paramIndex := 0
+
+	// The receiver arrives as a pointer already, so unlike an ordinary
+	// parameter below it's registered as-is rather than copied into its
+	// own alloca: GEPInstruction needs the pointer itself, not a pointer
+	// to a local copy of it.
+	if nirFunction.Receiver != nil {
+		receiverVariable := nirvalue.NewVariable(nirFunction.Receiver.Name, nirFunction.Receiver.Type)
+		converter.valueConverter.RegisterVariable(receiverVariable, llvmFunction.Param(paramIndex))
+		paramIndex++
+	}
+
+	for _, param := range nirFunction.Parameters {
+		llvmParam := llvmFunction.Param(paramIndex)
 		llvmType, err := converter.typeConverter.Convert(param.Type)
 		if err != nil {
 			return fmt.Errorf("failed to convert parameter %s type: %w", param.Name, err)
@@ -106,33 +165,46 @@ func (converter *FunctionConverter) registerParameters(nirFunction *nir.Function
 
 		paramVariable := nirvalue.NewVariable(param.Name, param.Type)
 		converter.valueConverter.RegisterVariable(paramVariable, allocaInstruction)
+
+		paramIndex++
 	}
 
 	return nil
 }
 
-func (converter *FunctionConverter) convertBasicBlocks(nirFunction *nir.Function, llvmFunction llvm.Value) error {
+// convertBasicBlocks runs in two passes so a block can branch forward to a
+// sibling that hasn't had its instructions filled in yet (e.g. an if
+// statement's `then` block jumping to a `merge` block that appears later
+// in nirFunction.BasicBlocks): the first pass creates every LLVM block up
+// front, and the second fills in instructions/terminators once all branch
+// targets are resolvable.
+func (converter *FunctionConverter) convertBasicBlocks(nirFunction *nir.Function, llvmFunction llvm.Value, debugScope llvm.Metadata) error {
 	builder := converter.context.GetRawContext().NewBuilder()
 	defer builder.Dispose()
 
+	blocksByName := make(map[string]llvm.BasicBlock, len(nirFunction.BasicBlocks))
+	for _, nirBlock := range nirFunction.BasicBlocks {
+		if nirBlock.Name == "entry" {
+			blocksByName[nirBlock.Name] = llvmFunction.FirstBasicBlock()
+		} else {
+			blocksByName[nirBlock.Name] = llvm.AddBasicBlock(llvmFunction, nirBlock.Name)
+		}
+	}
+
 	converter.instructionConverter = NewInstructionConverter(
 		builder,
 		converter.valueConverter,
 		converter.typeConverter,
+		blocksByName,
+		converter.errorCollector,
 	)
 
 	for _, nirBlock := range nirFunction.BasicBlocks {
-		var llvmBlock llvm.BasicBlock
-
-		if nirBlock.Name == "entry" {
-			llvmBlock = llvmFunction.FirstBasicBlock()
-		} else {
-			llvmBlock = llvm.AddBasicBlock(llvmFunction, nirBlock.Name)
-		}
-
-		builder.SetInsertPointAtEnd(llvmBlock)
+		builder.SetInsertPointAtEnd(blocksByName[nirBlock.Name])
 
 		for _, instruction := range nirBlock.Instructions {
+			converter.instructionConverter.SetCurrentLoc(nirFunction.Locations[instruction])
+			converter.setDebugLocation(builder, nirFunction, instruction, debugScope)
 			err := converter.convertInstruction(instruction)
 			if err != nil {
 				return fmt.Errorf("failed to convert instruction %s: %w", instruction.String(), err)
@@ -140,6 +212,8 @@ func (converter *FunctionConverter) convertBasicBlocks(nirFunction *nir.Function
 		}
 
 		if nirBlock.Terminator != nil {
+			converter.instructionConverter.SetCurrentLoc(nirFunction.Locations[nirBlock.Terminator])
+			converter.setDebugLocation(builder, nirFunction, nirBlock.Terminator, debugScope)
 			err := converter.convertInstruction(nirBlock.Terminator)
 			if err != nil {
 				return fmt.Errorf("failed to convert terminator %s: %w", nirBlock.Terminator.String(), err)
@@ -150,6 +224,29 @@ func (converter *FunctionConverter) convertBasicBlocks(nirFunction *nir.Function
 	return nil
 }
 
+// setDebugLocation attaches a !DILocation to builder's current debug state
+// before the next instruction it emits, using the location recorded for
+// inst during NIR lowering. It's a no-op when debug info wasn't requested
+// or inst has no recorded location (one built before any source location
+// was set during lowering).
+func (converter *FunctionConverter) setDebugLocation(
+	builder llvm.Builder,
+	nirFunction *nir.Function,
+	inst nirinstruction.Instruction,
+	debugScope llvm.Metadata,
+) {
+	if converter.debugInfo == nil {
+		return
+	}
+
+	loc, ok := nirFunction.Locations[inst]
+	if !ok {
+		return
+	}
+
+	builder.SetCurrentDebugLocation(converter.debugInfo.Location(loc, debugScope))
+}
+
 func (converter *FunctionConverter) convertInstruction(instruction nirinstruction.Instruction) error {
 	switch instruction := instruction.(type) {
 	case *nirinstruction.AllocInstruction:
@@ -167,9 +264,27 @@ func (converter *FunctionConverter) convertInstruction(instruction nirinstructio
 	case *nirinstruction.CallInstruction:
 		return converter.instructionConverter.ConvertCall(instruction)
 
+	case *nirinstruction.ExtractInstruction:
+		return converter.instructionConverter.ConvertExtract(instruction)
+
+	case *nirinstruction.GEPInstruction:
+		return converter.instructionConverter.ConvertGEP(instruction)
+
 	case *nirinstruction.ReturnInstruction:
 		return converter.instructionConverter.ConvertReturn(instruction)
 
+	case *nirinstruction.PhiInstruction:
+		return converter.instructionConverter.ConvertPhi(instruction)
+
+	case *nirinstruction.BranchInstruction:
+		return converter.instructionConverter.ConvertBranch(instruction)
+
+	case *nirinstruction.CondBranchInstruction:
+		return converter.instructionConverter.ConvertCondBranch(instruction)
+
+	case *nirinstruction.ConvertInstruction:
+		return converter.instructionConverter.ConvertConvert(instruction)
+
 	default:
 		return fmt.Errorf("unsupported instruction type: %T", instruction)
 	}
@@ -1,31 +1,57 @@
 package llvm
 
 import (
+	"compiler/codegen"
+	"compiler/errors"
 	"compiler/nir"
 	"fmt"
+
+	"tinygo.org/x/go-llvm"
 )
 
+// Generator implements codegen.Backend: it lowers an optimized, SSA-form
+// nir.Module to LLVM IR text.
+var _ codegen.Backend = (*Generator)(nil)
+
 type Generator struct {
-	context *Context
+	context        *Context
+	module         llvm.Module
+	errorCollector *errors.ErrorCollector
 }
 
-func NewGenerator() *Generator {
+// NewGenerator builds a Generator that reports codegen failures (e.g. an
+// unsupported binary operator, a call to a function missing from the
+// module) to errorCollector with the source location NIR lowering
+// recorded for the offending instruction, rather than only as a bare
+// error string. errorCollector may be nil, in which case codegen errors
+// are only ever returned, not reported.
+func NewGenerator(errorCollector *errors.ErrorCollector) *Generator {
 	return &Generator{
-		context: NewContext(),
+		context:        NewContext(),
+		errorCollector: errorCollector,
 	}
 }
 
 func (generator *Generator) Generate(nirModule *nir.Module) (string, error) {
-	moduleConverter := NewModuleConverter(generator.context, nirModule.Name)
+	moduleConverter := NewModuleConverter(generator.context, nirModule.Name, generator.errorCollector)
 
 	llvmIr, err := moduleConverter.Convert(nirModule)
 	if err != nil {
 		return "", fmt.Errorf("failed to convert module: %w", err)
 	}
+	generator.module = moduleConverter.module
 
 	return llvmIr, nil
 }
 
+// Module returns the llvm.Module built by the most recent Generate call,
+// for callers (currently codegen/llvm/jit) that need the raw module
+// rather than its printed IR text. It's the zero Module until Generate
+// has run once.
+func (generator *Generator) Module() llvm.Module {
+	return generator.module
+}
+
 func (generator *Generator) Dispose() {
 	if generator.context != nil {
 		generator.context.Dispose()
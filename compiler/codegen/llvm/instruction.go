@@ -1,9 +1,13 @@
 package llvm
 
 import (
+	"compiler/codegen/llvm/runtime"
 	llvmvalue "compiler/codegen/llvm/value"
+	"compiler/errors"
+	"compiler/nir"
 	"compiler/nir/instruction"
 	nirvalue "compiler/nir/value"
+	"compiler/types"
 	"fmt"
 
 	"tinygo.org/x/go-llvm"
@@ -13,36 +17,71 @@ type InstructionConverter struct {
 	builder        llvm.Builder
 	valueConverter *llvmvalue.Converter
 	typeConverter  *TypeConverter
+	errorCollector *errors.ErrorCollector
+
+	// currentLoc is the source location of the instruction currently
+	// being converted, set by FunctionConverter.convertBasicBlocks before
+	// each convertInstruction call so report can attribute an error to
+	// it, the same way setDebugLocation attributes !DILocation metadata.
+	currentLoc nir.SourceLoc
+
+	// blocksByName resolves a NIR block's Name to the LLVM block
+	// FunctionConverter.convertBasicBlocks pre-created for it, so a
+	// branch can target a sibling block regardless of lowering order.
+	blocksByName map[string]llvm.BasicBlock
 }
 
 func NewInstructionConverter(
 	builder llvm.Builder,
 	valueConverter *llvmvalue.Converter,
 	typeConverter *TypeConverter,
+	blocksByName map[string]llvm.BasicBlock,
+	errorCollector *errors.ErrorCollector,
 ) *InstructionConverter {
 	return &InstructionConverter{
 		builder:        builder,
 		valueConverter: valueConverter,
 		typeConverter:  typeConverter,
+		blocksByName:   blocksByName,
+		errorCollector: errorCollector,
 	}
 }
 
+// SetCurrentLoc records the source location that report should attribute
+// the next error to, until the next call changes it.
+func (converter *InstructionConverter) SetCurrentLoc(loc nir.SourceLoc) {
+	converter.currentLoc = loc
+}
+
+// report formats an error the same way fmt.Errorf does, additionally
+// recording it with errorCollector (if one was supplied) at currentLoc so
+// it's displayed with a caret-underlined source snippet instead of only
+// as a bare string, then returns it for the caller's usual `return
+// converter.report(...)`.
+func (converter *InstructionConverter) report(format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	if converter.errorCollector != nil {
+		converter.errorCollector.Add(errors.CodegenError, converter.currentLoc.Line, converter.currentLoc.Column, 0, "%s", err)
+	}
+	return err
+}
+
 func (converter *InstructionConverter) ConvertAlloc(allocInstruction *instruction.AllocInstruction) error {
 	allocateType := allocInstruction.GetAllocateType()
 
 	llvmType, err := converter.typeConverter.Convert(allocateType)
 	if err != nil {
-		return fmt.Errorf("failed to convert allocate type: %w", err)
+		return converter.report("failed to convert allocate type: %w", err)
 	}
 
 	result := allocInstruction.GetResult()
 	if result == nil {
-		return fmt.Errorf("alloc instruction has no result")
+		return converter.report("alloc instruction has no result")
 	}
 
 	variable, ok := result.(*nirvalue.Variable)
 	if !ok {
-		return fmt.Errorf("alloc result must be a variable, got %T", result)
+		return converter.report("alloc result must be a variable, got %T", result)
 	}
 
 	allocaInstruction := converter.builder.CreateAlloca(llvmType, variable.String())
@@ -55,22 +94,22 @@ func (converter *InstructionConverter) ConvertAlloc(allocInstruction *instructio
 func (converter *InstructionConverter) ConvertStore(storeInstruction *instruction.StoreInstruction) error {
 	destination := storeInstruction.GetDestination()
 	if destination == nil {
-		return fmt.Errorf("store instruction has no destination")
+		return converter.report("store instruction has no destination")
 	}
 
 	destinationPointer, err := converter.valueConverter.Convert(destination)
 	if err != nil {
-		return fmt.Errorf("failed to convert store destination: %w", err)
+		return converter.report("failed to convert store destination: %w", err)
 	}
 
 	value := storeInstruction.GetValue()
 	if value == nil {
-		return fmt.Errorf("store instruction has no value")
+		return converter.report("store instruction has no value")
 	}
 
 	llvmValue, err := converter.valueConverter.Convert(value)
 	if err != nil {
-		return fmt.Errorf("failed to convert store value: %w", err)
+		return converter.report("failed to convert store value: %w", err)
 	}
 
 	converter.builder.CreateStore(llvmValue, destinationPointer)
@@ -81,27 +120,27 @@ func (converter *InstructionConverter) ConvertStore(storeInstruction *instructio
 func (converter *InstructionConverter) ConvertLoad(loadInstruction *instruction.LoadInstruction) error {
 	source := loadInstruction.GetSource()
 	if source == nil {
-		return fmt.Errorf("load instruction has no source")
+		return converter.report("load instruction has no source")
 	}
 
 	sourcePointer, err := converter.valueConverter.Convert(source)
 	if err != nil {
-		return fmt.Errorf("failed to convert load source: %w", err)
+		return converter.report("failed to convert load source: %w", err)
 	}
 
 	result := loadInstruction.GetResult()
 	if result == nil {
-		return fmt.Errorf("load instruction has no result")
+		return converter.report("load instruction has no result")
 	}
 
 	temporary, ok := result.(*nirvalue.Temporary)
 	if !ok {
-		return fmt.Errorf("load result must be a temporary, got %T", result)
+		return converter.report("load result must be a temporary, got %T", result)
 	}
 
 	loadType, err := converter.typeConverter.Convert(temporary.Type())
 	if err != nil {
-		return fmt.Errorf("failed to convert load type: %w", err)
+		return converter.report("failed to convert load type: %w", err)
 	}
 
 	loadedValue := converter.builder.CreateLoad(loadType, sourcePointer, "")
@@ -114,49 +153,130 @@ func (converter *InstructionConverter) ConvertLoad(loadInstruction *instruction.
 func (converter *InstructionConverter) ConvertBinary(binaryInstruction *instruction.BinaryInstruction) error {
 	left := binaryInstruction.GetLeft()
 	if left == nil {
-		return fmt.Errorf("binary instruction has no left operand")
+		return converter.report("binary instruction has no left operand")
 	}
 
 	llvmLeft, err := converter.valueConverter.Convert(left)
 	if err != nil {
-		return fmt.Errorf("failed to convert binary left operand: %w", err)
+		return converter.report("failed to convert binary left operand: %w", err)
 	}
 
 	right := binaryInstruction.GetRight()
 	if right == nil {
-		return fmt.Errorf("binary instruction has no right operand")
+		return converter.report("binary instruction has no right operand")
 	}
 
 	llvmRight, err := converter.valueConverter.Convert(right)
 	if err != nil {
-		return fmt.Errorf("failed to convert binary right operand: %w", err)
+		return converter.report("failed to convert binary right operand: %w", err)
 	}
 
 	result := binaryInstruction.GetResult()
 	if result == nil {
-		return fmt.Errorf("binary instruction has no result")
+		return converter.report("binary instruction has no result")
 	}
 
 	temporary, ok := result.(*nirvalue.Temporary)
 	if !ok {
-		return fmt.Errorf("binary result must be a temporary, got %T", result)
+		return converter.report("binary result must be a temporary, got %T", result)
+	}
+
+	if left.Type().Equals(types.String) || right.Type().Equals(types.String) {
+		return converter.report("string operands must be lowered to a %s call, not a binary instruction", runtime.StringConcatName)
 	}
 
 	operator := binaryInstruction.GetOperator()
+	isFloat := types.IsFloat(left.Type())
+	isUnsigned := types.IsUnsigned(left.Type())
+
 	var llvmResult llvm.Value
 	switch operator {
 	case instruction.BinaryAdd:
-		llvmResult = converter.builder.CreateAdd(llvmLeft, llvmRight, "")
+		if isFloat {
+			llvmResult = converter.builder.CreateFAdd(llvmLeft, llvmRight, "")
+		} else {
+			llvmResult = converter.builder.CreateAdd(llvmLeft, llvmRight, "")
+		}
 	case instruction.BinarySubtract:
-		llvmResult = converter.builder.CreateSub(llvmLeft, llvmRight, "")
+		if isFloat {
+			llvmResult = converter.builder.CreateFSub(llvmLeft, llvmRight, "")
+		} else {
+			llvmResult = converter.builder.CreateSub(llvmLeft, llvmRight, "")
+		}
 	case instruction.BinaryMultiply:
-		llvmResult = converter.builder.CreateMul(llvmLeft, llvmRight, "")
+		if isFloat {
+			llvmResult = converter.builder.CreateFMul(llvmLeft, llvmRight, "")
+		} else {
+			llvmResult = converter.builder.CreateMul(llvmLeft, llvmRight, "")
+		}
 	case instruction.BinaryDivide:
-		llvmResult = converter.builder.CreateSDiv(llvmLeft, llvmRight, "")
+		switch {
+		case isFloat:
+			llvmResult = converter.builder.CreateFDiv(llvmLeft, llvmRight, "")
+		case isUnsigned:
+			llvmResult = converter.builder.CreateUDiv(llvmLeft, llvmRight, "")
+		default:
+			llvmResult = converter.builder.CreateSDiv(llvmLeft, llvmRight, "")
+		}
 	case instruction.BinaryModulo:
-		llvmResult = converter.builder.CreateSRem(llvmLeft, llvmRight, "")
+		switch {
+		case isFloat:
+			llvmResult = converter.builder.CreateFRem(llvmLeft, llvmRight, "")
+		case isUnsigned:
+			llvmResult = converter.builder.CreateURem(llvmLeft, llvmRight, "")
+		default:
+			llvmResult = converter.builder.CreateSRem(llvmLeft, llvmRight, "")
+		}
+	case instruction.BinaryEqual:
+		if isFloat {
+			llvmResult = converter.builder.CreateFCmp(llvm.FloatOEQ, llvmLeft, llvmRight, "")
+		} else {
+			llvmResult = converter.builder.CreateICmp(llvm.IntEQ, llvmLeft, llvmRight, "")
+		}
+	case instruction.BinaryNotEqual:
+		if isFloat {
+			llvmResult = converter.builder.CreateFCmp(llvm.FloatONE, llvmLeft, llvmRight, "")
+		} else {
+			llvmResult = converter.builder.CreateICmp(llvm.IntNE, llvmLeft, llvmRight, "")
+		}
+	case instruction.BinaryLess:
+		switch {
+		case isFloat:
+			llvmResult = converter.builder.CreateFCmp(llvm.FloatOLT, llvmLeft, llvmRight, "")
+		case isUnsigned:
+			llvmResult = converter.builder.CreateICmp(llvm.IntULT, llvmLeft, llvmRight, "")
+		default:
+			llvmResult = converter.builder.CreateICmp(llvm.IntSLT, llvmLeft, llvmRight, "")
+		}
+	case instruction.BinaryLessEqual:
+		switch {
+		case isFloat:
+			llvmResult = converter.builder.CreateFCmp(llvm.FloatOLE, llvmLeft, llvmRight, "")
+		case isUnsigned:
+			llvmResult = converter.builder.CreateICmp(llvm.IntULE, llvmLeft, llvmRight, "")
+		default:
+			llvmResult = converter.builder.CreateICmp(llvm.IntSLE, llvmLeft, llvmRight, "")
+		}
+	case instruction.BinaryGreater:
+		switch {
+		case isFloat:
+			llvmResult = converter.builder.CreateFCmp(llvm.FloatOGT, llvmLeft, llvmRight, "")
+		case isUnsigned:
+			llvmResult = converter.builder.CreateICmp(llvm.IntUGT, llvmLeft, llvmRight, "")
+		default:
+			llvmResult = converter.builder.CreateICmp(llvm.IntSGT, llvmLeft, llvmRight, "")
+		}
+	case instruction.BinaryGreaterEqual:
+		switch {
+		case isFloat:
+			llvmResult = converter.builder.CreateFCmp(llvm.FloatOGE, llvmLeft, llvmRight, "")
+		case isUnsigned:
+			llvmResult = converter.builder.CreateICmp(llvm.IntUGE, llvmLeft, llvmRight, "")
+		default:
+			llvmResult = converter.builder.CreateICmp(llvm.IntSGE, llvmLeft, llvmRight, "")
+		}
 	default:
-		return fmt.Errorf("unsupported binary operator: %v", operator)
+		return converter.report("unsupported binary operator: %v", operator)
 	}
 
 	converter.valueConverter.RegisterTemporary(temporary, llvmResult)
@@ -167,18 +287,29 @@ func (converter *InstructionConverter) ConvertBinary(binaryInstruction *instruct
 func (converter *InstructionConverter) ConvertCall(callInstruction *instruction.CallInstruction) error {
 	functionName := callInstruction.GetFunctionName()
 	if functionName == "" {
-		return fmt.Errorf("call instruction has no function name")
+		return converter.report("call instruction has no function name")
 	}
 
 	arguments := callInstruction.GetArguments()
 
-	llvmArguments := make([]llvm.Value, len(arguments))
+	llvmArguments := make([]llvm.Value, 0, len(arguments)+1)
+
+	// A method call's receiver is the callee's implicit first argument
+	// (see FunctionConverter.convertParameterTypes).
+	if receiver := callInstruction.GetReceiver(); receiver != nil {
+		llvmReceiver, err := converter.valueConverter.Convert(receiver)
+		if err != nil {
+			return converter.report("failed to convert call receiver: %w", err)
+		}
+		llvmArguments = append(llvmArguments, llvmReceiver)
+	}
+
 	for i, arg := range arguments {
 		llvmArg, err := converter.valueConverter.Convert(arg)
 		if err != nil {
-			return fmt.Errorf("failed to convert call argument %d: %w", i, err)
+			return converter.report("failed to convert call argument %d: %w", i, err)
 		}
-		llvmArguments[i] = llvmArg
+		llvmArguments = append(llvmArguments, llvmArg)
 	}
 
 	function := converter.builder.GetInsertBlock().Parent()
@@ -186,7 +317,16 @@ func (converter *InstructionConverter) ConvertCall(callInstruction *instruction.
 	calleeFunction := module.NamedFunction(functionName)
 
 	if calleeFunction.IsNil() {
-		return fmt.Errorf("function %s not found in module", functionName)
+		argTypes := make([]types.Type, len(arguments))
+		for i, arg := range arguments {
+			argTypes[i] = arg.Type()
+		}
+
+		runtimeFunction, err := converter.lookupRuntimeFunction(functionName, argTypes, module)
+		if err != nil {
+			return err
+		}
+		calleeFunction = runtimeFunction
 	}
 
 	functionType := calleeFunction.Type().ElementType()
@@ -195,7 +335,7 @@ func (converter *InstructionConverter) ConvertCall(callInstruction *instruction.
 	if result != nil {
 		temporary, ok := result.(*nirvalue.Temporary)
 		if !ok {
-			return fmt.Errorf("call result must be a temporary, got %T", result)
+			return converter.report("call result must be a temporary, got %T", result)
 		}
 
 		llvmResult := converter.builder.CreateCall(functionType, calleeFunction, llvmArguments, "")
@@ -207,7 +347,120 @@ func (converter *InstructionConverter) ConvertCall(callInstruction *instruction.
 	return nil
 }
 
+// lookupRuntimeFunction declares and returns one of the small set of
+// runtime helpers (see the runtime package) callable by name from NIR,
+// for a CallInstruction whose functionName doesn't correspond to a
+// user- or NIR-defined function. functionName is usually an overloaded
+// builtin's unmangled name (e.g. "print"), resolved against argTypes
+// through runtime.Registry; StringConcatName is the one exception,
+// already the mangled symbol some other lowering path calls directly.
+func (converter *InstructionConverter) lookupRuntimeFunction(functionName string, argTypes []types.Type, module llvm.Module) (llvm.Value, error) {
+	context := converter.typeConverter.context.GetRawContext()
+
+	if functionName == runtime.StringConcatName {
+		return runtime.StringConcat(context, module), nil
+	}
+
+	descriptor, signature := converter.runtimeRegistry().Resolve(functionName, argTypes)
+	if descriptor == nil {
+		return llvm.Value{}, converter.report("function %s not found in module", functionName)
+	}
+	if signature == nil {
+		return llvm.Value{}, converter.report("no overload of %s accepts the given argument types", functionName)
+	}
+
+	calleeFunction, err := runtime.Declare(context, module, descriptor, signature)
+	if err != nil {
+		return llvm.Value{}, converter.report("failed to declare %s: %w", signature.Name, err)
+	}
+
+	return calleeFunction, nil
+}
+
+// runtimeRegistry builds a fresh runtime.Registry for a single lookup:
+// the set of builtins it describes is static, so there's no per-module
+// state worth threading through InstructionConverter's constructor for it.
+func (converter *InstructionConverter) runtimeRegistry() *runtime.Registry {
+	return runtime.NewRegistry()
+}
+
+func (converter *InstructionConverter) ConvertExtract(extractInstruction *instruction.ExtractInstruction) error {
+	source := extractInstruction.GetSource()
+	if source == nil {
+		return converter.report("extract instruction has no source")
+	}
+
+	llvmSource, err := converter.valueConverter.Convert(source)
+	if err != nil {
+		return converter.report("failed to convert extract source: %w", err)
+	}
+
+	result := extractInstruction.GetResult()
+	if result == nil {
+		return converter.report("extract instruction has no result")
+	}
+
+	temporary, ok := result.(*nirvalue.Temporary)
+	if !ok {
+		return converter.report("extract result must be a temporary, got %T", result)
+	}
+
+	extractedValue := converter.builder.CreateExtractValue(llvmSource, extractInstruction.GetIndex(), "")
+
+	converter.valueConverter.RegisterTemporary(temporary, extractedValue)
+
+	return nil
+}
+
+// ConvertGEP computes the address of a field within a class or struct
+// instance via CreateStructGEP, for a GEPInstruction lowered from
+// `object.field` (see ast.MemberExpression). Like ConvertAlloc's result,
+// the registered temporary is a pointer even though its NIR type is the
+// field's value type.
+func (converter *InstructionConverter) ConvertGEP(gepInstruction *instruction.GEPInstruction) error {
+	object := gepInstruction.GetObject()
+	if object == nil {
+		return converter.report("gep instruction has no object")
+	}
+
+	switch object.Type().(type) {
+	case *types.ClassType, *types.StructType:
+	default:
+		return converter.report("gep object must be a class or struct instance, got %s", object.Type().String())
+	}
+
+	llvmObject, err := converter.valueConverter.Convert(object)
+	if err != nil {
+		return converter.report("failed to convert gep object: %w", err)
+	}
+
+	llvmClassType, err := converter.typeConverter.Convert(object.Type())
+	if err != nil {
+		return converter.report("failed to convert gep object's type: %w", err)
+	}
+
+	result := gepInstruction.GetResult()
+	if result == nil {
+		return converter.report("gep instruction has no result")
+	}
+
+	temporary, ok := result.(*nirvalue.Temporary)
+	if !ok {
+		return converter.report("gep result must be a temporary, got %T", result)
+	}
+
+	fieldPointer := converter.builder.CreateStructGEP(llvmClassType, llvmObject, gepInstruction.GetIndex(), "")
+
+	converter.valueConverter.RegisterTemporary(temporary, fieldPointer)
+
+	return nil
+}
+
 func (converter *InstructionConverter) ConvertReturn(returnInstruction *instruction.ReturnInstruction) error {
+	if returnValues := returnInstruction.GetValues(); len(returnValues) > 1 {
+		return converter.convertReturnMulti(returnValues)
+	}
+
 	returnValue := returnInstruction.GetValue()
 
 	if returnValue == nil {
@@ -215,10 +468,163 @@ func (converter *InstructionConverter) ConvertReturn(returnInstruction *instruct
 	} else {
 		llvmValue, err := converter.valueConverter.Convert(returnValue)
 		if err != nil {
-			return fmt.Errorf("failed to convert return value: %w", err)
+			return converter.report("failed to convert return value: %w", err)
 		}
 		converter.builder.CreateRet(llvmValue)
 	}
 
 	return nil
 }
+
+func (converter *InstructionConverter) ConvertPhi(phiInstruction *instruction.PhiInstruction) error {
+	result := phiInstruction.GetResult()
+	if result == nil {
+		return converter.report("phi instruction has no result")
+	}
+
+	temporary, ok := result.(*nirvalue.Temporary)
+	if !ok {
+		return converter.report("phi result must be a temporary, got %T", result)
+	}
+
+	llvmType, err := converter.typeConverter.Convert(temporary.Type())
+	if err != nil {
+		return converter.report("failed to convert phi type: %w", err)
+	}
+
+	phi := converter.builder.CreatePHI(llvmType, "")
+
+	for _, incoming := range phiInstruction.GetIncoming() {
+		llvmValue, err := converter.valueConverter.Convert(incoming.Value)
+		if err != nil {
+			return converter.report("failed to convert phi incoming value: %w", err)
+		}
+
+		predecessor, ok := converter.blocksByName[incoming.Predecessor]
+		if !ok {
+			return converter.report("phi predecessor %s not found", incoming.Predecessor)
+		}
+
+		phi.AddIncoming([]llvm.Value{llvmValue}, []llvm.BasicBlock{predecessor})
+	}
+
+	converter.valueConverter.RegisterTemporary(temporary, phi)
+
+	return nil
+}
+
+func (converter *InstructionConverter) ConvertConvert(convertInstruction *instruction.ConvertInstruction) error {
+	source := convertInstruction.GetSource()
+	if source == nil {
+		return converter.report("convert instruction has no source")
+	}
+
+	llvmSource, err := converter.valueConverter.Convert(source)
+	if err != nil {
+		return converter.report("failed to convert convert source: %w", err)
+	}
+
+	result := convertInstruction.GetResult()
+	if result == nil {
+		return converter.report("convert instruction has no result")
+	}
+
+	temporary, ok := result.(*nirvalue.Temporary)
+	if !ok {
+		return converter.report("convert result must be a temporary, got %T", result)
+	}
+
+	llvmType, err := converter.typeConverter.Convert(temporary.Type())
+	if err != nil {
+		return converter.report("failed to convert target type: %w", err)
+	}
+
+	var llvmResult llvm.Value
+	switch convertInstruction.GetKind() {
+	case instruction.SignExtend:
+		llvmResult = converter.builder.CreateSExt(llvmSource, llvmType, "")
+	case instruction.ZeroExtend:
+		llvmResult = converter.builder.CreateZExt(llvmSource, llvmType, "")
+	case instruction.Truncate:
+		llvmResult = converter.builder.CreateTrunc(llvmSource, llvmType, "")
+	case instruction.FloatExtend:
+		llvmResult = converter.builder.CreateFPExt(llvmSource, llvmType, "")
+	case instruction.FloatTruncate:
+		llvmResult = converter.builder.CreateFPTrunc(llvmSource, llvmType, "")
+	default:
+		return converter.report("unsupported convert kind: %v", convertInstruction.GetKind())
+	}
+
+	converter.valueConverter.RegisterTemporary(temporary, llvmResult)
+
+	return nil
+}
+
+func (converter *InstructionConverter) ConvertBranch(branchInstruction *instruction.BranchInstruction) error {
+	target, ok := converter.blocksByName[branchInstruction.GetTarget()]
+	if !ok {
+		return converter.report("branch target %s not found", branchInstruction.GetTarget())
+	}
+
+	converter.builder.CreateBr(target)
+
+	return nil
+}
+
+func (converter *InstructionConverter) ConvertCondBranch(condBranchInstruction *instruction.CondBranchInstruction) error {
+	condition := condBranchInstruction.GetCondition()
+	if condition == nil {
+		return converter.report("cond branch instruction has no condition")
+	}
+
+	llvmCondition, err := converter.valueConverter.Convert(condition)
+	if err != nil {
+		return converter.report("failed to convert branch condition: %w", err)
+	}
+
+	zero := llvm.ConstInt(llvmCondition.Type(), 0, false)
+	truthy := converter.builder.CreateICmp(llvm.IntNE, llvmCondition, zero, "")
+
+	trueBlock, ok := converter.blocksByName[condBranchInstruction.GetTrueTarget()]
+	if !ok {
+		return converter.report("branch true-target %s not found", condBranchInstruction.GetTrueTarget())
+	}
+
+	falseBlock, ok := converter.blocksByName[condBranchInstruction.GetFalseTarget()]
+	if !ok {
+		return converter.report("branch false-target %s not found", condBranchInstruction.GetFalseTarget())
+	}
+
+	converter.builder.CreateCondBr(truthy, trueBlock, falseBlock)
+
+	return nil
+}
+
+// convertReturnMulti packs a multi-value return into the struct the
+// function's TupleType return type was lowered to (see
+// TypeConverter.convertTupleType), one insertvalue per element, then
+// returns the aggregate.
+func (converter *InstructionConverter) convertReturnMulti(returnValues []nirvalue.Value) error {
+	elementTypes := make([]llvm.Type, len(returnValues))
+	llvmValues := make([]llvm.Value, len(returnValues))
+
+	for i, returnValue := range returnValues {
+		llvmValue, err := converter.valueConverter.Convert(returnValue)
+		if err != nil {
+			return converter.report("failed to convert return value %d: %w", i, err)
+		}
+		llvmValues[i] = llvmValue
+		elementTypes[i] = llvmValue.Type()
+	}
+
+	structType := converter.typeConverter.context.GetRawContext().StructType(elementTypes, false)
+	aggregate := llvm.Undef(structType)
+
+	for i, llvmValue := range llvmValues {
+		aggregate = converter.builder.CreateInsertValue(aggregate, llvmValue, i, "")
+	}
+
+	converter.builder.CreateRet(aggregate)
+
+	return nil
+}
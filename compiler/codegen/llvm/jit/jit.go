@@ -0,0 +1,63 @@
+// Package jit runs a compiled module in-process via LLVM's MCJIT, so
+// `naviary run` can execute a program without shelling out to lli/clang.
+package jit
+
+import (
+	"fmt"
+
+	"tinygo.org/x/go-llvm"
+)
+
+// Engine wraps an MCJIT execution engine over a single llvm.Module.
+type Engine struct {
+	engine llvm.ExecutionEngine
+}
+
+// New verifies module and builds an MCJIT execution engine over it.
+// Callers are responsible for calling Dispose once the returned Engine is
+// no longer needed.
+func New(module llvm.Module) (*Engine, error) {
+	if err := llvm.VerifyModule(module, llvm.ReturnStatusAction); err != nil {
+		return nil, fmt.Errorf("failed to verify module: %w", err)
+	}
+
+	llvm.LinkInMCJIT()
+	if err := llvm.InitializeNativeTarget(); err != nil {
+		return nil, fmt.Errorf("failed to initialize native target: %w", err)
+	}
+	if err := llvm.InitializeNativeAsmPrinter(); err != nil {
+		return nil, fmt.Errorf("failed to initialize native asm printer: %w", err)
+	}
+
+	options := llvm.NewMCJITCompilerOptions()
+	engine, err := llvm.NewMCJITCompiler(module, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create execution engine: %w", err)
+	}
+
+	return &Engine{engine: engine}, nil
+}
+
+// RunMain looks up main in the jitted module and invokes it with no
+// arguments, returning the program's exit code - main's return value, or
+// 0 if main is declared void.
+func (engine *Engine) RunMain() (int, error) {
+	mainFunction := engine.engine.FindFunction("main")
+	if mainFunction.IsNil() {
+		return 0, fmt.Errorf("no main function found in module")
+	}
+
+	result := engine.engine.RunFunction(mainFunction, []llvm.GenericValue{})
+	defer result.Dispose()
+
+	if mainFunction.Type().ElementType().ReturnType().TypeKind() == llvm.VoidTypeKind {
+		return 0, nil
+	}
+
+	return int(result.Int(false)), nil
+}
+
+// Dispose releases the execution engine and the module it owns.
+func (engine *Engine) Dispose() {
+	engine.engine.Dispose()
+}
@@ -1,9 +1,12 @@
 package llvm
 
 import (
+	"compiler/codegen/llvm/debuginfo"
 	llvmvalue "compiler/codegen/llvm/value"
+	"compiler/errors"
 	"compiler/nir"
 	"fmt"
+	"sort"
 
 	"tinygo.org/x/go-llvm"
 )
@@ -14,14 +17,15 @@ type ModuleConverter struct {
 	typeConverter     *TypeConverter
 	valueConverter    *llvmvalue.Converter
 	functionConverter *FunctionConverter
+	errorCollector    *errors.ErrorCollector
 }
 
-func NewModuleConverter(context *Context, moduleName string) *ModuleConverter {
+func NewModuleConverter(context *Context, moduleName string, errorCollector *errors.ErrorCollector) *ModuleConverter {
 	module := context.GetRawContext().NewModule(moduleName)
 
 	typeConverter := NewTypeConverter(context)
 	valueConverter := llvmvalue.NewConverter(typeConverter)
-	functionConverter := NewFunctionConverter(context, module, typeConverter, valueConverter)
+	functionConverter := NewFunctionConverter(context, module, typeConverter, valueConverter, errorCollector)
 
 	return &ModuleConverter{
 		context:           context,
@@ -29,18 +33,18 @@ func NewModuleConverter(context *Context, moduleName string) *ModuleConverter {
 		typeConverter:     typeConverter,
 		valueConverter:    valueConverter,
 		functionConverter: functionConverter,
+		errorCollector:    errorCollector,
 	}
 }
 
 func (converter *ModuleConverter) Convert(nirModule *nir.Module) (string, error) {
-	err := converter.declareRuntimeFunctions()
+	err := converter.convertFunctions(nirModule.Functions)
 	if err != nil {
-		return "", fmt.Errorf("failed to declare runtime functions: %w", err)
+		return "", fmt.Errorf("failed to convert functions: %w", err)
 	}
 
-	err = converter.convertFunctions(nirModule.Functions)
-	if err != nil {
-		return "", fmt.Errorf("failed to convert functions: %w", err)
+	if err := converter.buildEventHandlerTable(nirModule.EventHandlers); err != nil {
+		return "", fmt.Errorf("failed to build event handler table: %w", err)
 	}
 
 	if err := llvm.VerifyModule(converter.module, llvm.ReturnStatusAction); err != nil {
@@ -52,13 +56,19 @@ func (converter *ModuleConverter) Convert(nirModule *nir.Module) (string, error)
 	return llvmIR, nil
 }
 
-func (converter *ModuleConverter) declareRuntimeFunctions() error {
-	// TODO: currently we only support int64 type for print function
-	printParamTypes := []llvm.Type{llvm.GlobalContext().Int64Type()}
-	printFuncType := llvm.FunctionType(llvm.GlobalContext().VoidType(), printParamTypes, false)
-	llvm.AddFunction(converter.module, "print", printFuncType)
+// ConvertWithDebugInfo behaves like Convert but additionally attaches
+// DWARF metadata (!DIFile, !DICompileUnit, !DISubprogram, !DILocation) so
+// the resulting binary can be stepped through in lldb/gdb back to
+// sourcePath.
+func (converter *ModuleConverter) ConvertWithDebugInfo(nirModule *nir.Module, sourcePath string) (string, error) {
+	debugInfo := debuginfo.New(converter.module, sourcePath)
+	converter.functionConverter.debugInfo = debugInfo
 
-	return nil
+	llvmIR, err := converter.Convert(nirModule)
+
+	debugInfo.Finalize()
+
+	return llvmIR, err
 }
 
 func (converter *ModuleConverter) convertFunctions(nirFunctions []*nir.Function) error {
@@ -71,3 +81,57 @@ func (converter *ModuleConverter) convertFunctions(nirFunctions []*nir.Function)
 
 	return nil
 }
+
+// buildEventHandlerTable emits a global array of {name, handler} pairs,
+// one per entry in eventHandlers, so a host runtime can walk
+// naviary_event_table (naviary_event_table_count entries long) at
+// startup and wire each handler to its event without the host needing
+// to know any handler's mangled name ahead of time. It's a no-op when
+// the module declares no `on` handlers, so a program with none doesn't
+// gain an empty global. eventHandlers is iterated in sorted-by-name
+// order so the emitted IR (and therefore the table's layout) doesn't
+// depend on Go's randomized map iteration order.
+func (converter *ModuleConverter) buildEventHandlerTable(eventHandlers map[string]*nir.Function) error {
+	if len(eventHandlers) == 0 {
+		return nil
+	}
+
+	eventNames := make([]string, 0, len(eventHandlers))
+	for eventName := range eventHandlers {
+		eventNames = append(eventNames, eventName)
+	}
+	sort.Strings(eventNames)
+
+	rawContext := converter.context.GetRawContext()
+	stringType := llvm.PointerType(rawContext.Int8Type(), 0)
+	handlerType := llvm.PointerType(llvm.FunctionType(rawContext.VoidType(), nil, false), 0)
+	entryType := rawContext.StructType([]llvm.Type{stringType, handlerType}, false)
+
+	builder := rawContext.NewBuilder()
+	defer builder.Dispose()
+
+	entries := make([]llvm.Value, 0, len(eventNames))
+	for _, eventName := range eventNames {
+		handlerFunction := eventHandlers[eventName]
+
+		llvmHandler := converter.module.NamedFunction(handlerFunction.Name)
+		if llvmHandler.IsNil() {
+			return fmt.Errorf("event handler function %s was not declared", handlerFunction.Name)
+		}
+
+		namePtr := builder.CreateGlobalStringPtr(eventName, eventName+".name")
+		handlerPtr := llvm.ConstBitCast(llvmHandler, handlerType)
+		entries = append(entries, llvm.ConstNamedStruct(entryType, []llvm.Value{namePtr, handlerPtr}))
+	}
+
+	tableType := llvm.ArrayType(entryType, len(entries))
+	table := llvm.AddGlobal(converter.module, tableType, "naviary_event_table")
+	table.SetInitializer(llvm.ConstArray(entryType, entries))
+	table.SetLinkage(llvm.ExternalLinkage)
+
+	countGlobal := llvm.AddGlobal(converter.module, rawContext.Int64Type(), "naviary_event_table_count")
+	countGlobal.SetInitializer(llvm.ConstInt(rawContext.Int64Type(), uint64(len(entries)), false))
+	countGlobal.SetLinkage(llvm.ExternalLinkage)
+
+	return nil
+}
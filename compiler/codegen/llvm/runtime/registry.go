@@ -0,0 +1,144 @@
+package runtime
+
+import (
+	"compiler/types"
+	"fmt"
+
+	"tinygo.org/x/go-llvm"
+)
+
+// Signature is one overload of a builtin: the mangled symbol name the
+// LLVM backend declares/calls for it, and the argument types that select
+// it at a call site.
+type Signature struct {
+	Name       string
+	ParamTypes []types.Type
+}
+
+// Descriptor is a builtin function's full set of overloads, keyed by the
+// unmangled name a NIR CallInstruction carries (e.g. "print") before
+// Resolve picks the mangled Signature matching the call's argument types.
+type Descriptor struct {
+	Name       string
+	ReturnType types.Type
+	Variadic   bool
+	Overloads  []Signature
+}
+
+// Registry holds every builtin the LLVM backend knows how to declare and
+// call by name, the overload-aware counterpart to the single lazily
+// declared runtime.StringConcat: print used to hard-code a single int64
+// overload, so anything passed a float64/bool/string silently
+// mis-lowered.
+type Registry struct {
+	descriptors map[string]*Descriptor
+}
+
+// NewRegistry builds a Registry pre-populated with print's int/float/bool/
+// string overloads, mangled naviary_print_<type> the same way
+// runtime.StringConcatName names naviary_string_concat.
+func NewRegistry() *Registry {
+	registry := &Registry{descriptors: make(map[string]*Descriptor)}
+
+	registry.descriptors["print"] = &Descriptor{
+		Name:       "print",
+		ReturnType: types.Nil,
+		Overloads: []Signature{
+			{Name: "naviary_print_i64", ParamTypes: []types.Type{types.Int}},
+			{Name: "naviary_print_f64", ParamTypes: []types.Type{types.Float}},
+			{Name: "naviary_print_bool", ParamTypes: []types.Type{types.Bool}},
+			{Name: "naviary_print_str", ParamTypes: []types.Type{types.String}},
+		},
+	}
+
+	return registry
+}
+
+// Resolve looks up name's Descriptor and, if found, the Overload whose
+// ParamTypes exactly match argTypes. It returns a nil Descriptor when name
+// isn't a known builtin, and a non-nil Descriptor with a nil *Signature
+// when name is known but no overload matches argTypes (e.g. print called
+// with an unsupported type or wrong argument count).
+func (registry *Registry) Resolve(name string, argTypes []types.Type) (*Descriptor, *Signature) {
+	descriptor, ok := registry.descriptors[name]
+	if !ok {
+		return nil, nil
+	}
+
+	for i := range descriptor.Overloads {
+		if paramTypesMatch(descriptor.Overloads[i].ParamTypes, argTypes) {
+			return descriptor, &descriptor.Overloads[i]
+		}
+	}
+
+	return descriptor, nil
+}
+
+// Declare returns the LLVM function module exposes for signature,
+// declaring it first if this is the first call for module - the same
+// lazy-declare-on-first-use convention runtime.StringConcat follows.
+// descriptor.ReturnType/signature.ParamTypes are converted to llvm.Type by
+// hand rather than via the parent package's TypeConverter, since
+// TypeConverter lives in codegen/llvm and that package already imports
+// this one (see instruction.go's lookupRuntimeFunction) - importing it
+// back here would cycle.
+func Declare(context llvm.Context, module llvm.Module, descriptor *Descriptor, signature *Signature) (llvm.Value, error) {
+	if function := module.NamedFunction(signature.Name); !function.IsNil() {
+		return function, nil
+	}
+
+	paramTypes := make([]llvm.Type, len(signature.ParamTypes))
+	for i, paramType := range signature.ParamTypes {
+		llvmType, err := primitiveLLVMType(context, paramType)
+		if err != nil {
+			return llvm.Value{}, err
+		}
+		paramTypes[i] = llvmType
+	}
+
+	returnType, err := primitiveLLVMType(context, descriptor.ReturnType)
+	if err != nil {
+		return llvm.Value{}, err
+	}
+
+	functionType := llvm.FunctionType(returnType, paramTypes, descriptor.Variadic)
+	return llvm.AddFunction(module, signature.Name, functionType), nil
+}
+
+// primitiveLLVMType converts one of the scalar types a builtin signature
+// can use (int/float/bool/string, or nil for void) to its LLVM type.
+func primitiveLLVMType(context llvm.Context, t types.Type) (llvm.Type, error) {
+	if t == types.Nil {
+		return context.VoidType(), nil
+	}
+
+	primitive, ok := t.(*types.PrimitiveType)
+	if !ok {
+		return llvm.Type{}, fmt.Errorf("runtime: unsupported builtin type %s", t.String())
+	}
+
+	switch primitive.Name {
+	case "int", "i64", "u64":
+		return context.Int64Type(), nil
+	case "float", "f64":
+		return context.DoubleType(), nil
+	case "bool":
+		return context.Int1Type(), nil
+	case "string":
+		return llvm.PointerType(context.Int8Type(), 0), nil
+	default:
+		return llvm.Type{}, fmt.Errorf("runtime: unsupported builtin primitive type %s", primitive.Name)
+	}
+}
+
+func paramTypesMatch(paramTypes []types.Type, argTypes []types.Type) bool {
+	if len(paramTypes) != len(argTypes) {
+		return false
+	}
+	for i, paramType := range paramTypes {
+		if !paramType.Equals(argTypes[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,27 @@
+// Package runtime declares the small set of C-ABI helper functions the
+// LLVM backend calls into for operations NIR has no instruction for (e.g.
+// string concatenation via `naviary_string_concat`). Each is declared
+// lazily on the target module the first time a lowering needs it, rather
+// than up front on every module.
+package runtime
+
+import "tinygo.org/x/go-llvm"
+
+// StringConcatName is the symbol StringConcat declares, and the function
+// name nir's string-typed `+` lowering calls by.
+const StringConcatName = "naviary_string_concat"
+
+// StringConcat returns the `naviary_string_concat(i8*, i8*) i8*`
+// declaration on module, declaring it first if this is the first call
+// for module. The actual definition is linked in from the runtime
+// library at build time, same as any other extern function.
+func StringConcat(context llvm.Context, module llvm.Module) llvm.Value {
+	if function := module.NamedFunction(StringConcatName); !function.IsNil() {
+		return function
+	}
+
+	stringType := llvm.PointerType(context.Int8Type(), 0)
+	functionType := llvm.FunctionType(stringType, []llvm.Type{stringType, stringType}, false)
+
+	return llvm.AddFunction(module, StringConcatName, functionType)
+}
@@ -29,6 +29,12 @@ func (converter *TypeConverter) Convert(naviaryType types.Type) (llvm.Type, erro
 		return converter.convertNilType(t)
 	case *types.FunctionType:
 		return converter.convertFunctionType(t)
+	case *types.TupleType:
+		return converter.convertTupleType(t)
+	case *types.ClassType:
+		return converter.convertClassType(t)
+	case *types.StructType:
+		return converter.convertStructType(t)
 	default:
 		return llvm.Type{}, fmt.Errorf("unsupported type: %s", naviaryType.String())
 	}
@@ -37,10 +43,18 @@ func (converter *TypeConverter) Convert(naviaryType types.Type) (llvm.Type, erro
 func (converter *TypeConverter) convertPrimitiveType(primitiveType *types.PrimitiveType) (llvm.Type, error) {
 	context := converter.context.GetRawContext()
 	switch primitiveType.Name {
-	case "int":
+	case "int", "i64", "u64":
 		return context.Int64Type(), nil
-	case "float":
+	case "i8", "u8":
+		return context.Int8Type(), nil
+	case "i16", "u16":
+		return context.Int16Type(), nil
+	case "i32", "u32":
+		return context.Int32Type(), nil
+	case "float", "f64":
 		return context.DoubleType(), nil
+	case "f32":
+		return context.FloatType(), nil
 	case "string":
 		return llvm.PointerType(context.Int8Type(), 0), nil
 	case "bool":
@@ -54,6 +68,68 @@ func (converter *TypeConverter) convertNilType(nilType *types.NilType) (llvm.Typ
 	return converter.context.GetRawContext().VoidType(), nil
 }
 
+// convertTupleType lowers a multi-value return type to an anonymous LLVM
+// struct, one field per element in declaration order. Naviary has no
+// tuple values anywhere else, so this only ever shows up as a function's
+// return type.
+func (converter *TypeConverter) convertTupleType(tupleType *types.TupleType) (llvm.Type, error) {
+	elementTypes := make([]llvm.Type, len(tupleType.Elements))
+
+	for i, element := range tupleType.Elements {
+		llvmType, err := converter.Convert(element)
+		if err != nil {
+			return llvm.Type{}, fmt.Errorf("failed to convert tuple element %d: %w", i, err)
+		}
+		elementTypes[i] = llvmType
+	}
+
+	return converter.context.GetRawContext().StructType(elementTypes, false), nil
+}
+
+// convertClassType lowers a user-defined class (see ast.ClassStatement) to
+// an LLVM struct, one field per types.ClassType.Fields in declaration
+// order, so GEPInstruction's CreateStructGEP can address a field by the
+// same index FieldIndex returns.
+func (converter *TypeConverter) convertClassType(classType *types.ClassType) (llvm.Type, error) {
+	elementTypes := make([]llvm.Type, len(classType.Fields))
+
+	for i, field := range classType.Fields {
+		llvmType, err := converter.Convert(field.Type)
+		if err != nil {
+			return llvm.Type{}, fmt.Errorf("failed to convert field %s: %w", field.Name, err)
+		}
+		elementTypes[i] = llvmType
+	}
+
+	return converter.context.GetRawContext().StructType(elementTypes, false), nil
+}
+
+// convertStructType lowers a user-defined struct (see ast.StructStatement)
+// to a named LLVM struct, cached on Context by name so every reference to
+// the same struct converts to the same llvm.Type rather than a fresh
+// structurally-equal one each time, one field per types.StructType.Fields
+// in declaration order so a GEPInstruction's CreateStructGEP can address
+// a field by the same index FieldIndex returns.
+func (converter *TypeConverter) convertStructType(structType *types.StructType) (llvm.Type, error) {
+	llvmStruct, alreadyDefined := converter.context.NamedStructType(structType.Name)
+	if alreadyDefined {
+		return llvmStruct, nil
+	}
+
+	elementTypes := make([]llvm.Type, len(structType.Fields))
+	for i, field := range structType.Fields {
+		llvmType, err := converter.Convert(field.Type)
+		if err != nil {
+			return llvm.Type{}, fmt.Errorf("failed to convert field %s: %w", field.Name, err)
+		}
+		elementTypes[i] = llvmType
+	}
+
+	llvmStruct.StructSetBody(elementTypes, false)
+
+	return llvmStruct, nil
+}
+
 func (converter *TypeConverter) convertFunctionType(functionType *types.FunctionType) (llvm.Type, error) {
 	parameterTypes := make([]llvm.Type, len(functionType.ParameterTypes))
 
@@ -1,6 +1,7 @@
 package value
 
 import (
+	"compiler/constant"
 	nirvalue "compiler/nir/value"
 	"compiler/types"
 	"fmt"
@@ -22,102 +23,71 @@ func NewConstantConverter(typeConverter TypeConverter) *ConstantConverter {
 	}
 }
 
+// Convert emits naviaryConstant's exact value as an LLVM constant. It
+// switches on the constant.Value's own Kind rather than naviaryConstant's
+// declared type, so a sized type like i8 still gets ConstInt of the right
+// width - there's no longer a display string in between to lose precision
+// on a negative number, a NaN/Inf float, or an escaped string.
 func (converter *ConstantConverter) Convert(naviaryConstant *nirvalue.Constant) (llvm.Value, error) {
 	if naviaryConstant == nil {
 		return llvm.Value{}, fmt.Errorf("cannot convert nil constant")
 	}
 
-	switch naviaryConstant.Type() {
-	case types.Int:
+	switch naviaryConstant.Value().Kind() {
+	case constant.Int:
 		return converter.convertIntConstant(naviaryConstant)
-	case types.String:
-		return converter.convertStringConstant(naviaryConstant)
-	case types.Float:
+	case constant.Float:
 		return converter.convertFloatConstant(naviaryConstant)
-	case types.Bool:
+	case constant.String:
+		return converter.convertStringConstant(naviaryConstant)
+	case constant.Bool:
 		return converter.convertBoolConstant(naviaryConstant)
 	default:
 		return llvm.Value{}, fmt.Errorf("unsupported constant type: %s", naviaryConstant.Type().String())
-
 	}
 }
 
 func (converter *ConstantConverter) convertIntConstant(naviaryConstant *nirvalue.Constant) (llvm.Value, error) {
-	llvmType, err := converter.typeConverter.Convert(types.Int)
-
+	llvmType, err := converter.typeConverter.Convert(naviaryConstant.Type())
 	if err != nil {
 		return llvm.Value{}, fmt.Errorf("failed to convert int type: %w", err)
 	}
 
-	constantString := naviaryConstant.String()
-	var value int64
-	_, err = fmt.Sscanf(constantString, "Constant(%d)", &value)
-	if err != nil {
-		return llvm.Value{}, fmt.Errorf("failed to parse integer constant: %w", err)
+	intValue, ok := constant.Int64Val(naviaryConstant.Value())
+	if !ok {
+		return llvm.Value{}, fmt.Errorf("constant %s does not fit in an integer", naviaryConstant.Value().String())
 	}
 
-	return llvm.ConstInt(llvmType, uint64(value), false), nil
+	return llvm.ConstInt(llvmType, uint64(intValue), true), nil
 }
 
 func (converter *ConstantConverter) convertFloatConstant(naviaryConstant *nirvalue.Constant) (llvm.Value, error) {
-	llvmType, err := converter.typeConverter.Convert(types.Float)
-
+	llvmType, err := converter.typeConverter.Convert(naviaryConstant.Type())
 	if err != nil {
 		return llvm.Value{}, fmt.Errorf("failed to convert float type: %w", err)
 	}
 
-	constantString := naviaryConstant.String()
-	var value float64
-	_, err = fmt.Sscanf(constantString, "Constant(%f)", &value)
-	if err != nil {
-		return llvm.Value{}, fmt.Errorf("failed to parse float constant: %w", err)
+	floatValue, ok := constant.Float64Val(naviaryConstant.Value())
+	if !ok {
+		return llvm.Value{}, fmt.Errorf("constant %s is not a float", naviaryConstant.Value().String())
 	}
 
-	return llvm.ConstFloat(llvmType, value), nil
+	return llvm.ConstFloat(llvmType, floatValue), nil
 }
 
 func (converter *ConstantConverter) convertStringConstant(naviaryConstant *nirvalue.Constant) (llvm.Value, error) {
-	constantString := naviaryConstant.String()
-
-	var value string
-	_, err := fmt.Sscanf(constantString, "Constant(\"%s\")", &value)
-	if err != nil {
-		value = converter.extractStringValue(constantString)
-	}
-
-	return llvm.ConstString(value, false), nil
-}
-
-func (converter *ConstantConverter) extractStringValue(constantString string) string {
-	startIndex := len("Constant(\"")
-	endIndex := len(constantString) - 2 // Remove "))
-
-	if startIndex >= len(constantString) || endIndex <= startIndex {
-		return ""
-	}
-
-	return constantString[startIndex:endIndex]
+	return llvm.ConstString(constant.StringVal(naviaryConstant.Value()), false), nil
 }
 
 func (converter *ConstantConverter) convertBoolConstant(naviaryConstant *nirvalue.Constant) (llvm.Value, error) {
 	llvmType, err := converter.typeConverter.Convert(types.Bool)
-
 	if err != nil {
 		return llvm.Value{}, fmt.Errorf("failed to convert bool type: %w", err)
 	}
 
-	constantString := naviaryConstant.String()
-	var value bool
-	_, err = fmt.Sscanf(constantString, "Constant(%t)", &value)
-	if err != nil {
-		return llvm.Value{}, fmt.Errorf("failed to parse bool constant: %w", err)
-	}
-
 	var intValue uint64
-	if value {
+	if constant.BoolVal(naviaryConstant.Value()) {
 		intValue = 1
-	} else {
-		intValue = 0
 	}
 
 	return llvm.ConstInt(llvmType, intValue, false), nil
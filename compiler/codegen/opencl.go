@@ -0,0 +1,213 @@
+package codegen
+
+import (
+	"compiler/ast"
+	"compiler/errors"
+)
+
+// OpenCLGenerator emits OpenCL C kernel source for every function
+// declared `kernel` in the AST. Non-kernel (host-side) functions are not
+// part of a .cl source file, so they're skipped.
+type OpenCLGenerator struct {
+	emitter        *Emitter
+	errorCollector *errors.ErrorCollector
+}
+
+func NewOpenCLGenerator(errorCollector *errors.ErrorCollector) *OpenCLGenerator {
+	return &OpenCLGenerator{
+		emitter:        NewEmitter(),
+		errorCollector: errorCollector,
+	}
+}
+
+func (generator *OpenCLGenerator) Generate(program *ast.Program) string {
+	kernels := collectKernelFunctions(program)
+
+	generator.emitExtensionPragmas(kernels)
+
+	for _, kernel := range kernels {
+		generator.generateKernel(kernel)
+		generator.emitter.EmitNewLine()
+	}
+
+	return generator.emitter.GetOutput()
+}
+
+// emitExtensionPragmas prepends `#pragma OPENCL EXTENSION` lines for every
+// capability any kernel in the module needs. OpenCL requires cl_khr_fp64
+// to be unlocked explicitly before double-precision types may be used.
+func (generator *OpenCLGenerator) emitExtensionPragmas(kernels []*ast.FunctionStatement) {
+	for _, kernel := range kernels {
+		if requiresFloat64(kernel) {
+			generator.emitter.EmitLine("#pragma OPENCL EXTENSION cl_khr_fp64 : enable")
+			generator.emitter.EmitNewLine()
+			return
+		}
+	}
+}
+
+func (generator *OpenCLGenerator) generateKernel(function *ast.FunctionStatement) {
+	generator.emitter.Emit("__kernel void %s(", function.Name.Value)
+
+	for i, param := range function.Parameters {
+		if i > 0 {
+			generator.emitter.Emit(", ")
+		}
+		generator.emitter.Emit("%s%s %s", memorySpaceQualifierOpenCL(param.MemorySpace), openCLType(param.Type.Value), param.Name.Value)
+	}
+
+	generator.emitter.EmitLine(") {")
+	generator.emitter.IncreaseIndent()
+
+	for _, statement := range function.Body.Statements {
+		generator.generateStatement(statement)
+	}
+
+	generator.emitter.DecreaseIndent()
+	generator.emitter.EmitLine("}")
+}
+
+// memorySpaceQualifierOpenCL maps a Naviary kernel parameter qualifier to
+// the OpenCL address-space qualifier prefix for a pointer parameter.
+// "private" (the default, unqualified) parameters get no prefix: OpenCL
+// treats unqualified pointer parameters as __private by default anyway.
+func memorySpaceQualifierOpenCL(memorySpace string) string {
+	switch memorySpace {
+	case "global":
+		return "__global "
+	case "local":
+		return "__local "
+	case "shared":
+		return "__local "
+	default:
+		return ""
+	}
+}
+
+func openCLType(typeName string) string {
+	switch typeName {
+	case "int":
+		return "int*"
+	case "float", "float32":
+		return "float*"
+	case "float64", "double":
+		return "double*"
+	case "bool":
+		return "bool*"
+	default:
+		return typeName + "*"
+	}
+}
+
+func (generator *OpenCLGenerator) generateStatement(statement ast.Statement) {
+	switch stmt := statement.(type) {
+	case *ast.ReturnStatement:
+		generator.generateReturnStatement(stmt)
+	case *ast.LetStatement:
+		generator.generateLetStatement(stmt)
+	case *ast.ExpressionStatement:
+		generator.generateExpression(stmt.Expression)
+		generator.emitter.EmitLine(";")
+	default:
+		generator.errorCollector.Add(
+			errors.SyntaxError,
+			0, 0, 0,
+			"OpenCLGenerator: unsupported statement type %T",
+			stmt,
+		)
+	}
+}
+
+func (generator *OpenCLGenerator) generateReturnStatement(returnStmt *ast.ReturnStatement) {
+	generator.emitter.Emit("return")
+	if returnStmt.ReturnValue != nil {
+		generator.emitter.Emit(" ")
+		generator.generateExpression(returnStmt.ReturnValue)
+	}
+	generator.emitter.EmitLine(";")
+}
+
+func (generator *OpenCLGenerator) generateLetStatement(let *ast.LetStatement) {
+	typeName := ""
+	if let.TypeAnnotation != nil {
+		typeName = let.TypeAnnotation.Value
+	}
+
+	generator.emitter.Emit("%s %s = ", openCLScalarType(typeName), let.Name.Value)
+	generator.generateExpression(let.Value)
+	generator.emitter.EmitLine(";")
+}
+
+// openCLScalarType is like openCLType but for a `let` local variable,
+// which (unlike a kernel parameter) holds a value rather than a pointer.
+func openCLScalarType(typeName string) string {
+	switch typeName {
+	case "float", "float32":
+		return "float"
+	case "float64", "double":
+		return "double"
+	case "bool":
+		return "bool"
+	case "":
+		return "int"
+	default:
+		return typeName
+	}
+}
+
+func (generator *OpenCLGenerator) generateExpression(expression ast.Expression) {
+	switch expr := expression.(type) {
+	case *ast.IntegerLiteral:
+		generator.emitter.Emit(expr.Value)
+	case *ast.FloatLiteral:
+		generator.emitter.Emit(expr.Value)
+	case *ast.Identifier:
+		generator.emitter.Emit(expr.Value)
+	case *ast.BinaryExpression:
+		generator.generateExpression(expr.Left)
+		generator.emitter.Emit(" %s ", expr.Operator)
+		generator.generateExpression(expr.Right)
+	case *ast.CallExpression:
+		generator.generateCallExpression(expr)
+	default:
+		generator.errorCollector.Add(
+			errors.SyntaxError,
+			0, 0, 0,
+			"OpenCLGenerator: unsupported expression type %T",
+			expr,
+		)
+	}
+}
+
+func (generator *OpenCLGenerator) generateCallExpression(call *ast.CallExpression) {
+	if identifier, ok := call.Function.(*ast.Identifier); ok {
+		if intrinsic, isIntrinsic := threadIndexIntrinsics[identifier.Value]; isIntrinsic {
+			generator.emitter.Emit(intrinsic.openCL)
+			return
+		}
+	}
+
+	generator.generateExpression(call.Function)
+	generator.emitter.Emit("(")
+	for i, argument := range call.Arguments {
+		if i > 0 {
+			generator.emitter.Emit(", ")
+		}
+		generator.generateExpression(argument)
+	}
+	generator.emitter.Emit(")")
+}
+
+// collectKernelFunctions returns every top-level function declared
+// `kernel`, in source order.
+func collectKernelFunctions(program *ast.Program) []*ast.FunctionStatement {
+	var kernels []*ast.FunctionStatement
+
+	for _, statement := range program.Statements {
+		if function, ok := statement.(*ast.FunctionStatement); ok && function.Kernel {
+			kernels = append(kernels, function)
+		}
+	}
+
+	return kernels
+}
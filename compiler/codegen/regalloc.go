@@ -0,0 +1,229 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+)
+
+// LiveInterval is the span of instruction positions over which a virtual
+// register is live, from the earliest of its uses/def to the latest.
+type LiveInterval struct {
+	Vreg  int
+	Start int
+	End   int
+}
+
+// computeIntervals derives one LiveInterval per virtual register from
+// liveness, widening [Start, End] to cover every position where the
+// register is live-in, live-out, or directly read/written.
+func computeIntervals(instructions []Instruction, liveness *Liveness, numVirtual int) []LiveInterval {
+	intervals := make([]LiveInterval, numVirtual)
+	for v := range intervals {
+		intervals[v] = LiveInterval{Vreg: v, Start: -1, End: -1}
+	}
+
+	touch := func(v, position int) {
+		interval := &intervals[v]
+		if interval.Start == -1 || position < interval.Start {
+			interval.Start = position
+		}
+		if position > interval.End {
+			interval.End = position
+		}
+	}
+
+	for blockIndex, b := range liveness.blocks {
+		liveness.liveIn[blockIndex].Iter(func(v int) { touch(v, b.start) })
+		end := b.start + len(b.instructions) - 1
+		liveness.liveOut[blockIndex].Iter(func(v int) { touch(v, end) })
+	}
+
+	for position, inst := range instructions {
+		def, uses := useDef(inst)
+		if def != nil {
+			touch(*def, position)
+		}
+		for _, v := range uses {
+			touch(v, position)
+		}
+	}
+
+	live := intervals[:0]
+	for _, interval := range intervals {
+		if interval.Start != -1 {
+			live = append(live, interval)
+		}
+	}
+
+	sort.Slice(live, func(i, j int) bool { return live[i].Start < live[j].Start })
+
+	return live
+}
+
+// spillSlot records where a spilled virtual register's value lives on the
+// stack, FramePointer-relative like every other local.
+type spillSlot struct {
+	offset int64
+}
+
+// Allocate lowers instructions' VirtualRegister operands to physical
+// Registers using linear-scan (Poletto & Sarkar): live intervals are
+// processed in start order against an active set sorted by end point,
+// handing out one of numPhysical general-purpose registers
+// (base..base+numPhysical-1) per interval still active, and spilling the
+// interval whose end is furthest away when none are free. A spilled
+// register is materialized into a scratch register - the one physical
+// register just past the numPhysical handed out, i.e. base+numPhysical -
+// with a Load before each use and a Store after each def, annotated with
+// a MakeComment explaining the decision. It also returns the byte size
+// of the stack frame those spill slots need, 16-byte aligned and ready
+// to hand to an emitter's AdjustStack; callers with no spills get 0.
+func Allocate(instructions []Instruction, numVirtual, numPhysical int, base Register) ([]Instruction, int64) {
+	liveness := ComputeLiveness(instructions, numVirtual)
+	intervals := computeIntervals(instructions, liveness, numVirtual)
+
+	assignment := make(map[int]Register)   // vreg -> physical register, while active
+	spills := make(map[int]*spillSlot)     // vreg -> stack slot, once spilled
+	nextSpillOffset := int64(-16)          // grows downward past the saved fp/lr pair
+
+	var active []LiveInterval
+	freeRegisters := make([]Register, numPhysical)
+	for i := range freeRegisters {
+		freeRegisters[i] = base + Register(i)
+	}
+
+	annotations := make(map[int][]string) // position -> comments to emit just before it
+
+	expireOld := func(current LiveInterval) {
+		kept := active[:0]
+		for _, interval := range active {
+			if interval.End < current.Start {
+				freeRegisters = append(freeRegisters, assignment[interval.Vreg])
+				delete(assignment, interval.Vreg)
+			} else {
+				kept = append(kept, interval)
+			}
+		}
+		active = kept
+		sort.Slice(active, func(i, j int) bool { return active[i].End < active[j].End })
+	}
+
+	for _, interval := range intervals {
+		expireOld(interval)
+
+		if len(freeRegisters) > 0 {
+			register := freeRegisters[len(freeRegisters)-1]
+			freeRegisters = freeRegisters[:len(freeRegisters)-1]
+			assignment[interval.Vreg] = register
+			active = append(active, interval)
+			sort.Slice(active, func(i, j int) bool { return active[i].End < active[j].End })
+			annotations[interval.Start] = append(annotations[interval.Start],
+				comment("v%d -> %s", interval.Vreg, registerName(register)))
+			continue
+		}
+
+		// No free register: spill whichever active interval ends
+		// furthest in the future, since it has the most to gain from
+		// staying in a register and the least to lose from waiting.
+		furthest := active[len(active)-1]
+		if furthest.End > interval.End {
+			register := assignment[furthest.Vreg]
+			delete(assignment, furthest.Vreg)
+			active = active[:len(active)-1]
+
+			spills[furthest.Vreg] = &spillSlot{offset: nextSpillOffset}
+			nextSpillOffset -= 8
+			annotations[interval.Start] = append(annotations[interval.Start],
+				comment("spill v%d to free %s for v%d", furthest.Vreg, registerName(register), interval.Vreg))
+
+			assignment[interval.Vreg] = register
+			active = append(active, interval)
+			sort.Slice(active, func(i, j int) bool { return active[i].End < active[j].End })
+		} else {
+			spills[interval.Vreg] = &spillSlot{offset: nextSpillOffset}
+			nextSpillOffset -= 8
+			annotations[interval.Start] = append(annotations[interval.Start],
+				comment("v%d spilled at definition (no free register)", interval.Vreg))
+		}
+	}
+
+	frameSize := int64(0)
+	if len(spills) > 0 {
+		frameSize = align16(8*int64(len(spills)) + 8)
+	}
+
+	return rewriteOperands(instructions, assignment, spills, annotations, base, numPhysical), frameSize
+}
+
+// align16 rounds n up to the next multiple of 16, the stack alignment
+// every target here requires.
+func align16(n int64) int64 {
+	return (n + 15) &^ 15
+}
+
+// rewriteOperands walks instructions a second time, substituting the
+// final register/spill-slot assignment for every VirtualRegister operand
+// and inserting the Load/Store pairs a spilled register needs around
+// each use.
+func rewriteOperands(instructions []Instruction, assignment map[int]Register, spills map[int]*spillSlot, annotations map[int][]string, base Register, numPhysical int) []Instruction {
+	scratch := base + Register(numPhysical) // one register reserved for spill traffic
+
+	result := make([]Instruction, 0, len(instructions))
+	for position, inst := range instructions {
+		for _, text := range annotations[position] {
+			result = append(result, MakeComment(text))
+		}
+
+		def, uses := useDef(inst)
+		rewritten := inst
+		rewritten.Operands = append([]Operand(nil), inst.Operands...)
+
+		for _, v := range uses {
+			if slot, spilled := spills[v]; spilled {
+				result = append(result, LoadFromMemory(scratch, FramePointer, slot.offset))
+				substituteOperand(rewritten.Operands, v, scratch)
+			} else {
+				substituteOperand(rewritten.Operands, v, assignment[v])
+			}
+		}
+
+		if def != nil {
+			if _, spilled := spills[*def]; spilled {
+				substituteOperand(rewritten.Operands, *def, scratch)
+			} else {
+				substituteOperand(rewritten.Operands, *def, assignment[*def])
+			}
+		}
+
+		result = append(result, rewritten)
+
+		if def != nil {
+			if slot, spilled := spills[*def]; spilled {
+				result = append(result, StoreToMemory(scratch, FramePointer, slot.offset))
+			}
+		}
+	}
+
+	return result
+}
+
+func substituteOperand(operands []Operand, vreg int, register Register) {
+	for i, operand := range operands {
+		if v, ok := operand.(VirtualRegister); ok && int(v) == vreg {
+			operands[i] = register
+		}
+	}
+}
+
+func registerName(register Register) string {
+	names := []string{"r0", "r1", "r2", "r3", "r4", "r5", "r6", "r7", "sp", "fp", "lr"}
+	if int(register) < len(names) {
+		return names[register]
+	}
+	// Register8 and up is the ARM64 allocator's x9-x15 scratch range.
+	return fmt.Sprintf("x%d", int(register)+1)
+}
+
+func comment(format string, args ...interface{}) string {
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,27 @@
+package constant
+
+// Kind identifies which concrete representation a Value holds.
+type Kind int
+
+const (
+	Unknown Kind = iota
+	Bool
+	Int
+	Float
+	String
+)
+
+func (kind Kind) String() string {
+	switch kind {
+	case Bool:
+		return "bool"
+	case Int:
+		return "int"
+	case Float:
+		return "float"
+	case String:
+		return "string"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,161 @@
+package constant
+
+import (
+	"compiler/token"
+	"math/big"
+)
+
+// BinaryOp evaluates x op y at compile time, widening to Float if either
+// operand is Float, and returns Unknown if op isn't defined for x and y's
+// kinds (including division/modulo by zero - the caller should leave the
+// runtime instruction in place rather than fold it away).
+func BinaryOp(x Value, op token.TokenType, y Value) Value {
+	if x.Kind() == String && y.Kind() == String {
+		if op != token.PLUS {
+			return MakeUnknown()
+		}
+		return MakeString(StringVal(x) + StringVal(y))
+	}
+
+	if x.Kind() == Float || y.Kind() == Float {
+		xRat, xOk := toRat(x)
+		yRat, yOk := toRat(y)
+		if !xOk || !yOk {
+			return MakeUnknown()
+		}
+		return binaryFloat(xRat, op, yRat)
+	}
+
+	xInt, xOk := x.(intVal)
+	yInt, yOk := y.(intVal)
+	if !xOk || !yOk {
+		return MakeUnknown()
+	}
+	return binaryInt(xInt.val, op, yInt.val)
+}
+
+func binaryInt(x *big.Int, op token.TokenType, y *big.Int) Value {
+	result := new(big.Int)
+	switch op {
+	case token.PLUS:
+		result.Add(x, y)
+	case token.MINUS:
+		result.Sub(x, y)
+	case token.ASTERISK:
+		result.Mul(x, y)
+	case token.SLASH:
+		if y.Sign() == 0 {
+			return MakeUnknown()
+		}
+		result.Quo(x, y)
+	case token.PERCENT:
+		if y.Sign() == 0 {
+			return MakeUnknown()
+		}
+		result.Rem(x, y)
+	default:
+		return MakeUnknown()
+	}
+	return intVal{val: result}
+}
+
+func binaryFloat(x *big.Rat, op token.TokenType, y *big.Rat) Value {
+	result := new(big.Rat)
+	switch op {
+	case token.PLUS:
+		result.Add(x, y)
+	case token.MINUS:
+		result.Sub(x, y)
+	case token.ASTERISK:
+		result.Mul(x, y)
+	case token.SLASH:
+		if y.Sign() == 0 {
+			return MakeUnknown()
+		}
+		result.Quo(x, y)
+	default:
+		return MakeUnknown()
+	}
+	return floatVal{val: result}
+}
+
+func toRat(value Value) (*big.Rat, bool) {
+	switch v := value.(type) {
+	case floatVal:
+		return v.val, true
+	case intVal:
+		return new(big.Rat).SetInt(v.val), true
+	default:
+		return nil, false
+	}
+}
+
+// UnaryOp evaluates op x (MINUS negation or LOGICAL_NOT) at compile time,
+// returning Unknown if op doesn't apply to x's kind.
+func UnaryOp(op token.TokenType, x Value) Value {
+	switch v := x.(type) {
+	case intVal:
+		if op != token.MINUS {
+			return MakeUnknown()
+		}
+		return intVal{val: new(big.Int).Neg(v.val)}
+	case floatVal:
+		if op != token.MINUS {
+			return MakeUnknown()
+		}
+		return floatVal{val: new(big.Rat).Neg(v.val)}
+	case boolVal:
+		if op != token.LOGICAL_NOT {
+			return MakeUnknown()
+		}
+		return boolVal(!v)
+	default:
+		return MakeUnknown()
+	}
+}
+
+// Compare evaluates x op y (EQUAL, NOT_EQUAL, LESS_THAN, LESS_THAN_EQUAL,
+// GREATER_THAN, GREATER_THAN_EQUAL) and reports whether it holds. It
+// returns false if x and y aren't comparable (mismatched, non-numeric
+// kinds for an ordering comparison).
+func Compare(x Value, op token.TokenType, y Value) bool {
+	if x.Kind() == String && y.Kind() == String {
+		return compareOrdered(StringVal(x), op, StringVal(y))
+	}
+	if x.Kind() == Bool && y.Kind() == Bool {
+		switch op {
+		case token.EQUAL:
+			return BoolVal(x) == BoolVal(y)
+		case token.NOT_EQUAL:
+			return BoolVal(x) != BoolVal(y)
+		default:
+			return false
+		}
+	}
+
+	xRat, xOk := toRat(x)
+	yRat, yOk := toRat(y)
+	if !xOk || !yOk {
+		return false
+	}
+	return compareOrdered(xRat.Cmp(yRat), op, 0)
+}
+
+func compareOrdered[T int | string](x T, op token.TokenType, y T) bool {
+	switch op {
+	case token.EQUAL:
+		return x == y
+	case token.NOT_EQUAL:
+		return x != y
+	case token.LESS_THAN:
+		return x < y
+	case token.LESS_THAN_EQUAL:
+		return x <= y
+	case token.GREATER_THAN:
+		return x > y
+	case token.GREATER_THAN_EQUAL:
+		return x >= y
+	default:
+		return false
+	}
+}
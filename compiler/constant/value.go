@@ -0,0 +1,135 @@
+// Package constant represents compile-time constant values, modeled on
+// Go's go/constant: a single Value interface backed by arbitrary-precision
+// int/float representations so a literal never loses precision round-
+// tripping through a display string. It replaces the old approach of
+// storing a constant as a bare Go value (or worse, re-parsing its debug
+// string - see the Sscanf calls this package's callers used to do) with a
+// type that every phase from the typechecker down to the LLVM backend can
+// fold, compare, and convert without caring how the literal was spelled.
+package constant
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Value is a single constant with a definite Kind. The zero value of no
+// implementation is valid - use Unknown for "no constant value available".
+type Value interface {
+	Kind() Kind
+	String() string
+}
+
+type unknownVal struct{}
+
+func (unknownVal) Kind() Kind     { return Unknown }
+func (unknownVal) String() string { return "unknown" }
+
+// MakeUnknown returns the Value for "no constant value available", e.g.
+// when MakeFromLiteral was given malformed text. Callers can keep treating
+// the expression as non-constant rather than checking for a nil Value.
+func MakeUnknown() Value {
+	return unknownVal{}
+}
+
+type boolVal bool
+
+func (b boolVal) Kind() Kind     { return Bool }
+func (b boolVal) String() string { return fmt.Sprintf("%t", bool(b)) }
+
+type intVal struct{ val *big.Int }
+
+func (i intVal) Kind() Kind     { return Int }
+func (i intVal) String() string { return i.val.String() }
+
+type floatVal struct{ val *big.Rat }
+
+func (f floatVal) Kind() Kind     { return Float }
+func (f floatVal) String() string { return f.val.FloatString(6) }
+
+type stringVal string
+
+func (s stringVal) Kind() Kind     { return String }
+func (s stringVal) String() string { return fmt.Sprintf("%q", string(s)) }
+
+func MakeBool(value bool) Value {
+	return boolVal(value)
+}
+
+func MakeInt64(value int64) Value {
+	return intVal{val: big.NewInt(value)}
+}
+
+func MakeString(value string) Value {
+	return stringVal(value)
+}
+
+// MakeFromLiteral parses the raw source text of an int or float literal -
+// as scanned by the lexer, so it may contain `_` digit separators and a
+// 0x/0b/0o prefix - into a Value of the requested kind. It returns Unknown
+// rather than an error, matching how a malformed literal already reaches
+// this package: the lexer/parser report the syntax error, and this just
+// needs to not panic on the bad text.
+func MakeFromLiteral(text string, kind Kind) Value {
+	clean := strings.ReplaceAll(text, "_", "")
+
+	switch kind {
+	case Int:
+		parsed, ok := new(big.Int).SetString(clean, 0)
+		if !ok {
+			return MakeUnknown()
+		}
+		return intVal{val: parsed}
+	case Float:
+		parsed, ok := new(big.Rat).SetString(clean)
+		if !ok {
+			return MakeUnknown()
+		}
+		return floatVal{val: parsed}
+	default:
+		return MakeUnknown()
+	}
+}
+
+// BoolVal returns value's underlying bool, or false if value isn't Bool.
+func BoolVal(value Value) bool {
+	b, ok := value.(boolVal)
+	return ok && bool(b)
+}
+
+// Int64Val returns value's underlying integer and true, or (0, false) if
+// value isn't Int or doesn't fit in an int64. A narrower range, such as
+// i8's for `let x: i8 = 1000`, is not checked here: 1000 comes back as
+// (1000, true).
+func Int64Val(value Value) (int64, bool) {
+	i, ok := value.(intVal)
+	if !ok || !i.val.IsInt64() {
+		return 0, false
+	}
+	return i.val.Int64(), true
+}
+
+// Float64Val returns value as a float64, widening an Int value, or
+// (0, false) if value is neither Int nor Float.
+func Float64Val(value Value) (float64, bool) {
+	switch v := value.(type) {
+	case floatVal:
+		f, _ := v.val.Float64()
+		return f, true
+	case intVal:
+		f, _ := new(big.Float).SetInt(v.val).Float64()
+		return f, true
+	default:
+		return 0, false
+	}
+}
+
+// StringVal returns value's underlying string, or "" if value isn't String.
+func StringVal(value Value) string {
+	s, ok := value.(stringVal)
+	if !ok {
+		return ""
+	}
+	return string(s)
+}
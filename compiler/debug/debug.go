@@ -0,0 +1,83 @@
+// Package debug lets every compiler phase dump a human-readable artifact to
+// disk, gated by the `-dump=phase1,phase2` CLI flag. It's modeled after the
+// Go compiler's `-d=dumpfile=...` for `genssa`: each phase writes next to
+// the output file so regressions can be diffed phase by phase.
+package debug
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Dumper decides which phases should be written to disk and where.
+type Dumper struct {
+	phases     map[string]bool
+	all        bool
+	outputBase string // source file path without extension
+	funcFilter string // only dump artifacts for this function name, if set
+}
+
+// NewDumper parses a `-dump=phase1,phase2` (or `-dump=all`) flag value and
+// an optional `-dump-func=name` filter into a Dumper. outputBase is the
+// input source path with its extension stripped, used as the dump file
+// prefix (e.g. "hello" -> "hello.lex.dump").
+func NewDumper(dumpFlag string, funcFilter string, outputBase string) *Dumper {
+	dumper := &Dumper{
+		phases:     make(map[string]bool),
+		outputBase: outputBase,
+		funcFilter: funcFilter,
+	}
+
+	if dumpFlag == "" {
+		return dumper
+	}
+
+	for _, phase := range strings.Split(dumpFlag, ",") {
+		phase = strings.TrimSpace(phase)
+		if phase == "all" {
+			dumper.all = true
+			continue
+		}
+		dumper.phases[phase] = true
+	}
+
+	return dumper
+}
+
+// Enabled reports whether phase should be dumped.
+func (dumper *Dumper) Enabled(phase string) bool {
+	return dumper.all || dumper.phases[phase]
+}
+
+// FuncFilter returns the -dump-func name, or "" if every function should be
+// dumped.
+func (dumper *Dumper) FuncFilter() string {
+	return dumper.funcFilter
+}
+
+// DumpPhase writes artifact's textual representation to
+// "<outputBase>.<name>.dump" when phase name is enabled. artifact is
+// typically something with a String() method already used for debugging
+// (token lists, ast.Program, nir.Module, ...); fmt.Sprintf("%v", ...) is
+// used as a fallback for plain slices.
+func (dumper *Dumper) DumpPhase(name string, artifact any) error {
+	if !dumper.Enabled(name) {
+		return nil
+	}
+
+	path := fmt.Sprintf("%s.%s.dump", dumper.outputBase, name)
+
+	var content string
+	if stringer, ok := artifact.(fmt.Stringer); ok {
+		content = stringer.String()
+	} else {
+		content = fmt.Sprintf("%v", artifact)
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to dump phase %s: %w", name, err)
+	}
+
+	return nil
+}
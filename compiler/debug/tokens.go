@@ -0,0 +1,19 @@
+package debug
+
+import (
+	"compiler/token"
+	"fmt"
+	"strings"
+)
+
+// FormatTokens renders a token stream one token per line as
+// "Line:Column Type Literal", for the "lex" dump phase.
+func FormatTokens(tokens []token.Token) string {
+	var builder strings.Builder
+
+	for _, t := range tokens {
+		fmt.Fprintf(&builder, "%d:%d %s %q\n", t.Line, t.Column, t.Type.String(), t.Value)
+	}
+
+	return builder.String()
+}
@@ -12,6 +12,7 @@ const (
 	SyntaxError
 	TypeError
 	RuntimeError
+	CodegenError
 )
 
 var errorTypeMap = map[ErrorType]string{
@@ -19,6 +20,7 @@ var errorTypeMap = map[ErrorType]string{
 	SyntaxError:  "Syntax Error",
 	TypeError:    "Type Error",
 	RuntimeError: "Runtime Error",
+	CodegenError: "Codegen Error",
 }
 
 func (e ErrorType) String() string {
@@ -0,0 +1,132 @@
+// Command gen builds compiler/lexer/operator_table.go from operatorSpecs:
+// a trie over every operator/punctuation lexeme, compiled to the
+// transition/accept tables matchOperator walks in the generated file.
+// Run it with:
+//
+//	go run ./compiler/lexer/gen
+//
+// from the repository root whenever operatorSpecs changes.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// trieNode is one DFA state. Every node belongs to a lexer state (see
+// defaultLexerState) so a future spec entry naming a different State -
+// e.g. one entered after an opening `"` for string interpolation, or
+// inside a nested block comment - gets its own independent trie instead
+// of colliding with the default one; only defaultLexerState is populated
+// today.
+type trieNode struct {
+	id          int
+	lexerState  string
+	transitions map[byte]*trieNode
+	accept      string // token.TokenType identifier, or "" if not accepting
+}
+
+// defaultLexerState is the trie matchOperator starts walking from for an
+// ordinary token; see trieNode's doc comment.
+const defaultLexerState = "default"
+
+func newTrieNode(id int, lexerState string) *trieNode {
+	return &trieNode{id: id, lexerState: lexerState, transitions: make(map[byte]*trieNode)}
+}
+
+func main() {
+	// State 0 is reserved as operatorDeadState, so every real trie node
+	// (including each root) gets a nonzero id: that way the sparse
+	// [256]int literal emitted below can leave a byte unset to mean "no
+	// transition" and rely on Go's int zero value, instead of needing
+	// every one of the 256 entries spelled out per state.
+	roots := map[string]*trieNode{defaultLexerState: newTrieNode(1, defaultLexerState)}
+	nextID := 2
+
+	for _, spec := range operatorSpecs {
+		lexerState := spec.State
+		if lexerState == "" {
+			lexerState = defaultLexerState
+		}
+
+		root, ok := roots[lexerState]
+		if !ok {
+			root = newTrieNode(nextID, lexerState)
+			nextID++
+			roots[lexerState] = root
+		}
+
+		node := root
+		for i := 0; i < len(spec.Pattern); i++ {
+			b := spec.Pattern[i]
+			child, ok := node.transitions[b]
+			if !ok {
+				child = newTrieNode(nextID, lexerState)
+				nextID++
+				node.transitions[b] = child
+			}
+			node = child
+		}
+		node.accept = spec.TypeName
+	}
+
+	var nodes []*trieNode
+	for _, root := range roots {
+		collect(root, &nodes)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].id < nodes[j].id })
+
+	var buffer bytes.Buffer
+	buffer.WriteString("// Code generated by compiler/lexer/gen from operatorSpecs; DO NOT EDIT.\n\n")
+	buffer.WriteString("package lexer\n\n")
+	buffer.WriteString("import \"compiler/token\"\n\n")
+	buffer.WriteString("// operatorDeadState marks \"no transition\": matchOperator stops advancing\n")
+	buffer.WriteString("// and falls back to whatever it last accepted, if anything. It is the\n")
+	buffer.WriteString("// zero value so operatorTransitions' sparse [256]int literals don't need\n")
+	buffer.WriteString("// to list every non-matching byte.\n")
+	buffer.WriteString("const operatorDeadState = 0\n\n")
+	buffer.WriteString(fmt.Sprintf("const operatorStartState = %d\n\n", roots[defaultLexerState].id))
+
+	buffer.WriteString("// operatorTransitions[state][b] is the next state after consuming byte b\n")
+	buffer.WriteString("// from state, or operatorDeadState if no operator pattern continues there.\n")
+	buffer.WriteString("var operatorTransitions = map[int][256]int{\n")
+	for _, node := range nodes {
+		if len(node.transitions) == 0 {
+			continue
+		}
+		buffer.WriteString(fmt.Sprintf("\t%d: {\n", node.id))
+		for b := 0; b < 256; b++ {
+			child, ok := node.transitions[byte(b)]
+			if !ok {
+				continue
+			}
+			buffer.WriteString(fmt.Sprintf("\t\t%d: %d,\n", b, child.id))
+		}
+		buffer.WriteString("\t},\n")
+	}
+	buffer.WriteString("}\n\n")
+
+	buffer.WriteString("// operatorAccept[state] is the token produced by stopping at state, for\n")
+	buffer.WriteString("// every state reached by a complete operator pattern.\n")
+	buffer.WriteString("var operatorAccept = map[int]token.TokenType{\n")
+	for _, node := range nodes {
+		if node.accept != "" {
+			buffer.WriteString(fmt.Sprintf("\t%d: token.%s,\n", node.id, node.accept))
+		}
+	}
+	buffer.WriteString("}\n")
+
+	if err := os.WriteFile("compiler/lexer/operator_table.go", buffer.Bytes(), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func collect(node *trieNode, out *[]*trieNode) {
+	*out = append(*out, node)
+	for _, child := range node.transitions {
+		collect(child, out)
+	}
+}
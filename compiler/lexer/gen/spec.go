@@ -0,0 +1,56 @@
+package main
+
+// operatorSpec is one entry in the declarative table generate.go compiles
+// into a trie: Pattern is the literal lexeme, and TypeName is the Go
+// identifier (in package token) of the TokenType it produces when it's
+// the longest match at the head of the remaining input. Patterns sharing
+// a prefix (e.g. "=" and "==") merge into one trie path, so the longer
+// one naturally wins via maximal munch - see matchOperator in the
+// generated operator_table.go.
+//
+// Two constructs stay hand-written in Lexer.scanToken instead of living
+// here: the bare '/' that can introduce a line/block comment instead of
+// SLASH or SLASH_ASSIGN, and the ".."/"..." distinction, where an
+// unterminated ".." must raise its own two-character lexical error
+// rather than accept as DOT. Both need a custom error shape maximal
+// munch alone can't express. Every other operator is listed below.
+var operatorSpecs = []operatorSpec{
+	{Pattern: "==", TypeName: "EQUAL"},
+	{Pattern: "=", TypeName: "ASSIGN"},
+	{Pattern: "!=", TypeName: "NOT_EQUAL"},
+	{Pattern: "!", TypeName: "LOGICAL_NOT"},
+	{Pattern: "<=", TypeName: "LESS_THAN_EQUAL"},
+	{Pattern: "<", TypeName: "LESS_THAN"},
+	{Pattern: ">=", TypeName: "GREATER_THAN_EQUAL"},
+	{Pattern: ">", TypeName: "GREATER_THAN"},
+	{Pattern: "+=", TypeName: "PLUS_ASSIGN"},
+	{Pattern: "+", TypeName: "PLUS"},
+	{Pattern: "->", TypeName: "ARROW"},
+	{Pattern: "-=", TypeName: "MINUS_ASSIGN"},
+	{Pattern: "-", TypeName: "MINUS"},
+	{Pattern: "*=", TypeName: "ASTERISK_ASSIGN"},
+	{Pattern: "*", TypeName: "ASTERISK"},
+	{Pattern: "%=", TypeName: "PERCENT_ASSIGN"},
+	{Pattern: "%", TypeName: "PERCENT"},
+	{Pattern: "(", TypeName: "LEFT_PAREN"},
+	{Pattern: ")", TypeName: "RIGHT_PAREN"},
+	{Pattern: "{", TypeName: "LEFT_BRACE"},
+	{Pattern: "}", TypeName: "RIGHT_BRACE"},
+	{Pattern: ",", TypeName: "COMMA"},
+	{Pattern: ";", TypeName: "SEMICOLON"},
+	{Pattern: ":=", TypeName: "COLON_ASSIGN"},
+	{Pattern: ":", TypeName: "COLON"},
+	{Pattern: "&&", TypeName: "LOGICAL_AND"},
+	{Pattern: "||", TypeName: "LOGICAL_OR"},
+	{Pattern: "\n", TypeName: "NEW_LINE"},
+}
+
+// operatorSpec is one entry in the table; State names which lexer state
+// the pattern's trie belongs to (see defaultLexerState in generate.go)
+// and is left empty by every entry today since the whole table lexes
+// from the same state.
+type operatorSpec struct {
+	Pattern  string
+	TypeName string
+	State    string
+}
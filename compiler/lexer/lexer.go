@@ -3,6 +3,7 @@ package lexer
 import (
 	"compiler/errors"
 	"compiler/token"
+	"strings"
 )
 
 // Lexer tokenizes the input source code
@@ -15,6 +16,63 @@ type Lexer struct {
 	column       int
 	errors       *errors.ErrorCollector
 	fileName     string
+
+	// peeked holds tokens already scanned by Peek/PeekN but not yet
+	// consumed by NextToken, in order, so repeated peeks don't re-scan
+	// the source.
+	peeked []token.Token
+
+	// incremental marks a Lexer created by NewIncremental: it defers
+	// unterminated-string/char/block-comment errors instead of reporting
+	// them immediately, so a REPL can distinguish "this buffer just ends
+	// mid-construct" from a genuine lexical error.
+	incremental bool
+	needsMore   bool
+	openParens  int
+	openBraces  int
+}
+
+// NewIncremental creates a Lexer meant to be fed source incrementally by a
+// REPL: it starts with an empty buffer, and Reset appends to it and
+// re-scans from the start. Unlike New, unterminated strings, unterminated
+// block comments, and unbalanced parens/braces don't raise an error on
+// their own; call NeedsMoreInput after draining tokens to tell whether the
+// buffer ended inside one of those constructs and more source is needed.
+func NewIncremental(fileName string, errorCollector *errors.ErrorCollector) *Lexer {
+	lexer := &Lexer{
+		fileName:    fileName,
+		errors:      errorCollector,
+		incremental: true,
+	}
+	lexer.Reset("")
+	return lexer
+}
+
+// Reset appends additionalSource to the lexer's buffer and re-scans from
+// the beginning, clearing any pending peeked tokens and the
+// NeedsMoreInput state. Intended for incremental lexers: a REPL calls it
+// to feed the next line once NeedsMoreInput reports the statement isn't
+// finished yet.
+func (lexer *Lexer) Reset(additionalSource string) {
+	lexer.input += additionalSource
+	lexer.position = 0
+	lexer.readPosition = 0
+	lexer.currentChar = 0
+	lexer.line = 1
+	lexer.column = 0
+	lexer.peeked = nil
+	lexer.needsMore = false
+	lexer.openParens = 0
+	lexer.openBraces = 0
+	lexer.advance()
+}
+
+// NeedsMoreInput reports whether, after draining the lexer's tokens, the
+// buffer ended inside an open construct: an unterminated string or
+// character literal, an unclosed block comment, or an unbalanced '(' or
+// '{'. Only meaningful for a Lexer created by NewIncremental.
+func (lexer *Lexer) NeedsMoreInput() bool {
+	return lexer.needsMore || lexer.openParens > 0 || lexer.openBraces > 0
 }
 
 // New creates a new Lexer instance
@@ -30,8 +88,100 @@ func New(input string, fileName string, errorCollector *errors.ErrorCollector) *
 	return lexer
 }
 
-// NextToken returns the next token from the input
+// NextToken returns the next token from the input, consuming it.
 func (lexer *Lexer) NextToken() token.Token {
+	if len(lexer.peeked) > 0 {
+		t := lexer.peeked[0]
+		lexer.peeked = lexer.peeked[1:]
+		return t
+	}
+
+	return lexer.nextScannedToken()
+}
+
+// Peek returns the next token without consuming it: the following call
+// to NextToken returns an identical token. Equivalent to PeekN(1).
+func (lexer *Lexer) Peek() token.Token {
+	return lexer.PeekN(1)
+}
+
+// PeekN returns the token n positions ahead (1-indexed, so PeekN(1) is
+// the same token Peek returns) without consuming it or any token before
+// it. Already-peeked tokens are cached, so repeated peeks don't re-scan
+// the source. Peeking past EOF is idempotent: PeekN keeps returning the
+// same EOF token rather than scanning past it.
+func (lexer *Lexer) PeekN(n int) token.Token {
+	for len(lexer.peeked) < n {
+		next := lexer.nextScannedToken()
+		lexer.peeked = append(lexer.peeked, next)
+		if next.Type == token.EOF {
+			break
+		}
+	}
+
+	if n-1 < len(lexer.peeked) {
+		return lexer.peeked[n-1]
+	}
+
+	return lexer.peeked[len(lexer.peeked)-1]
+}
+
+// All consumes the rest of the input and returns every remaining token,
+// including the final EOF.
+func (lexer *Lexer) All() []token.Token {
+	var tokens []token.Token
+
+	for {
+		t := lexer.NextToken()
+		tokens = append(tokens, t)
+		if t.Type == token.EOF {
+			return tokens
+		}
+	}
+}
+
+// Tokens returns a range-over-func iterator (see the standard library's
+// iter.Seq shape) over the rest of the input's tokens, stopping after
+// yielding EOF or when the consumer returns false.
+func (lexer *Lexer) Tokens() func(yield func(token.Token) bool) {
+	return func(yield func(token.Token) bool) {
+		for {
+			t := lexer.NextToken()
+			if !yield(t) || t.Type == token.EOF {
+				return
+			}
+		}
+	}
+}
+
+// nextScannedToken scans the next token and, for an incremental lexer,
+// updates the open-paren/open-brace counters NeedsMoreInput relies on.
+func (lexer *Lexer) nextScannedToken() token.Token {
+	t := lexer.scanToken()
+
+	if lexer.incremental {
+		switch t.Type {
+		case token.LEFT_PAREN:
+			lexer.openParens++
+		case token.RIGHT_PAREN:
+			if lexer.openParens > 0 {
+				lexer.openParens--
+			}
+		case token.LEFT_BRACE:
+			lexer.openBraces++
+		case token.RIGHT_BRACE:
+			if lexer.openBraces > 0 {
+				lexer.openBraces--
+			}
+		}
+	}
+
+	return t
+}
+
+// scanToken scans and returns the next token from the input, without
+// consulting or touching the peek buffer.
+func (lexer *Lexer) scanToken() token.Token {
 	var t token.Token
 
 	lexer.skipWhitespace()
@@ -40,61 +190,67 @@ func (lexer *Lexer) NextToken() token.Token {
 	t.Line = lexer.line
 	t.Column = lexer.column
 
-	switch lexer.currentChar {
-	case '=':
-		t = token.New(token.ASSIGN, string(lexer.currentChar), lexer.line, lexer.column)
-		lexer.advance()
-	case '+':
-		t = token.New(token.PLUS, string(lexer.currentChar), lexer.line, lexer.column)
-		lexer.advance()
-	case '-':
-		if lexer.peek() == '>' {
-			startColumn := lexer.column
-			lexer.advance() // consume '-'
-			lexer.advance() // consume '>'
-			t = token.New(token.ARROW, "->", lexer.line, startColumn)
-		} else {
-			t = token.New(token.MINUS, string(lexer.currentChar), lexer.line, lexer.column)
+	// Most operator/punctuation tokens are recognized by matchOperator's
+	// generated trie (see operator_table.go); '/' and '.' stay hand-written
+	// below because they need lookahead matchOperator's plain maximal
+	// munch can't express - see operatorSpecs' doc comment for why.
+	if tokenType, length, ok := matchOperator(lexer.input[lexer.position:]); ok {
+		value := lexer.input[lexer.position : lexer.position+length]
+		for i := 0; i < length; i++ {
 			lexer.advance()
 		}
-	case '*':
-		t = token.New(token.ASTERISK, string(lexer.currentChar), lexer.line, lexer.column)
-		lexer.advance()
+		t.Type = tokenType
+		t.Value = value
+		return t
+	}
+
+	switch lexer.currentChar {
 	case '/':
-		t = token.New(token.SLASH, string(lexer.currentChar), lexer.line, lexer.column)
-		lexer.advance()
-	case '(':
-		t = token.New(token.LEFT_PAREN, string(lexer.currentChar), lexer.line, lexer.column)
-		lexer.advance()
-	case ')':
-		t = token.New(token.RIGHT_PAREN, string(lexer.currentChar), lexer.line, lexer.column)
-		lexer.advance()
-	case '{':
-		t = token.New(token.LEFT_BRACE, string(lexer.currentChar), lexer.line, lexer.column)
-		lexer.advance()
-	case '}':
-		t = token.New(token.RIGHT_BRACE, string(lexer.currentChar), lexer.line, lexer.column)
-		lexer.advance()
-	case ',':
-		t = token.New(token.COMMA, string(lexer.currentChar), lexer.line, lexer.column)
-		lexer.advance()
-	case ';':
-		t = token.New(token.SEMICOLON, string(lexer.currentChar), lexer.line, lexer.column)
-		lexer.advance()
-	case ':':
-		// Check for := (colon assign)
-		if lexer.peek() == '=' {
+		switch lexer.peek() {
+		case '/':
+			return lexer.readLineComment(t.Line, t.Column)
+		case '*':
+			return lexer.readBlockComment(t.Line, t.Column)
+		case '=':
 			startColumn := lexer.column
-			lexer.advance() // consume ':'
+			lexer.advance() // consume '/'
 			lexer.advance() // consume '='
-			t = token.New(token.COLON_ASSIGN, ":=", lexer.line, startColumn)
+			t = token.New(token.SLASH_ASSIGN, "/=", lexer.line, startColumn)
+		default:
+			t = token.New(token.SLASH, string(lexer.currentChar), lexer.line, lexer.column)
+			lexer.advance()
+		}
+	case '.':
+		if lexer.peek() == '.' {
+			startColumn := lexer.column
+			lexer.advance() // consume first '.'
+			lexer.advance() // consume second '.'
+			if lexer.currentChar == '.' {
+				lexer.advance() // consume third '.'
+				t = token.New(token.ELLIPSIS, "...", lexer.line, startColumn)
+			} else {
+				t = token.New(token.ILLEGAL, "..", lexer.line, startColumn)
+				lexer.errors.Add(
+					errors.LexicalError,
+					lexer.line,
+					startColumn,
+					2,
+					"Unexpected character: %s",
+					"..",
+				)
+			}
 		} else {
-			t = token.New(token.COLON, string(lexer.currentChar), lexer.line, lexer.column)
+			t = token.New(token.DOT, string(lexer.currentChar), lexer.line, lexer.column)
 			lexer.advance()
 		}
-	case '\n':
-		t = token.New(token.NEW_LINE, string(lexer.currentChar), lexer.line, lexer.column)
-		lexer.advance()
+	case '"':
+		t.Value = lexer.readStringLiteral()
+		t.Type = token.STRING_LITERAL
+		return t // readStringLiteral already advanced position
+	case '\'':
+		t.Value = lexer.readCharLiteral()
+		t.Type = token.CHAR_LITERAL
+		return t // readCharLiteral already advanced position
 	case 0:
 		t.Type = token.EOF
 		t.Value = ""
@@ -104,8 +260,7 @@ func (lexer *Lexer) NextToken() token.Token {
 			t.Type = token.LookupIdentifier(t.Value)
 			return t // readIdentifier already advanced position
 		} else if isDigit(lexer.currentChar) {
-			t.Value = lexer.readNumber()
-			t.Type = token.INT
+			t.Value, t.Type = lexer.readNumber()
 			return t // readNumber already advanced position
 		} else {
 			t = token.New(token.ILLEGAL, string(lexer.currentChar), lexer.line, lexer.column)
@@ -172,13 +327,52 @@ func (lexer *Lexer) skipWhitespace() {
 	}
 }
 
-// readNumber reads a number from the input
-func (lexer *Lexer) readNumber() string {
+// readNumber reads a numeric literal from the input: a plain or
+// underscore-separated decimal integer, a decimal float (with an
+// optional exponent), or a 0x/0b/0o-prefixed integer in another base.
+// The current char must be the literal's leading digit.
+func (lexer *Lexer) readNumber() (string, token.TokenType) {
 	startPosition := lexer.position
+	startLine := lexer.line
+	startColumn := lexer.column
 
-	// Read all consecutive digits
-	for isDigit(lexer.currentChar) {
-		lexer.advance()
+	if lexer.currentChar == '0' && (lexer.peek() == 'x' || lexer.peek() == 'X') {
+		return lexer.readPrefixedInteger(startPosition, startLine, startColumn, "hexadecimal", isHexDigit)
+	}
+	if lexer.currentChar == '0' && (lexer.peek() == 'b' || lexer.peek() == 'B') {
+		return lexer.readPrefixedInteger(startPosition, startLine, startColumn, "binary", isBinaryDigit)
+	}
+	if lexer.currentChar == '0' && (lexer.peek() == 'o' || lexer.peek() == 'O') {
+		return lexer.readPrefixedInteger(startPosition, startLine, startColumn, "octal", isOctalDigit)
+	}
+
+	return lexer.readDecimalNumber(startPosition, startLine, startColumn)
+}
+
+// readDecimalNumber reads a decimal integer or float literal, handling a
+// fractional part and an exponent. startPosition/startLine/startColumn
+// locate the literal's first digit for error reporting.
+func (lexer *Lexer) readDecimalNumber(startPosition int, startLine int, startColumn int) (string, token.TokenType) {
+	lexer.readDigitGroup(isDigit)
+
+	isFloat := false
+	if lexer.currentChar == '.' && isDigit(lexer.peek()) {
+		isFloat = true
+		lexer.advance() // consume '.'
+		lexer.readDigitGroup(isDigit)
+	}
+
+	if lexer.currentChar == 'e' || lexer.currentChar == 'E' {
+		next := lexer.peek()
+		signedExponent := (next == '+' || next == '-') && isDigit(lexer.peekAt(2))
+		if isDigit(next) || signedExponent {
+			isFloat = true
+			lexer.advance() // consume 'e'/'E'
+			if lexer.currentChar == '+' || lexer.currentChar == '-' {
+				lexer.advance() // consume sign
+			}
+			lexer.readDigitGroup(isDigit)
+		}
 	}
 
 	// Check for invalid number format (e.g., 123abc)
@@ -190,16 +384,86 @@ func (lexer *Lexer) readNumber() string {
 		invalidToken := lexer.input[startPosition:lexer.position]
 		lexer.errors.Add(
 			errors.LexicalError,
-			lexer.line,
-			lexer.column,
+			startLine,
+			startColumn,
 			len(invalidToken),
 			"Invalid number format: %s",
 			invalidToken,
 		)
-		return invalidToken
+		return invalidToken, token.INT_LITERAL
 	}
 
-	return lexer.input[startPosition:lexer.position]
+	literal := lexer.input[startPosition:lexer.position]
+	if isFloat {
+		return literal, token.FLOAT_LITERAL
+	}
+	return literal, token.INT_LITERAL
+}
+
+// readPrefixedInteger reads a 0x/0b/0o-prefixed integer literal in the
+// base described by isValidDigit, reporting a malformed-literal error if
+// the prefix is followed by no digits of that base. startPosition/
+// startLine/startColumn locate the literal's leading '0' for error
+// reporting.
+func (lexer *Lexer) readPrefixedInteger(startPosition int, startLine int, startColumn int, base string, isValidDigit func(byte) bool) (string, token.TokenType) {
+	lexer.advance() // consume '0'
+	lexer.advance() // consume base prefix letter
+
+	digitsStart := lexer.position
+	lexer.readDigitGroup(isValidDigit)
+
+	if lexer.position == digitsStart {
+		lexer.errors.Add(
+			errors.LexicalError,
+			startLine,
+			startColumn,
+			lexer.position-startPosition,
+			"Malformed numeric literal: %s integer has no digits",
+			base,
+		)
+	}
+
+	// Check for invalid trailing characters, including decimal digits
+	// outside this literal's alphabet (e.g. 0xFFz, 0b102)
+	if isLetter(lexer.currentChar) || isDigit(lexer.currentChar) {
+		for isLetter(lexer.currentChar) || isDigit(lexer.currentChar) {
+			lexer.advance()
+		}
+		invalidToken := lexer.input[startPosition:lexer.position]
+		lexer.errors.Add(
+			errors.LexicalError,
+			startLine,
+			startColumn,
+			len(invalidToken),
+			"Malformed numeric literal: %s",
+			invalidToken,
+		)
+		return invalidToken, token.INT_LITERAL
+	}
+
+	return lexer.input[startPosition:lexer.position], token.INT_LITERAL
+}
+
+// readDigitGroup consumes a run of digits (as validated by isValidDigit)
+// with optional underscore separators, reporting a malformed-literal
+// error if an underscore doesn't fall strictly between two digits.
+func (lexer *Lexer) readDigitGroup(isValidDigit func(byte) bool) {
+	for isValidDigit(lexer.currentChar) || lexer.currentChar == '_' {
+		if lexer.currentChar == '_' {
+			precededByDigit := lexer.position > 0 && isValidDigit(lexer.input[lexer.position-1])
+			followedByDigit := isValidDigit(lexer.peek())
+			if !precededByDigit || !followedByDigit {
+				lexer.errors.Add(
+					errors.LexicalError,
+					lexer.line,
+					lexer.column,
+					1,
+					"Malformed numeric literal: underscore must be between digits",
+				)
+			}
+		}
+		lexer.advance()
+	}
 }
 
 // readIdentifier reads an identifier or keyword from the input
@@ -222,6 +486,95 @@ func (lexer *Lexer) readIdentifier() string {
 	return lexer.input[startPosition:lexer.position]
 }
 
+// readLineComment consumes a `//` comment up to (but not including) the
+// next newline or EOF. A `///` comment is a doc comment: its trimmed body
+// is returned as a DOC_COMMENT token. A plain `//` comment is transparent
+// to the parser, so it is skipped and scanning continues to the next
+// token. startLine and startColumn locate the comment's opening slashes.
+func (lexer *Lexer) readLineComment(startLine int, startColumn int) token.Token {
+	lexer.advance() // consume first '/'
+	lexer.advance() // consume second '/'
+
+	isDoc := lexer.currentChar == '/'
+	if isDoc {
+		lexer.advance() // consume third '/'
+	}
+
+	var body strings.Builder
+	for lexer.currentChar != '\n' && lexer.currentChar != 0 {
+		if isDoc {
+			body.WriteByte(lexer.currentChar)
+		}
+		lexer.advance()
+	}
+
+	if !isDoc {
+		return lexer.scanToken()
+	}
+
+	return token.New(token.DOC_COMMENT, strings.TrimSpace(body.String()), startLine, startColumn)
+}
+
+// readBlockComment consumes a `/* ... */` comment, honoring nested block
+// comments. A `/**` comment (that isn't the empty `/**/`) is a doc
+// comment: its trimmed body is returned as a DOC_COMMENT token. A plain
+// block comment is transparent to the parser, so it is skipped and
+// scanning continues to the next token. An EOF before the matching `*/`
+// is reported as an unterminated block comment. startLine and
+// startColumn locate the comment's opening `/*`.
+func (lexer *Lexer) readBlockComment(startLine int, startColumn int) token.Token {
+	lexer.advance() // consume '/'
+	lexer.advance() // consume '*'
+
+	isDoc := lexer.currentChar == '*' && lexer.peek() != '/'
+	if isDoc {
+		lexer.advance() // consume the extra '*' marking a doc comment
+	}
+
+	var body strings.Builder
+	depth := 1
+	for depth > 0 && lexer.currentChar != 0 {
+		if lexer.currentChar == '*' && lexer.peek() == '/' {
+			lexer.advance() // consume '*'
+			lexer.advance() // consume '/'
+			depth--
+			continue
+		}
+
+		if lexer.currentChar == '/' && lexer.peek() == '*' {
+			lexer.advance() // consume '/'
+			lexer.advance() // consume '*'
+			depth++
+			continue
+		}
+
+		if isDoc {
+			body.WriteByte(lexer.currentChar)
+		}
+		lexer.advance()
+	}
+
+	if depth > 0 {
+		if lexer.incremental {
+			lexer.needsMore = true
+			return token.New(token.EOF, "", lexer.line, lexer.column)
+		}
+		lexer.errors.Add(
+			errors.LexicalError,
+			startLine,
+			startColumn,
+			2,
+			"Unterminated block comment",
+		)
+	}
+
+	if !isDoc {
+		return lexer.scanToken()
+	}
+
+	return token.New(token.DOC_COMMENT, strings.TrimSpace(body.String()), startLine, startColumn)
+}
+
 func (lexer *Lexer) peek() byte {
 	if lexer.readPosition >= len(lexer.input) {
 		return 0
@@ -229,3 +582,208 @@ func (lexer *Lexer) peek() byte {
 
 	return lexer.input[lexer.readPosition]
 }
+
+// peekAt returns the character offset bytes ahead of readPosition (so
+// peekAt(1) is equivalent to peek()), or 0 if that is past EOF. Used by
+// readDecimalNumber to look two characters ahead when disambiguating a
+// signed exponent (e.g. the "1" in "2.5e-1") from a trailing identifier.
+func (lexer *Lexer) peekAt(offset int) byte {
+	index := lexer.readPosition + offset - 1
+	if index >= len(lexer.input) {
+		return 0
+	}
+
+	return lexer.input[index]
+}
+
+// readStringLiteral reads a double-quoted string literal, decoding escape
+// sequences as it goes, and returns the decoded value (the raw quotes and
+// backslashes are not part of it). The current char must be the opening
+// quote. An EOF or stray newline before the closing quote is reported as
+// an unterminated string literal, and the literal read so far is returned.
+func (lexer *Lexer) readStringLiteral() string {
+	startLine := lexer.line
+	startColumn := lexer.column
+	startPosition := lexer.position
+
+	var value strings.Builder
+	lexer.advance() // consume opening quote
+
+	for lexer.currentChar != '"' {
+		if lexer.currentChar == 0 || lexer.currentChar == '\n' {
+			if lexer.incremental && lexer.currentChar == 0 {
+				lexer.needsMore = true
+				return value.String()
+			}
+			lexer.errors.Add(
+				errors.LexicalError,
+				startLine,
+				startColumn,
+				lexer.position-startPosition,
+				"Unterminated string literal",
+			)
+			return value.String()
+		}
+
+		if lexer.currentChar == '\\' {
+			lexer.advance() // consume '\'
+			value.WriteRune(lexer.readEscapeSequence())
+			continue
+		}
+
+		value.WriteByte(lexer.currentChar)
+		lexer.advance()
+	}
+
+	lexer.advance() // consume closing quote
+
+	return value.String()
+}
+
+// readCharLiteral reads a single-quoted character literal, decoding a
+// single escape sequence if present, and returns the decoded value as a
+// one-rune string. The current char must be the opening quote.
+func (lexer *Lexer) readCharLiteral() string {
+	startLine := lexer.line
+	startColumn := lexer.column
+	startPosition := lexer.position
+
+	lexer.advance() // consume opening quote
+
+	if lexer.currentChar == '\'' || lexer.currentChar == 0 || lexer.currentChar == '\n' {
+		if lexer.incremental && lexer.currentChar == 0 {
+			lexer.needsMore = true
+			return ""
+		}
+		lexer.errors.Add(
+			errors.LexicalError,
+			startLine,
+			startColumn,
+			lexer.position-startPosition,
+			"Empty character literal",
+		)
+		if lexer.currentChar == '\'' {
+			lexer.advance()
+		}
+		return ""
+	}
+
+	var char rune
+	if lexer.currentChar == '\\' {
+		lexer.advance() // consume '\'
+		char = lexer.readEscapeSequence()
+	} else {
+		char = rune(lexer.currentChar)
+		lexer.advance()
+	}
+
+	if lexer.currentChar != '\'' {
+		if lexer.incremental && lexer.currentChar == 0 {
+			lexer.needsMore = true
+			return string(char)
+		}
+		lexer.errors.Add(
+			errors.LexicalError,
+			startLine,
+			startColumn,
+			lexer.position-startPosition,
+			"Unterminated character literal",
+		)
+		return string(char)
+	}
+
+	lexer.advance() // consume closing quote
+
+	return string(char)
+}
+
+// readEscapeSequence decodes a single escape sequence, with the current
+// char positioned right after the backslash, and returns its decoded
+// rune, leaving the lexer positioned after the sequence. An unrecognized
+// escape, or a malformed \x/\u sequence, is reported and the offending
+// character(s) are consumed so scanning of the surrounding literal can
+// continue.
+func (lexer *Lexer) readEscapeSequence() rune {
+	line := lexer.line
+	column := lexer.column
+
+	switch lexer.currentChar {
+	case 'n':
+		lexer.advance()
+		return '\n'
+	case 't':
+		lexer.advance()
+		return '\t'
+	case 'r':
+		lexer.advance()
+		return '\r'
+	case '\\':
+		lexer.advance()
+		return '\\'
+	case '"':
+		lexer.advance()
+		return '"'
+	case '\'':
+		lexer.advance()
+		return '\''
+	case '0':
+		lexer.advance()
+		return 0
+	case 'x':
+		lexer.advance()
+		return rune(lexer.readHexDigits(2, line, column))
+	case 'u':
+		lexer.advance()
+		return rune(lexer.readHexDigits(4, line, column))
+	default:
+		lexer.errors.Add(
+			errors.LexicalError,
+			line,
+			column,
+			1,
+			"Unknown escape sequence: \\%s",
+			string(lexer.currentChar),
+		)
+		lexer.advance()
+		return 0
+	}
+}
+
+// readHexDigits reads exactly count hexadecimal digits (as used by the
+// \xHH and \uHHHH escapes) and returns their value. line and column
+// locate the escape's leading backslash for error reporting.
+func (lexer *Lexer) readHexDigits(count int, line int, column int) int {
+	value := 0
+
+	for i := 0; i < count; i++ {
+		if !isHexDigit(lexer.currentChar) {
+			lexer.errors.Add(
+				errors.LexicalError,
+				line,
+				column,
+				i+2, // backslash + escape letter + digits read so far
+				"Invalid escape sequence: expected %d hex digits",
+				count,
+			)
+			return value
+		}
+
+		value = value*16 + hexDigitValue(lexer.currentChar)
+		lexer.advance()
+	}
+
+	return value
+}
+
+// hexDigitValue converts a hex digit character, as validated by
+// isHexDigit, to its numeric value.
+func hexDigitValue(char byte) int {
+	switch {
+	case '0' <= char && char <= '9':
+		return int(char - '0')
+	case 'a' <= char && char <= 'f':
+		return int(char-'a') + 10
+	default:
+		return int(char-'A') + 10
+	}
+}
@@ -118,13 +118,61 @@ func TestLexer(t *testing.T) {
 				expectedType:  token.INT_LITERAL,
 				expectedValue: "123",
 			},
-			// TODO:
-			// {
-			// 	name:          "Integer with underscore",
-			// 	input:         "1_000",
-			// 	expectedType:  token.INT_LITERAL,
-			// 	expectedValue: "1_000",
-			// },
+			{
+				name:          "Integer with underscore",
+				input:         "1_000",
+				expectedType:  token.INT_LITERAL,
+				expectedValue: "1_000",
+			},
+			{
+				name:          "Hexadecimal integer",
+				input:         "0xFF",
+				expectedType:  token.INT_LITERAL,
+				expectedValue: "0xFF",
+			},
+			{
+				name:          "Hexadecimal integer with underscore",
+				input:         "0xDEAD_BEEF",
+				expectedType:  token.INT_LITERAL,
+				expectedValue: "0xDEAD_BEEF",
+			},
+			{
+				name:          "Binary integer",
+				input:         "0b1010",
+				expectedType:  token.INT_LITERAL,
+				expectedValue: "0b1010",
+			},
+			{
+				name:          "Octal integer",
+				input:         "0o755",
+				expectedType:  token.INT_LITERAL,
+				expectedValue: "0o755",
+			},
+			// FLOAT
+			{
+				name:          "Simple float",
+				input:         "3.14",
+				expectedType:  token.FLOAT_LITERAL,
+				expectedValue: "3.14",
+			},
+			{
+				name:          "Float with exponent",
+				input:         "1e10",
+				expectedType:  token.FLOAT_LITERAL,
+				expectedValue: "1e10",
+			},
+			{
+				name:          "Float with negative exponent",
+				input:         "2.5e-3",
+				expectedType:  token.FLOAT_LITERAL,
+				expectedValue: "2.5e-3",
+			},
+			{
+				name:          "Float with positive exponent",
+				input:         "2.5e+3",
+				expectedType:  token.FLOAT_LITERAL,
+				expectedValue: "2.5e+3",
+			},
 			// IDENTIFIER
 			{
 				name:          "Simple identifier",
@@ -150,6 +198,60 @@ func TestLexer(t *testing.T) {
 				expectedType:  token.IDENTIFIER,
 				expectedValue: "x1",
 			},
+			{
+				name:          "Simple string literal",
+				input:         `"hello"`,
+				expectedType:  token.STRING_LITERAL,
+				expectedValue: "hello",
+			},
+			{
+				name:          "Empty string literal",
+				input:         `""`,
+				expectedType:  token.STRING_LITERAL,
+				expectedValue: "",
+			},
+			{
+				name:          "String literal with escape sequences",
+				input:         `"a\nb\tc\\d\"e"`,
+				expectedType:  token.STRING_LITERAL,
+				expectedValue: "a\nb\tc\\d\"e",
+			},
+			{
+				name:          "String literal with hex escape",
+				input:         `"\x41"`,
+				expectedType:  token.STRING_LITERAL,
+				expectedValue: "A",
+			},
+			{
+				name:          "String literal with multi-byte UTF-8 character",
+				input:         `"é"`,
+				expectedType:  token.STRING_LITERAL,
+				expectedValue: "é",
+			},
+			{
+				name:          "String literal with \\u escape",
+				input:         "\"\\u00e9\"",
+				expectedType:  token.STRING_LITERAL,
+				expectedValue: "é",
+			},
+			{
+				name:          "Simple character literal",
+				input:         `'a'`,
+				expectedType:  token.CHAR_LITERAL,
+				expectedValue: "a",
+			},
+			{
+				name:          "Character literal with escape sequence",
+				input:         `'\n'`,
+				expectedType:  token.CHAR_LITERAL,
+				expectedValue: "\n",
+			},
+			{
+				name:          "Character literal with escaped quote",
+				input:         `'\''`,
+				expectedType:  token.CHAR_LITERAL,
+				expectedValue: "'",
+			},
 		}
 
 		for _, testCase := range tests {
@@ -232,6 +334,48 @@ func TestLexer(t *testing.T) {
 				expectedType:  token.COLON_ASSIGN,
 				expectedValue: ":=",
 			},
+			{
+				name:          "Plus assign token",
+				input:         "+=",
+				expectedType:  token.PLUS_ASSIGN,
+				expectedValue: "+=",
+			},
+			{
+				name:          "Minus assign token",
+				input:         "-=",
+				expectedType:  token.MINUS_ASSIGN,
+				expectedValue: "-=",
+			},
+			{
+				name:          "Asterisk assign token",
+				input:         "*=",
+				expectedType:  token.ASTERISK_ASSIGN,
+				expectedValue: "*=",
+			},
+			{
+				name:          "Slash assign token",
+				input:         "/=",
+				expectedType:  token.SLASH_ASSIGN,
+				expectedValue: "/=",
+			},
+			{
+				name:          "Percent assign token",
+				input:         "%=",
+				expectedType:  token.PERCENT_ASSIGN,
+				expectedValue: "%=",
+			},
+			{
+				name:          "Logical not token",
+				input:         "!",
+				expectedType:  token.LOGICAL_NOT,
+				expectedValue: "!",
+			},
+			{
+				name:          "Percent token",
+				input:         "%",
+				expectedType:  token.PERCENT,
+				expectedValue: "%",
+			},
 		}
 
 		for _, testCase := range tests {
@@ -425,6 +569,66 @@ func TestLexer(t *testing.T) {
 				expectedErrorCount: 1,
 				shouldContainError: "Invalid number format",
 			},
+			{
+				name:               "Unterminated string literal",
+				input:              `"hello`,
+				expectedErrorCount: 1,
+				shouldContainError: "Unterminated string literal",
+			},
+			{
+				name:               "String literal with stray newline",
+				input:              "\"hello\nworld\"",
+				expectedErrorCount: 1,
+				shouldContainError: "Unterminated string literal",
+			},
+			{
+				name:               "Unknown escape sequence",
+				input:              `"\q"`,
+				expectedErrorCount: 1,
+				shouldContainError: "Unknown escape sequence",
+			},
+			{
+				name:               "Incomplete hex escape",
+				input:              `"\x4"`,
+				expectedErrorCount: 1,
+				shouldContainError: "Invalid escape sequence",
+			},
+			{
+				name:               "Empty character literal",
+				input:              "''",
+				expectedErrorCount: 1,
+				shouldContainError: "Empty character literal",
+			},
+			{
+				name:               "Unterminated character literal",
+				input:              "'ab'",
+				expectedErrorCount: 1,
+				shouldContainError: "Unterminated character literal",
+			},
+			{
+				name:               "Trailing underscore in integer",
+				input:              "1_",
+				expectedErrorCount: 1,
+				shouldContainError: "Malformed numeric literal",
+			},
+			{
+				name:               "Double underscore in integer",
+				input:              "1__000",
+				expectedErrorCount: 1,
+				shouldContainError: "Malformed numeric literal",
+			},
+			{
+				name:               "Hexadecimal prefix with no digits",
+				input:              "0x",
+				expectedErrorCount: 1,
+				shouldContainError: "Malformed numeric literal",
+			},
+			{
+				name:               "Binary literal with invalid digit",
+				input:              "0b102",
+				expectedErrorCount: 1,
+				shouldContainError: "Malformed numeric literal",
+			},
 		}
 
 		for _, testCase := range tests {
@@ -666,6 +870,20 @@ func TestLexer(t *testing.T) {
 					{token.INT_LITERAL, "5", 1, 10},
 				},
 			},
+			{
+				name:  "Numeric literal spans are captured whole",
+				input: "0xDEAD_BEEF 2.5e-3 x",
+				expectedTokens: []struct {
+					tokenType token.TokenType
+					value     string
+					line      int
+					column    int
+				}{
+					{token.INT_LITERAL, "0xDEAD_BEEF", 1, 1},
+					{token.FLOAT_LITERAL, "2.5e-3", 1, 13},
+					{token.IDENTIFIER, "x", 1, 20},
+				},
+			},
 		}
 
 		for _, testCase := range tests {
@@ -692,4 +910,318 @@ func TestLexer(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("Test comments", func(t *testing.T) {
+		tests := []multipleTokenTestCase{
+			{
+				name:  "Line comment is skipped",
+				input: "let x = 1 // this is ignored\nlet y = 2",
+				expectedTokens: []struct {
+					tokenType  token.TokenType
+					tokenValue string
+				}{
+					{token.LET, "let"},
+					{token.IDENTIFIER, "x"},
+					{token.ASSIGN, "="},
+					{token.INT_LITERAL, "1"},
+					{token.NEW_LINE, "\n"},
+					{token.LET, "let"},
+					{token.IDENTIFIER, "y"},
+					{token.ASSIGN, "="},
+					{token.INT_LITERAL, "2"},
+					{token.EOF, ""},
+				},
+			},
+			{
+				name:  "Block comment is skipped",
+				input: "let x /* inline */ = 1",
+				expectedTokens: []struct {
+					tokenType  token.TokenType
+					tokenValue string
+				}{
+					{token.LET, "let"},
+					{token.IDENTIFIER, "x"},
+					{token.ASSIGN, "="},
+					{token.INT_LITERAL, "1"},
+					{token.EOF, ""},
+				},
+			},
+			{
+				name:  "Multi-line block comment is skipped",
+				input: "let x = 1 /* spans\nmultiple\nlines */ + 2",
+				expectedTokens: []struct {
+					tokenType  token.TokenType
+					tokenValue string
+				}{
+					{token.LET, "let"},
+					{token.IDENTIFIER, "x"},
+					{token.ASSIGN, "="},
+					{token.INT_LITERAL, "1"},
+					{token.PLUS, "+"},
+					{token.INT_LITERAL, "2"},
+					{token.EOF, ""},
+				},
+			},
+			{
+				name:  "Nested block comment is skipped",
+				input: "1 /* outer /* inner */ still outer */ 2",
+				expectedTokens: []struct {
+					tokenType  token.TokenType
+					tokenValue string
+				}{
+					{token.INT_LITERAL, "1"},
+					{token.INT_LITERAL, "2"},
+					{token.EOF, ""},
+				},
+			},
+			{
+				name:  "Doc comment between declarations",
+				input: "/// Adds two numbers.\nfunc add() {}",
+				expectedTokens: []struct {
+					tokenType  token.TokenType
+					tokenValue string
+				}{
+					{token.DOC_COMMENT, "Adds two numbers."},
+					{token.NEW_LINE, "\n"},
+					{token.FUNC, "func"},
+					{token.IDENTIFIER, "add"},
+					{token.LEFT_PAREN, "("},
+					{token.RIGHT_PAREN, ")"},
+					{token.LEFT_BRACE, "{"},
+					{token.RIGHT_BRACE, "}"},
+					{token.EOF, ""},
+				},
+			},
+			{
+				name:  "Block doc comment",
+				input: "/** Adds two numbers. */\nfunc add() {}",
+				expectedTokens: []struct {
+					tokenType  token.TokenType
+					tokenValue string
+				}{
+					{token.DOC_COMMENT, "Adds two numbers."},
+					{token.NEW_LINE, "\n"},
+					{token.FUNC, "func"},
+					{token.IDENTIFIER, "add"},
+					{token.LEFT_PAREN, "("},
+					{token.RIGHT_PAREN, ")"},
+					{token.LEFT_BRACE, "{"},
+					{token.RIGHT_BRACE, "}"},
+					{token.EOF, ""},
+				},
+			},
+			{
+				name:  "Empty block comment is not a doc comment",
+				input: "/**/ 1",
+				expectedTokens: []struct {
+					tokenType  token.TokenType
+					tokenValue string
+				}{
+					{token.INT_LITERAL, "1"},
+					{token.EOF, ""},
+				},
+			},
+			{
+				name:  "Comment interleaved in expression",
+				input: "1 + /* two */ 2",
+				expectedTokens: []struct {
+					tokenType  token.TokenType
+					tokenValue string
+				}{
+					{token.INT_LITERAL, "1"},
+					{token.PLUS, "+"},
+					{token.INT_LITERAL, "2"},
+					{token.EOF, ""},
+				},
+			},
+		}
+
+		for _, testCase := range tests {
+			t.Run(testCase.name, func(t *testing.T) {
+				errorCollector := errors.New(testCase.input, "test.navi")
+				lexerInstance := New(testCase.input, "test.navi", errorCollector)
+
+				for index, expected := range testCase.expectedTokens {
+					tok := lexerInstance.NextToken()
+
+					assert.Equal(t, expected.tokenType, tok.Type,
+						"Token %d: type mismatch", index)
+					assert.Equal(t, expected.tokenValue, tok.Value,
+						"Token %d: value mismatch", index)
+				}
+
+				assert.False(t, errorCollector.HasErrors(),
+					"Lexer should not produce errors for valid input")
+			})
+		}
+
+		t.Run("Line/column tracking after multi-line comment", func(t *testing.T) {
+			input := "/* line one\nline two */let x = 1"
+			errorCollector := errors.New(input, "test.navi")
+			lexerInstance := New(input, "test.navi", errorCollector)
+
+			tok := lexerInstance.NextToken()
+
+			assert.Equal(t, token.LET, tok.Type, "Token type mismatch")
+			assert.Equal(t, 2, tok.Line, "Line mismatch")
+			assert.Equal(t, 12, tok.Column, "Column mismatch")
+		})
+
+		t.Run("Unterminated block comment", func(t *testing.T) {
+			input := "/* never closed"
+			errorCollector := errors.New(input, "test.navi")
+			lexerInstance := New(input, "test.navi", errorCollector)
+
+			for {
+				tok := lexerInstance.NextToken()
+				if tok.Type == token.EOF {
+					break
+				}
+			}
+
+			assert.True(t, errorCollector.HasErrors(),
+				"Lexer should report an error for an unterminated block comment")
+		})
+	})
+
+	t.Run("Test Peek and PeekN", func(t *testing.T) {
+		t.Run("Peek does not consume the token", func(t *testing.T) {
+			input := "1 + 2"
+			errorCollector := errors.New(input, "test.navi")
+			lexerInstance := New(input, "test.navi", errorCollector)
+
+			peeked := lexerInstance.Peek()
+			next := lexerInstance.NextToken()
+
+			assert.Equal(t, peeked.Type, next.Type, "Type mismatch")
+			assert.Equal(t, peeked.Value, next.Value, "Value mismatch")
+			assert.Equal(t, peeked.Line, next.Line, "Line mismatch")
+			assert.Equal(t, peeked.Column, next.Column, "Column mismatch")
+		})
+
+		t.Run("Repeated Peek returns the same token", func(t *testing.T) {
+			input := "1 + 2"
+			errorCollector := errors.New(input, "test.navi")
+			lexerInstance := New(input, "test.navi", errorCollector)
+
+			first := lexerInstance.Peek()
+			second := lexerInstance.Peek()
+
+			assert.Equal(t, first, second, "Repeated Peek should return identical tokens")
+		})
+
+		t.Run("PeekN looks ahead without losing tokens", func(t *testing.T) {
+			input := "1 + 2"
+			errorCollector := errors.New(input, "test.navi")
+			lexerInstance := New(input, "test.navi", errorCollector)
+
+			third := lexerInstance.PeekN(3)
+			assert.Equal(t, token.INT_LITERAL, third.Type, "Token 3 type mismatch")
+			assert.Equal(t, "2", third.Value, "Token 3 value mismatch")
+
+			expectedTypes := []token.TokenType{token.INT_LITERAL, token.PLUS, token.INT_LITERAL, token.EOF}
+			for index, expectedType := range expectedTypes {
+				tok := lexerInstance.NextToken()
+				assert.Equal(t, expectedType, tok.Type, "Token %d: type mismatch", index)
+			}
+		})
+
+		t.Run("Peeking past EOF is idempotent", func(t *testing.T) {
+			input := "1"
+			errorCollector := errors.New(input, "test.navi")
+			lexerInstance := New(input, "test.navi", errorCollector)
+
+			first := lexerInstance.PeekN(5)
+			second := lexerInstance.PeekN(5)
+
+			assert.Equal(t, token.EOF, first.Type, "Expected EOF when peeking past the end of input")
+			assert.Equal(t, first, second, "Peeking past EOF repeatedly should return the same token")
+		})
+	})
+
+	t.Run("Test All", func(t *testing.T) {
+		input := "1 + 2"
+		errorCollector := errors.New(input, "test.navi")
+		lexerInstance := New(input, "test.navi", errorCollector)
+
+		tokens := lexerInstance.All()
+
+		expectedTypes := []token.TokenType{token.INT_LITERAL, token.PLUS, token.INT_LITERAL, token.EOF}
+		assert.Equal(t, len(expectedTypes), len(tokens), "Unexpected token count")
+		for index, expectedType := range expectedTypes {
+			assert.Equal(t, expectedType, tokens[index].Type, "Token %d: type mismatch", index)
+		}
+	})
+
+	t.Run("Test incremental lexing", func(t *testing.T) {
+		t.Run("Unterminated string defers the error until more input arrives", func(t *testing.T) {
+			errorCollector := errors.New("", "repl")
+			lexerInstance := NewIncremental("repl", errorCollector)
+
+			lexerInstance.Reset(`let x = "`)
+			for {
+				tok := lexerInstance.NextToken()
+				if tok.Type == token.EOF {
+					break
+				}
+			}
+
+			assert.True(t, lexerInstance.NeedsMoreInput(),
+				"Lexer should report that more input is needed for the unterminated string")
+			assert.False(t, errorCollector.HasErrors(),
+				"Lexer should not report an error while the string is still open")
+
+			lexerInstance.Reset("hello\"\n")
+
+			var tokens []token.Token
+			for {
+				tok := lexerInstance.NextToken()
+				tokens = append(tokens, tok)
+				if tok.Type == token.EOF {
+					break
+				}
+			}
+
+			assert.False(t, lexerInstance.NeedsMoreInput(),
+				"Lexer should no longer need more input once the string is closed")
+			assert.False(t, errorCollector.HasErrors(),
+				"Lexer should not report an error once the string is well-formed")
+
+			require := func(index int, expectedType token.TokenType, expectedValue string) {
+				assert.Equal(t, expectedType, tokens[index].Type, "Token %d: type mismatch", index)
+				assert.Equal(t, expectedValue, tokens[index].Value, "Token %d: value mismatch", index)
+			}
+			require(0, token.LET, "let")
+			require(1, token.IDENTIFIER, "x")
+			require(2, token.ASSIGN, "=")
+			require(3, token.STRING_LITERAL, "hello")
+		})
+
+		t.Run("Unbalanced brace needs more input", func(t *testing.T) {
+			errorCollector := errors.New("", "repl")
+			lexerInstance := NewIncremental("repl", errorCollector)
+
+			lexerInstance.Reset("func main() {")
+			for {
+				tok := lexerInstance.NextToken()
+				if tok.Type == token.EOF {
+					break
+				}
+			}
+
+			assert.True(t, lexerInstance.NeedsMoreInput(),
+				"Lexer should report that more input is needed for the open brace")
+
+			lexerInstance.Reset("}\n")
+			for {
+				tok := lexerInstance.NextToken()
+				if tok.Type == token.EOF {
+					break
+				}
+			}
+
+			assert.False(t, lexerInstance.NeedsMoreInput(),
+				"Lexer should no longer need more input once the brace is closed")
+		})
+	})
 }
@@ -0,0 +1,32 @@
+package lexer
+
+import "compiler/token"
+
+// matchOperator finds the longest operator/punctuation lexeme at the
+// start of input using the trie compiled into operatorTransitions and
+// operatorAccept by compiler/lexer/gen (maximal munch: "==" wins over
+// "=" because the trie keeps walking past the shorter accepting state).
+// It reports ok == false if input doesn't start with any pattern from
+// operatorSpecs at all, in which case the caller falls back to its own
+// hand-written handling.
+func matchOperator(input string) (tokenType token.TokenType, length int, ok bool) {
+	state := operatorStartState
+
+	for i := 0; i < len(input); i++ {
+		transitions, hasTransitions := operatorTransitions[state]
+		if !hasTransitions {
+			break
+		}
+		next := transitions[input[i]]
+		if next == operatorDeadState {
+			break
+		}
+		state = next
+
+		if accepted, isAccepting := operatorAccept[state]; isAccepting {
+			tokenType, length, ok = accepted, i+1, true
+		}
+	}
+
+	return tokenType, length, ok
+}
@@ -9,3 +9,22 @@ func isLetter(char byte) bool {
 func isDigit(char byte) bool {
 	return '0' <= char && char <= '9'
 }
+
+// isHexDigit checks if a character is a hexadecimal digit, used when
+// decoding \xHH and \uHHHH escape sequences in string and character
+// literals, and when scanning 0x-prefixed integer literals.
+func isHexDigit(char byte) bool {
+	return isDigit(char) || ('a' <= char && char <= 'f') || ('A' <= char && char <= 'F')
+}
+
+// isBinaryDigit checks if a character is a valid digit in a 0b-prefixed
+// integer literal.
+func isBinaryDigit(char byte) bool {
+	return char == '0' || char == '1'
+}
+
+// isOctalDigit checks if a character is a valid digit in a 0o-prefixed
+// integer literal.
+func isOctalDigit(char byte) bool {
+	return '0' <= char && char <= '7'
+}
@@ -1,23 +1,70 @@
 package main
 
 import (
+	"compiler/ast"
+	"compiler/codegen"
 	"compiler/codegen/llvm"
-	"compiler/constants"
+	"compiler/codegen/llvm/jit"
+	"compiler/debug"
 	"compiler/errors"
 	"compiler/lexer"
 	"compiler/nir"
+	"compiler/nir/opt"
+	"compiler/nir/ssa"
 	"compiler/parser"
+	"compiler/sema"
+	typecheck "compiler/type-checker"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
-// CompileFile compiles a single Naviary source file
-func CompileFile(inputPath string, runAfterCompile bool) error {
+// naviaryExtension is the required suffix for source files passed to the
+// compiler, e.g. "hello.navi".
+const naviaryExtension = ".navi"
+
+// nativeTargetTriples maps the -target flag's os/arch spelling to the
+// target triple codegen.NewEmitterForTarget expects.
+var nativeTargetTriples = map[string]string{
+	"darwin/arm64":  "arm64-apple-darwin",
+	"darwin/amd64":  "x86_64-apple-darwin",
+	"linux/amd64":   "x86_64-linux-gnu",
+	"linux/riscv64": "riscv64-linux-gnu",
+}
+
+// compileNative lowers program directly to target's assembly via the
+// abstract-instruction CodeGenerator (compiler/codegen), bypassing the
+// LLVM pipeline entirely. It exists alongside LLVM codegen rather than
+// replacing it: LLVM remains the default, full-featured backend, while
+// -target picks this lighter native path for a specific ISA/ABI.
+// optLevel is the same -O0/-O1 switch that gates NIR optimization:
+// here it picks between CodeGenerator's naive register strategy and its
+// linear-scan allocator (see CodeGenerator.allocateVirtual).
+func compileNative(program *ast.Program, target string, outputPath string, optLevel int) error {
+	triple, ok := nativeTargetTriples[target]
+	if !ok {
+		return fmt.Errorf("unsupported -target %q (supported: darwin/arm64, darwin/amd64, linux/amd64, linux/riscv64)", target)
+	}
+
+	emitter, err := codegen.NewEmitterForTarget(triple)
+	if err != nil {
+		return err
+	}
+
+	generator := codegen.New(emitter, optLevel)
+	generator.Generate(program)
+
+	return os.WriteFile(outputPath, []byte(generator.GenerateAssembly()), 0644)
+}
+
+// CompileFile compiles a single Naviary source file. exitCode is only
+// meaningful when runAfterCompile is true, holding the jitted program's
+// own exit status; callers should ignore it otherwise.
+func CompileFile(inputPath string, runAfterCompile bool, dumper *debug.Dumper, optLevel int, target string, traceFlag bool, dumpNirFlag bool) (int, error) {
 	sourceCode, err := os.ReadFile(inputPath)
 	if err != nil {
-		return fmt.Errorf("failed to read file %s: %v", inputPath, err)
+		return 0, fmt.Errorf("failed to read file %s: %v", inputPath, err)
 	}
 
 	fileName := filepath.Base(inputPath)
@@ -29,63 +76,168 @@ func CompileFile(inputPath string, runAfterCompile bool) error {
 	// Step 1: Lexical Analysis
 	fmt.Printf("Compiling %s...\n", fileName)
 	lexerInstance := lexer.New(sourceStr, fileName, errorCollector)
+	tokens := lexerInstance.Tokenize()
+	if err := dumper.DumpPhase("lex", debug.FormatTokens(tokens)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
 
 	// Transfer lexer errors to main collector
 	if errorCollector.HasErrors() {
 		errorCollector.Display()
-		return fmt.Errorf("compilation failed")
+		return 0, fmt.Errorf("compilation failed")
 	}
 
 	// Step 2: Parsing
-	parserInstance := parser.New(lexerInstance, errorCollector)
+	parserMode := parser.ModeNone
+	if traceFlag {
+		parserMode = parser.ModeTrace
+	}
+	parserInstance := parser.NewWithMode(lexer.New(sourceStr, fileName, errorCollector), errorCollector, parserMode)
 	program := parserInstance.ParseProgram()
+	if err := dumper.DumpPhase("parse", program); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
 
 	// Transfer parser errors to main collector
 	if errorCollector.HasErrors() {
 		errorCollector.Display()
-		return fmt.Errorf("compilation failed")
+		return 0, fmt.Errorf("compilation failed")
+	}
+
+	// -target selects the native assembly backend instead of LLVM: it
+	// lowers straight from the AST (see compileNative), so it skips NIR
+	// entirely and returns here rather than falling through to Step 3.
+	if target != "" {
+		if runAfterCompile {
+			return 0, fmt.Errorf("-target is not supported with 'run' (no native JIT yet)")
+		}
+		outputPath := strings.TrimSuffix(inputPath, naviaryExtension) + ".s"
+		if err := compileNative(program, target, outputPath, optLevel); err != nil {
+			return 0, fmt.Errorf("native codegen failed: %w", err)
+		}
+		fmt.Printf("Wrote native assembly to %s\n", outputPath)
+		return 0, nil
+	}
+
+	// Step 2.5: Name resolution, ahead of lowering so the lowerer can use
+	// each variable/parameter's real declared type instead of guessing.
+	resolver := sema.NewResolver(errorCollector)
+	resolution := resolver.Resolve(program)
+
+	if errorCollector.HasErrors() {
+		errorCollector.Display()
+		return 0, fmt.Errorf("name resolution failed")
+	}
+
+	// Step 2.6: Type checking, ahead of lowering so an ill-typed program
+	// never reaches NIR at all. It keeps its own ErrorCollector (built
+	// over the same source) rather than sharing errorCollector above,
+	// since it predates sema.Resolver and doesn't consume Resolve's
+	// *sema.Resolution.
+	typeChecker := typecheck.New(sourceStr, fileName)
+	typeChecker.Check(program)
+
+	if typeChecker.Errors().HasErrors() {
+		typeChecker.Errors().Display()
+		return 0, fmt.Errorf("type checking failed")
 	}
 
 	//Step 3: Lower AST to NIR
 	lowerer := nir.NewLowerer(errorCollector)
+	lowerer.SetResolution(resolution)
+	lowerer.SetTrace(traceFlag)
 	nirModule := lowerer.Lower(program)
 
 	if errorCollector.HasErrors() {
 		errorCollector.Display()
-		return fmt.Errorf("lowering failed")
+		return 0, fmt.Errorf("lowering failed")
 	}
 
 	if !nirModule.IsComplete() {
-		return fmt.Errorf("generated NIR module is incomplete")
+		return 0, fmt.Errorf("generated NIR module is incomplete")
 	}
 	fmt.Println("NIR generation successful!")
+	if err := dumper.DumpPhase("nir", nirModule); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if dumpNirFlag {
+		if err := nir.DumpModule(os.Stdout, nirModule); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+
+	// Step 3.5: Optimize NIR (dead code elimination + constant folding) so
+	// both the LLVM and native backends see the same cleaned-up module.
+	opt.Run(nirModule, optLevel)
+	if err := dumper.DumpPhase("nir-opt", nirModule); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	// Step 3.6: Lift stack variables to SSA (dominance-frontier phi
+	// placement), then run another cleanup pass over the now-SSA module.
+	ssa.Run(nirModule)
+	opt.Run(nirModule, optLevel)
+	if err := dumper.DumpPhase("nir-ssa", nirModule); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
 
 	// Step 4: Generate LLVM IR
 	fmt.Println("Generating LLVM IR...")
-	generator := llvm.NewGenerator()
+	generator := llvm.NewGenerator(errorCollector)
 	defer generator.Dispose()
 
 	llvmIR, err := generator.Generate(nirModule)
+	if errorCollector.HasErrors() {
+		errorCollector.Display()
+		return 0, fmt.Errorf("codegen failed")
+	}
 	if err != nil {
-		return fmt.Errorf("failed to generate LLVM IR: %w", err)
+		return 0, fmt.Errorf("failed to generate LLVM IR: %w", err)
+	}
+	if err := dumper.DumpPhase("codegen", llvmIR); err != nil {
+		fmt.Fprintln(os.Stderr, err)
 	}
 
 	// Step 5: LLVM IR to file
-	outputPath := strings.TrimSuffix(inputPath, constants.NAVIARY_EXTENSION) + ".ll"
+	outputPath := strings.TrimSuffix(inputPath, naviaryExtension) + ".ll"
 	err = os.WriteFile(outputPath, []byte(llvmIR), 0644)
 	if err != nil {
-		return fmt.Errorf("failed to write LLVM IR to file: %w", err)
+		return 0, fmt.Errorf("failed to write LLVM IR to file: %w", err)
 	}
 
 	fmt.Println("\n=== Generated LLVM IR ===")
 	fmt.Println(llvmIR)
 
-	return nil
+	if !runAfterCompile {
+		return 0, nil
+	}
+
+	// Step 6: JIT the module in-process instead of only serializing it,
+	// so `naviary run` doesn't have to shell out to lli/clang.
+	fmt.Println("\nRunning with JIT...")
+	engine, err := jit.New(generator.Module())
+	if err != nil {
+		return 0, fmt.Errorf("failed to create JIT engine: %w", err)
+	}
+	defer engine.Dispose()
+
+	programExitCode, err := engine.RunMain()
+	if err != nil {
+		return 0, fmt.Errorf("failed to run program: %w", err)
+	}
+
+	return programExitCode, nil
 }
 
 func main() {
 	// Parse command line arguments
 	runFlag := false
+	dumpFlag := ""
+	dumpFuncFlag := ""
+	targetFlag := ""
+	optLevel := 1
+	traceFlag := false
+	dumpNirFlag := false
 	args := os.Args[1:]
 
 	if len(args) > 0 && args[0] == "run" {
@@ -93,18 +245,43 @@ func main() {
 		args = args[1:]
 	}
 
+	// Pull -dump=..., -dump-func=..., -target=..., and -O0/-O1 out of the
+	// argument list; they can appear before or after the source file.
+	var positional []string
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "-dump-func="):
+			dumpFuncFlag = strings.TrimPrefix(arg, "-dump-func=")
+		case strings.HasPrefix(arg, "-dump="):
+			dumpFlag = strings.TrimPrefix(arg, "-dump=")
+		case strings.HasPrefix(arg, "-target="):
+			targetFlag = strings.TrimPrefix(arg, "-target=")
+		case arg == "-O0":
+			optLevel = 0
+		case arg == "-O1":
+			optLevel = 1
+		case arg == "-trace":
+			traceFlag = true
+		case arg == "--dump-nir":
+			dumpNirFlag = true
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	args = positional
+
 	if len(args) < 1 {
-		fmt.Printf("Usage: naviary [run] <source_file%s>\n", constants.NAVIARY_EXTENSION)
-		fmt.Printf("  naviary hello%s       # Compile only\n", constants.NAVIARY_EXTENSION)
-		fmt.Printf("  naviary run hello%s   # Compile and run\n", constants.NAVIARY_EXTENSION)
+		fmt.Printf("Usage: naviary [run] <source_file%s> [-O0|-O1] [-dump=phase1,phase2|all] [-dump-func=name] [-target=darwin/arm64|darwin/amd64|linux/amd64|linux/riscv64] [-trace] [--dump-nir]\n", naviaryExtension)
+		fmt.Printf("  naviary hello%s       # Compile only\n", naviaryExtension)
+		fmt.Printf("  naviary run hello%s   # Compile and run\n", naviaryExtension)
 		os.Exit(1)
 	}
 
 	inputFile := args[0]
 
 	// Validate file extension
-	if !strings.HasSuffix(inputFile, constants.NAVIARY_EXTENSION) {
-		fmt.Printf("Error: Input file must have %s extension\n", constants.NAVIARY_EXTENSION)
+	if !strings.HasSuffix(inputFile, naviaryExtension) {
+		fmt.Printf("Error: Input file must have %s extension\n", naviaryExtension)
 		os.Exit(1)
 	}
 
@@ -114,11 +291,19 @@ func main() {
 		os.Exit(1)
 	}
 
+	outputBase := strings.TrimSuffix(inputFile, naviaryExtension)
+	dumper := debug.NewDumper(dumpFlag, dumpFuncFlag, outputBase)
+
 	// Compile the file
-	if err := CompileFile(inputFile, runFlag); err != nil {
+	exitCode, err := CompileFile(inputFile, runFlag, dumper, optLevel, targetFlag, traceFlag, dumpNirFlag)
+	if err != nil {
 		fmt.Printf("Compilation failed: %v\n", err)
 		os.Exit(1)
 	}
 
 	fmt.Println("Compilation successful!")
+
+	if runFlag {
+		os.Exit(exitCode)
+	}
 }
@@ -0,0 +1,160 @@
+// Package modules resolves `import "path"` statements to lowered NIR
+// modules, wiring the resulting graph into nir.Module.Imports/Exports so
+// the lowerer can turn a `pkg.func()` call into a cross-module call (see
+// nir.Lowerer.SetImports). It covers the compiler's own module-resolution
+// pipeline (lex -> parse -> lower, recursively over a file's imports);
+// stitching the resolved graph into a single linkable LLVM module is a
+// separate, not-yet-implemented integration step in codegen/llvm.
+package modules
+
+import (
+	"compiler/ast"
+	"compiler/errors"
+	"compiler/lexer"
+	"compiler/nir"
+	"compiler/parser"
+	"compiler/token"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Importer resolves import paths to lowered modules, searching Roots in
+// order for each one (mirroring how Peon's Interpreter resolves
+// lookupPaths to a source file) and loading any given file at most once,
+// so a module imported from two different files in the same program is
+// lowered a single time and shared between both.
+type Importer struct {
+	// Roots holds the directories searched, in order, for an import's
+	// source file. "" searches relative to the current working directory.
+	Roots []string
+
+	// Extension is appended to an import path to find its source file,
+	// e.g. ".navi" turns `import "std/math"` into "std/math.navi".
+	Extension string
+
+	loaded  map[string]*nir.Module // resolved file path -> its lowered Module
+	loading map[string]bool        // resolved file path -> currently being resolved, for cycle detection
+}
+
+func NewImporter(roots []string, extension string) *Importer {
+	return &Importer{
+		Roots:     roots,
+		Extension: extension,
+		loaded:    make(map[string]*nir.Module),
+		loading:   make(map[string]bool),
+	}
+}
+
+// ModuleName derives an import's module name from its path the same way
+// the lowerer does when matching an `import` against a `pkg.func()` call
+// (see nir.moduleNameFromPath): the last '/'-separated segment, e.g.
+// "std/math" -> "math".
+func ModuleName(importPath string) string {
+	if index := strings.LastIndex(importPath, "/"); index != -1 {
+		return importPath[index+1:]
+	}
+	return importPath
+}
+
+// Resolve loads and lowers importPath, recursively resolving any imports
+// of its own first, and returns the cached Module if an earlier Resolve
+// call already loaded it. importToken is the `import "path"` token that
+// triggered this resolution and fromFile the file it appears in, so an
+// unresolved path or an import cycle can be reported at the right
+// location.
+func (importer *Importer) Resolve(importPath string, importToken token.Token, fromFile string) (*nir.Module, error) {
+	resolvedPath, found := importer.find(importPath)
+	if !found {
+		return nil, errors.CompileError{
+			Type:    errors.TypeError,
+			Message: fmt.Sprintf("cannot find module %q in any of %v", importPath, importer.Roots),
+			File:    fromFile,
+			Line:    importToken.Line,
+			Column:  importToken.Column,
+			Length:  len(importToken.Value),
+		}
+	}
+
+	if module, ok := importer.loaded[resolvedPath]; ok {
+		return module, nil
+	}
+
+	if importer.loading[resolvedPath] {
+		return nil, errors.CompileError{
+			Type:    errors.TypeError,
+			Message: fmt.Sprintf("import cycle: %q is imported while it is still being resolved", importPath),
+			File:    fromFile,
+			Line:    importToken.Line,
+			Column:  importToken.Column,
+			Length:  len(importToken.Value),
+		}
+	}
+
+	importer.loading[resolvedPath] = true
+	defer delete(importer.loading, resolvedPath)
+
+	module, err := importer.load(resolvedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	importer.loaded[resolvedPath] = module
+	return module, nil
+}
+
+// find searches Roots in order for importPath+Extension, returning the
+// first match's cleaned path.
+func (importer *Importer) find(importPath string) (resolvedPath string, found bool) {
+	for _, root := range importer.Roots {
+		candidate := filepath.Join(root, importPath+importer.Extension)
+		if _, err := os.Stat(candidate); err == nil {
+			return filepath.Clean(candidate), true
+		}
+	}
+	return "", false
+}
+
+// load lexes, parses and lowers the file at resolvedPath, first
+// resolving every import it declares so the lowerer can attach them via
+// SetImports before lowering.
+func (importer *Importer) load(resolvedPath string) (*nir.Module, error) {
+	source, err := os.ReadFile(resolvedPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module %s: %w", resolvedPath, err)
+	}
+
+	fileName := filepath.Base(resolvedPath)
+	errorCollector := errors.New(string(source), fileName)
+
+	parserInstance := parser.New(lexer.New(string(source), fileName, errorCollector), errorCollector)
+	program := parserInstance.ParseProgram()
+	if errorCollector.HasErrors() {
+		return nil, fmt.Errorf("module %s has parse errors", resolvedPath)
+	}
+
+	imports := make(map[string]*nir.Module)
+	for _, statement := range program.Statements {
+		importStatement, ok := statement.(*ast.ImportStatement)
+		if !ok {
+			continue
+		}
+
+		importedModule, err := importer.Resolve(importStatement.Path, importStatement.Token, fileName)
+		if err != nil {
+			return nil, err
+		}
+		imports[ModuleName(importStatement.Path)] = importedModule
+	}
+
+	lowerer := nir.NewLowerer(errorCollector)
+	lowerer.SetImports(imports)
+	module := lowerer.Lower(program)
+	module.Name = ModuleName(strings.TrimSuffix(fileName, importer.Extension))
+	if errorCollector.HasErrors() {
+		return nil, fmt.Errorf("module %s failed to lower", resolvedPath)
+	}
+
+	return module, nil
+}
@@ -0,0 +1,67 @@
+package modules
+
+import (
+	"compiler/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeModule(t *testing.T, dir string, name string, source string) {
+	t.Helper()
+	err := os.WriteFile(filepath.Join(dir, name+".navi"), []byte(source), 0644)
+	require.NoError(t, err)
+}
+
+func TestResolveLoadsExportedFunction(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, dir, "mathutils", `export func square(x: int) -> int {
+		return x * x
+	}`)
+
+	importer := NewImporter([]string{dir}, ".navi")
+	module, err := importer.Resolve("mathutils", token.Token{Value: "import"}, "main.navi")
+
+	require.NoError(t, err)
+	require.NotNil(t, module)
+	assert.Contains(t, module.Exports, "square")
+}
+
+func TestResolveCachesAlreadyLoadedModule(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, dir, "mathutils", `export func square(x: int) -> int {
+		return x * x
+	}`)
+
+	importer := NewImporter([]string{dir}, ".navi")
+	first, err := importer.Resolve("mathutils", token.Token{Value: "import"}, "a.navi")
+	require.NoError(t, err)
+
+	second, err := importer.Resolve("mathutils", token.Token{Value: "import"}, "b.navi")
+	require.NoError(t, err)
+
+	assert.Same(t, first, second)
+}
+
+func TestResolveReportsMissingModule(t *testing.T) {
+	importer := NewImporter([]string{t.TempDir()}, ".navi")
+
+	_, err := importer.Resolve("nonexistent", token.Token{Value: "import", Line: 3, Column: 1}, "main.navi")
+
+	require.Error(t, err)
+}
+
+func TestResolveDetectsImportCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, dir, "a", `import "b"`)
+	writeModule(t, dir, "b", `import "a"`)
+
+	importer := NewImporter([]string{dir}, ".navi")
+
+	_, err := importer.Resolve("a", token.Token{Value: "import"}, "main.navi")
+
+	require.Error(t, err)
+}
@@ -12,6 +12,14 @@ type BasicBlock struct {
 	Name         string
 	Instructions []instruction.Instruction
 	Terminator   instruction.Instruction
+
+	// Successors and Predecessors record the block's CFG edges, so
+	// passes like nir/ssa can build a dominator tree without re-deriving
+	// control flow from terminators. Populated by Builder.BuildBranch/
+	// BuildCondBranch via AddSuccessor as if/else and short-circuit
+	// lowering split a function into multiple blocks.
+	Successors   []*BasicBlock
+	Predecessors []*BasicBlock
 }
 
 func NewBasicBlock(name string) *BasicBlock {
@@ -50,3 +58,10 @@ func (block *BasicBlock) String() string {
 func (block *BasicBlock) AddInstruction(inst instruction.Instruction) {
 	block.Instructions = append(block.Instructions, inst)
 }
+
+// AddSuccessor records a CFG edge from block to target, registering block
+// as one of target's predecessors at the same time.
+func (block *BasicBlock) AddSuccessor(target *BasicBlock) {
+	block.Successors = append(block.Successors, target)
+	target.Predecessors = append(target.Predecessors, block)
+}
@@ -1,6 +1,7 @@
 package nir
 
 import (
+	"compiler/constant"
 	"compiler/nir/instruction"
 	"compiler/nir/value"
 	"compiler/types"
@@ -9,12 +10,34 @@ import (
 type Builder struct {
 	nextTemporaryID int
 	currentBlock    *BasicBlock
+	currentLoc      SourceLoc
+	locations       map[instruction.Instruction]SourceLoc
 }
 
 func NewBuilder() *Builder {
 	return &Builder{
 		nextTemporaryID: 0,
 		currentBlock:    nil,
+		locations:       make(map[instruction.Instruction]SourceLoc),
+	}
+}
+
+// SetCurrentLoc records the source location that subsequent BuildXxx calls
+// should be attributed to, until the next call changes it.
+func (builder *Builder) SetCurrentLoc(loc SourceLoc) {
+	builder.currentLoc = loc
+}
+
+// Locations returns the source location recorded for each instruction this
+// builder produced, for attaching !DILocation metadata in the LLVM backend.
+func (builder *Builder) Locations() map[instruction.Instruction]SourceLoc {
+	return builder.locations
+}
+
+// record remembers inst's current source location, if one was set.
+func (builder *Builder) record(inst instruction.Instruction) {
+	if inst != nil && builder.currentLoc.HasLoc() {
+		builder.locations[inst] = builder.currentLoc
 	}
 }
 
@@ -32,11 +55,11 @@ func (builder *Builder) CreateTemporary(temporaryType types.Type) value.Value {
 }
 
 func (builder *Builder) CreateConstantInt(val int) value.Value {
-	return value.NewConstant(val, types.Int)
+	return value.NewConstant(constant.MakeInt64(int64(val)), types.Int)
 }
 
 func (builder *Builder) CreateConstantString(val string) value.Value {
-	return value.NewConstant(val, types.String)
+	return value.NewConstant(constant.MakeString(val), types.String)
 }
 
 func (builder *Builder) CreateVariable(name string, variableType types.Type) value.Value {
@@ -49,6 +72,7 @@ func (builder *Builder) BuildAlloc(name string, allocateType types.Type) value.V
 
 	if builder.currentBlock != nil {
 		builder.currentBlock.AddInstruction(allocInstruction)
+		builder.record(allocInstruction)
 	}
 
 	return variable
@@ -59,6 +83,7 @@ func (builder *Builder) BuildStore(destination value.Value, val value.Value) {
 
 	if builder.currentBlock != nil {
 		builder.currentBlock.AddInstruction(storeInstruction)
+		builder.record(storeInstruction)
 	}
 }
 
@@ -68,18 +93,25 @@ func (builder *Builder) BuildLoad(source value.Value) value.Value {
 
 	if builder.currentBlock != nil {
 		builder.currentBlock.AddInstruction(loadInstruction)
+		builder.record(loadInstruction)
 	}
 
 	return temporary
 }
 
 func (builder *Builder) BuildBinary(left value.Value, right value.Value, operator instruction.BinaryOperator) value.Value {
-	temporary := builder.CreateTemporary(left.Type())
+	resultType := left.Type()
+	if operator.IsComparison() {
+		resultType = types.Bool
+	}
+
+	temporary := builder.CreateTemporary(resultType)
 
 	binaryInstruction := instruction.NewBinaryInstruction(temporary, operator, left, right)
 
 	if builder.currentBlock != nil {
 		builder.currentBlock.AddInstruction(binaryInstruction)
+		builder.record(binaryInstruction)
 	}
 
 	return temporary
@@ -96,20 +128,166 @@ func (builder *Builder) BuildCall(functionName string, arguments []value.Value,
 
 	if builder.currentBlock != nil {
 		builder.currentBlock.AddInstruction(callInstruction)
+		builder.record(callInstruction)
+	}
+
+	return result
+}
+
+// BuildMethodCall is BuildCall for a method dispatch (an ast.CallExpression
+// whose Function is an ast.MemberExpression): receiver is passed as the
+// callee's implicit first argument, ahead of arguments.
+func (builder *Builder) BuildMethodCall(functionName string, receiver value.Value, arguments []value.Value, returnType types.Type) value.Value {
+	var result value.Value = nil
+
+	if returnType != nil {
+		result = builder.CreateTemporary(returnType)
+	}
+
+	callInstruction := instruction.NewMethodCallInstruction(result, functionName, receiver, arguments)
+
+	if builder.currentBlock != nil {
+		builder.currentBlock.AddInstruction(callInstruction)
+		builder.record(callInstruction)
 	}
 
 	return result
 }
 
+// BuildGEP computes the address of field index within a class instance,
+// for lowering `object.field` (ast.MemberExpression) before a Load or
+// Store operates on the result. Like BuildAlloc's, the result is
+// conceptually a pointer even though fieldType is the field's value type.
+func (builder *Builder) BuildGEP(object value.Value, index int, fieldType types.Type) value.Value {
+	temporary := builder.CreateTemporary(fieldType)
+	gepInstruction := instruction.NewGEPInstruction(temporary, object, index)
+
+	if builder.currentBlock != nil {
+		builder.currentBlock.AddInstruction(gepInstruction)
+		builder.record(gepInstruction)
+	}
+
+	return temporary
+}
+
+// BuildExtract pulls element index out of a tuple-typed source (e.g. the
+// result of a multi-value return call), yielding a new temporary of
+// elementType.
+func (builder *Builder) BuildExtract(source value.Value, index int, elementType types.Type) value.Value {
+	temporary := builder.CreateTemporary(elementType)
+
+	extractInstruction := instruction.NewExtractInstruction(temporary, source, index)
+
+	if builder.currentBlock != nil {
+		builder.currentBlock.AddInstruction(extractInstruction)
+		builder.record(extractInstruction)
+	}
+
+	return temporary
+}
+
 func (builder *Builder) BuildReturn(val value.Value) {
 	returnInst := instruction.NewReturnInstruction(val)
 
 	if builder.currentBlock != nil {
 		builder.currentBlock.Terminator = returnInst
+		builder.record(returnInst)
+	}
+}
+
+// BuildReturnMulti terminates the current block with a return that
+// yields every value in vals, for `return a, b` style statements.
+func (builder *Builder) BuildReturnMulti(vals []value.Value) {
+	returnInst := instruction.NewReturnMultiInstruction(vals)
+
+	if builder.currentBlock != nil {
+		builder.currentBlock.Terminator = returnInst
+		builder.record(returnInst)
+	}
+}
+
+// BuildBranch terminates the current block with an unconditional jump to
+// target, recording the CFG edge via AddSuccessor.
+func (builder *Builder) BuildBranch(target *BasicBlock) {
+	branchInst := instruction.NewBranchInstruction(target.Name)
+
+	if builder.currentBlock != nil {
+		builder.currentBlock.Terminator = branchInst
+		builder.currentBlock.AddSuccessor(target)
+		builder.record(branchInst)
+	}
+}
+
+// BuildCondBranch terminates the current block with a jump to trueBlock
+// when condition is non-zero, falseBlock otherwise, recording both CFG
+// edges via AddSuccessor.
+func (builder *Builder) BuildCondBranch(condition value.Value, trueBlock *BasicBlock, falseBlock *BasicBlock) {
+	condBranchInst := instruction.NewCondBranchInstruction(condition, trueBlock.Name, falseBlock.Name)
+
+	if builder.currentBlock != nil {
+		builder.currentBlock.Terminator = condBranchInst
+		builder.currentBlock.AddSuccessor(trueBlock)
+		builder.currentBlock.AddSuccessor(falseBlock)
+		builder.record(condBranchInst)
 	}
 }
 
+// BuildConvert changes source's representation to targetType, inserting
+// a sign-/zero-extend, truncate, or float-extend/truncate as appropriate.
+// Returns source unchanged if it's already targetType.
+func (builder *Builder) BuildConvert(source value.Value, targetType types.Type) value.Value {
+	sourceType := source.Type()
+	if sourceType.Equals(targetType) {
+		return source
+	}
+
+	var kind instruction.ConvertKind
+	switch {
+	case types.IsFloat(sourceType) && types.IsFloat(targetType):
+		if types.FloatWidth(targetType) > types.FloatWidth(sourceType) {
+			kind = instruction.FloatExtend
+		} else {
+			kind = instruction.FloatTruncate
+		}
+	case types.IntWidth(targetType) > types.IntWidth(sourceType):
+		if types.IsUnsigned(sourceType) {
+			kind = instruction.ZeroExtend
+		} else {
+			kind = instruction.SignExtend
+		}
+	default:
+		kind = instruction.Truncate
+	}
+
+	temporary := builder.CreateTemporary(targetType)
+	convertInst := instruction.NewConvertInstruction(temporary, kind, source)
+
+	if builder.currentBlock != nil {
+		builder.currentBlock.AddInstruction(convertInst)
+		builder.record(convertInst)
+	}
+
+	return temporary
+}
+
+// BuildPhi inserts a non-terminator PhiInstruction at the current block,
+// selecting one of incoming's values depending on which predecessor
+// control arrived from. Used by short-circuiting && / ||, whose result
+// depends on which of the original or rhs block was last executed.
+func (builder *Builder) BuildPhi(phiType types.Type, incoming []instruction.PhiIncoming) value.Value {
+	temporary := builder.CreateTemporary(phiType)
+	phiInst := instruction.NewPhiInstruction(temporary, incoming)
+
+	if builder.currentBlock != nil {
+		builder.currentBlock.AddInstruction(phiInst)
+		builder.record(phiInst)
+	}
+
+	return temporary
+}
+
 func (builder *Builder) Reset() {
 	builder.nextTemporaryID = 0
 	builder.currentBlock = nil
+	builder.locations = make(map[instruction.Instruction]SourceLoc)
 }
@@ -1,6 +1,7 @@
 package nir
 
 import (
+	"compiler/nir/instruction"
 	"compiler/types"
 	"fmt"
 	"strings"
@@ -27,6 +28,31 @@ type Function struct {
 	Parameters  []Parameter
 	ReturnType  types.Type
 	BasicBlocks []*BasicBlock
+
+	// IsExtern is true for a body-less declaration of a function defined
+	// elsewhere (typically a C library function, e.g. `extern func
+	// printf(fmt: string, ...) -> int`). An extern function has no
+	// BasicBlocks; the LLVM backend adds it via llvm.AddFunction without
+	// emitting a body or entry block.
+	IsExtern bool
+
+	// IsVariadic is true when the function's parameter list ends with
+	// `...`, allowing callers to pass additional trailing arguments
+	// beyond Parameters with no further type checking.
+	IsVariadic bool
+
+	// Receiver is non-nil for a method lowered from a class body (see
+	// ast.ClassStatement): its Type names the receiving class, and the
+	// LLVM backend prepends a pointer to it as the function's implicit
+	// first parameter ("this"), ahead of Parameters.
+	Receiver *Parameter
+
+	// Locations maps each instruction to the source position it was lowered
+	// from, for DWARF/!DILocation emission in the backends. Instructions
+	// built before the builder was given any location are simply absent
+	// from the map; every later one, implicit returns included, carries
+	// the location last set with Builder.SetCurrentLoc.
+	Locations map[instruction.Instruction]SourceLoc
 }
 
 func NewFunction(name string, parameters []Parameter, returnType types.Type) *Function {
@@ -42,6 +68,38 @@ func (function *Function) AddBasicBlock(block *BasicBlock) {
 	function.BasicBlocks = append(function.BasicBlocks, block)
 }
 
+// DeclLine returns the earliest recorded source line among the function's
+// instructions, as a stand-in for the 'func' keyword's line until the
+// lowerer threads the declaration token itself into Function. Returns 0
+// (meaning "unknown") if no instruction has a recorded location.
+func (function *Function) DeclLine() int {
+	declLine := 0
+	for _, loc := range function.Locations {
+		if declLine == 0 || loc.Line < declLine {
+			declLine = loc.Line
+		}
+	}
+	return declLine
+}
+
+// Blocks returns the function's basic blocks, for passes (e.g. nir/ssa)
+// that operate over the CFG rather than the lowering-order list.
+func (function *Function) Blocks() []*BasicBlock {
+	return function.BasicBlocks
+}
+
+// CFGSuccessors returns every block's CFG successors keyed by the block
+// itself, so a pass (e.g. nir/ssa's dominator-tree construction) can walk
+// the whole control-flow graph without threading *BasicBlock.Successors
+// lookups through its own traversal.
+func (function *Function) CFGSuccessors() map[*BasicBlock][]*BasicBlock {
+	successors := make(map[*BasicBlock][]*BasicBlock, len(function.BasicBlocks))
+	for _, block := range function.BasicBlocks {
+		successors[block] = block.Successors
+	}
+	return successors
+}
+
 func (function *Function) GetEntryBlock() *BasicBlock {
 	if len(function.BasicBlocks) == 0 {
 		return nil
@@ -68,6 +126,13 @@ func (function *Function) String() string {
 
 	builder.WriteString(fmt.Sprintf("Function: %s(", function.Name))
 
+	if function.Receiver != nil {
+		builder.WriteString(function.Receiver.String())
+		if len(function.Parameters) > 0 {
+			builder.WriteString(", ")
+		}
+	}
+
 	for i, param := range function.Parameters {
 		if i > 0 {
 			builder.WriteString(", ")
@@ -13,6 +13,13 @@ const (
 	BinaryMultiply
 	BinaryDivide
 	BinaryModulo
+
+	BinaryEqual
+	BinaryNotEqual
+	BinaryLess
+	BinaryLessEqual
+	BinaryGreater
+	BinaryGreaterEqual
 )
 
 func (operator BinaryOperator) String() string {
@@ -27,11 +34,30 @@ func (operator BinaryOperator) String() string {
 		return "Divide"
 	case BinaryModulo:
 		return "Modulo"
+	case BinaryEqual:
+		return "Equal"
+	case BinaryNotEqual:
+		return "NotEqual"
+	case BinaryLess:
+		return "Less"
+	case BinaryLessEqual:
+		return "LessEqual"
+	case BinaryGreater:
+		return "Greater"
+	case BinaryGreaterEqual:
+		return "GreaterEqual"
 	default:
 		return "Unknown"
 	}
 }
 
+// IsComparison reports whether operator yields a bool rather than an
+// operand-typed result, so BuildBinary knows what temporary type to
+// allocate for its result.
+func (operator BinaryOperator) IsComparison() bool {
+	return operator >= BinaryEqual && operator <= BinaryGreaterEqual
+}
+
 // BinaryInstruction performs binary operations (like +, -, *, /, %)
 // Example: %result = Add(%left, %right)
 type BinaryInstruction struct {
@@ -0,0 +1,66 @@
+package instruction
+
+import (
+	"compiler/nir/value"
+	"fmt"
+)
+
+// BranchInstruction is an unconditional jump to Target, a block Name
+// rather than a *nir.BasicBlock, since this package is imported by
+// compiler/nir and can't import it back.
+type BranchInstruction struct {
+	target string
+}
+
+func NewBranchInstruction(target string) *BranchInstruction {
+	return &BranchInstruction{target: target}
+}
+
+func (branch *BranchInstruction) String() string {
+	return fmt.Sprintf("Branch(%s)", branch.target)
+}
+
+func (branch *BranchInstruction) GetResult() value.Value {
+	return nil
+}
+
+func (branch *BranchInstruction) GetTarget() string {
+	return branch.target
+}
+
+// CondBranchInstruction jumps to TrueTarget when Condition is non-zero,
+// FalseTarget otherwise. Both targets are block Names for the same
+// import-cycle reason as BranchInstruction.Target.
+type CondBranchInstruction struct {
+	condition   value.Value
+	trueTarget  string
+	falseTarget string
+}
+
+func NewCondBranchInstruction(condition value.Value, trueTarget, falseTarget string) *CondBranchInstruction {
+	return &CondBranchInstruction{
+		condition:   condition,
+		trueTarget:  trueTarget,
+		falseTarget: falseTarget,
+	}
+}
+
+func (condBranch *CondBranchInstruction) String() string {
+	return fmt.Sprintf("CondBranch(%s, %s, %s)", condBranch.condition.String(), condBranch.trueTarget, condBranch.falseTarget)
+}
+
+func (condBranch *CondBranchInstruction) GetResult() value.Value {
+	return nil
+}
+
+func (condBranch *CondBranchInstruction) GetCondition() value.Value {
+	return condBranch.condition
+}
+
+func (condBranch *CondBranchInstruction) GetTrueTarget() string {
+	return condBranch.trueTarget
+}
+
+func (condBranch *CondBranchInstruction) GetFalseTarget() string {
+	return condBranch.falseTarget
+}
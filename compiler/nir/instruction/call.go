@@ -11,6 +11,11 @@ type CallInstruction struct {
 	result       value.Value
 	functionName string
 	arguments    []value.Value
+
+	// receiver is non-nil for a method call (an ast.CallExpression whose
+	// Function is an ast.MemberExpression), holding the instance pointer
+	// passed as the callee's implicit first parameter.
+	receiver value.Value
 }
 
 func NewCallInstruction(result value.Value, functionName string, arguments []value.Value) *CallInstruction {
@@ -21,6 +26,17 @@ func NewCallInstruction(result value.Value, functionName string, arguments []val
 	}
 }
 
+// NewMethodCallInstruction is NewCallInstruction plus the receiver
+// instance a method call dispatches through.
+func NewMethodCallInstruction(result value.Value, functionName string, receiver value.Value, arguments []value.Value) *CallInstruction {
+	return &CallInstruction{
+		result:       result,
+		functionName: functionName,
+		arguments:    arguments,
+		receiver:     receiver,
+	}
+}
+
 func (call *CallInstruction) String() string {
 	args := "["
 	for i, arg := range call.arguments {
@@ -31,10 +47,15 @@ func (call *CallInstruction) String() string {
 	}
 	args += "]"
 
+	receiver := ""
+	if call.receiver != nil {
+		receiver = fmt.Sprintf("%s, ", call.receiver.String())
+	}
+
 	if call.result != nil {
-		return fmt.Sprintf("%s = Call(%s, %s)", call.result.String(), call.functionName, args)
+		return fmt.Sprintf("%s = Call(%s, %s%s)", call.result.String(), call.functionName, receiver, args)
 	}
-	return fmt.Sprintf("Call(%s, %s)", call.functionName, args)
+	return fmt.Sprintf("Call(%s, %s%s)", call.functionName, receiver, args)
 }
 
 func (call *CallInstruction) GetResult() value.Value {
@@ -48,3 +69,9 @@ func (call *CallInstruction) GetFunctionName() string {
 func (call *CallInstruction) GetArguments() []value.Value {
 	return call.arguments
 }
+
+// GetReceiver returns the instance a method call dispatches through, or
+// nil for an ordinary function call.
+func (call *CallInstruction) GetReceiver() value.Value {
+	return call.receiver
+}
@@ -0,0 +1,69 @@
+package instruction
+
+import (
+	"compiler/nir/value"
+	"fmt"
+)
+
+// ConvertKind identifies which width-changing conversion a
+// ConvertInstruction performs.
+type ConvertKind int
+
+const (
+	SignExtend    ConvertKind = iota // widen a signed/unsized int, e.g. i32 -> i64
+	ZeroExtend                       // widen an unsigned int, e.g. u32 -> u64
+	Truncate                         // narrow an int, e.g. i64 -> i32
+	FloatExtend                      // widen a float, e.g. f32 -> f64
+	FloatTruncate                    // narrow a float, e.g. f64 -> f32
+)
+
+func (kind ConvertKind) String() string {
+	switch kind {
+	case SignExtend:
+		return "SignExtend"
+	case ZeroExtend:
+		return "ZeroExtend"
+	case Truncate:
+		return "Truncate"
+	case FloatExtend:
+		return "FloatExtend"
+	case FloatTruncate:
+		return "FloatTruncate"
+	default:
+		return "Unknown"
+	}
+}
+
+// ConvertInstruction changes source's representation to result's type,
+// bridging mixed-width arithmetic between differently-sized int/float
+// primitives (e.g. storing an i32 value into an i64 variable).
+// Example: %1 = SignExtend(%0)
+type ConvertInstruction struct {
+	result value.Value
+	kind   ConvertKind
+	source value.Value
+}
+
+func NewConvertInstruction(result value.Value, kind ConvertKind, source value.Value) *ConvertInstruction {
+	return &ConvertInstruction{
+		result: result,
+		kind:   kind,
+		source: source,
+	}
+}
+
+func (convert *ConvertInstruction) GetResult() value.Value {
+	return convert.result
+}
+
+func (convert *ConvertInstruction) GetKind() ConvertKind {
+	return convert.kind
+}
+
+func (convert *ConvertInstruction) GetSource() value.Value {
+	return convert.source
+}
+
+func (convert *ConvertInstruction) String() string {
+	return fmt.Sprintf("%s = %s(%s)", convert.result.String(), convert.kind.String(), convert.source.String())
+}
@@ -0,0 +1,39 @@
+package instruction
+
+import (
+	"compiler/nir/value"
+	"fmt"
+)
+
+// ExtractInstruction pulls a single element out of a tuple-typed value,
+// e.g. the individual results of a multi-value return.
+// Example: %1 = Extract(%0, 1)
+type ExtractInstruction struct {
+	result value.Value
+	source value.Value
+	index  int
+}
+
+func NewExtractInstruction(result value.Value, source value.Value, index int) *ExtractInstruction {
+	return &ExtractInstruction{
+		result: result,
+		source: source,
+		index:  index,
+	}
+}
+
+func (extract *ExtractInstruction) String() string {
+	return fmt.Sprintf("%s = Extract(%s, %d)", extract.result.String(), extract.source.String(), extract.index)
+}
+
+func (extract *ExtractInstruction) GetResult() value.Value {
+	return extract.result
+}
+
+func (extract *ExtractInstruction) GetSource() value.Value {
+	return extract.source
+}
+
+func (extract *ExtractInstruction) GetIndex() int {
+	return extract.index
+}
@@ -0,0 +1,42 @@
+package instruction
+
+import (
+	"compiler/nir/value"
+	"fmt"
+)
+
+// GEPInstruction computes the address of a field within a class instance
+// (see types.ClassType), for lowering `object.field` (ast.MemberExpression)
+// before a Load or Store operates on the result. Its result, like
+// AllocInstruction's, is conceptually a pointer even though its recorded
+// Type is the field's value type rather than a pointer type.
+// Example: %1 = GEP(%0, 1)
+type GEPInstruction struct {
+	result value.Value
+	object value.Value
+	index  int
+}
+
+func NewGEPInstruction(result value.Value, object value.Value, index int) *GEPInstruction {
+	return &GEPInstruction{
+		result: result,
+		object: object,
+		index:  index,
+	}
+}
+
+func (gep *GEPInstruction) String() string {
+	return fmt.Sprintf("%s = GEP(%s, %d)", gep.result.String(), gep.object.String(), gep.index)
+}
+
+func (gep *GEPInstruction) GetResult() value.Value {
+	return gep.result
+}
+
+func (gep *GEPInstruction) GetObject() value.Value {
+	return gep.object
+}
+
+func (gep *GEPInstruction) GetIndex() int {
+	return gep.index
+}
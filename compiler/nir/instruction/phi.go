@@ -0,0 +1,67 @@
+package instruction
+
+import (
+	"compiler/nir/value"
+	"fmt"
+	"strings"
+)
+
+// PhiIncoming is one operand of a PhiInstruction: the value to take when
+// control arrives from Predecessor. Predecessor is the block's Name
+// rather than a *nir.BasicBlock, since this package is imported by
+// compiler/nir and can't import it back.
+type PhiIncoming struct {
+	Predecessor string
+	Value       value.Value
+}
+
+// PhiInstruction selects one of several incoming values depending on
+// which predecessor block control arrived from. It appears at the head of
+// a block with multiple predecessors, placed there either by
+// compiler/nir/ssa or by the lowerer's short-circuit && / || lowering
+// (see Builder.BuildPhi).
+// Example: %2 = Phi([then: %0, else: %1])
+type PhiInstruction struct {
+	result   value.Value
+	incoming []PhiIncoming
+}
+
+func NewPhiInstruction(result value.Value, incoming []PhiIncoming) *PhiInstruction {
+	return &PhiInstruction{
+		result:   result,
+		incoming: incoming,
+	}
+}
+
+func (phi *PhiInstruction) String() string {
+	parts := make([]string, len(phi.incoming))
+	for i, in := range phi.incoming {
+		value := "?"
+		if in.Value != nil {
+			value = in.Value.String()
+		}
+		parts[i] = fmt.Sprintf("%s: %s", in.Predecessor, value)
+	}
+	return fmt.Sprintf("%s = Phi([%s])", phi.result.String(), strings.Join(parts, ", "))
+}
+
+func (phi *PhiInstruction) GetResult() value.Value {
+	return phi.result
+}
+
+func (phi *PhiInstruction) GetIncoming() []PhiIncoming {
+	return phi.incoming
+}
+
+// AddIncoming records the value to use when control arrives from
+// predecessor, called once per predecessor during SSA renaming.
+func (phi *PhiInstruction) AddIncoming(predecessor string, val value.Value) {
+	phi.incoming = append(phi.incoming, PhiIncoming{Predecessor: predecessor, Value: val})
+}
+
+// SetIncoming replaces the incoming list wholesale, used by block-level
+// optimizations (e.g. nir/opt's RemoveUnreachableBlocks) that drop or
+// merge predecessors and need to rebuild it from scratch.
+func (phi *PhiInstruction) SetIncoming(incoming []PhiIncoming) {
+	phi.incoming = incoming
+}
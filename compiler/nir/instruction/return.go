@@ -3,10 +3,12 @@ package instruction
 import (
 	"compiler/nir/value"
 	"fmt"
+	"strings"
 )
 
 type ReturnInstruction struct {
-	value value.Value
+	value  value.Value
+	values []value.Value
 }
 
 func NewReturnInstruction(value value.Value) *ReturnInstruction {
@@ -15,7 +17,24 @@ func NewReturnInstruction(value value.Value) *ReturnInstruction {
 	}
 }
 
+// NewReturnMultiInstruction builds a return that yields more than one
+// value, e.g. `return a, b`. values must have at least two elements;
+// for zero or one values use NewReturnInstruction instead.
+func NewReturnMultiInstruction(values []value.Value) *ReturnInstruction {
+	return &ReturnInstruction{
+		value:  values[0],
+		values: values,
+	}
+}
+
 func (returnInst *ReturnInstruction) String() string {
+	if len(returnInst.values) > 1 {
+		parts := make([]string, len(returnInst.values))
+		for i, v := range returnInst.values {
+			parts[i] = v.String()
+		}
+		return fmt.Sprintf("Return(%s)", strings.Join(parts, ", "))
+	}
 	if returnInst.value != nil {
 		return fmt.Sprintf("Return(%s)", returnInst.value.String())
 	}
@@ -26,6 +45,14 @@ func (returnInst *ReturnInstruction) GetResult() value.Value {
 	return nil
 }
 
+// GetValue returns the instruction's sole return value, or the first of
+// several for a multi-value return. Use GetValues for the full list.
 func (returnInst *ReturnInstruction) GetValue() value.Value {
 	return returnInst.value
 }
+
+// GetValues returns every returned value. It is nil unless the return
+// statement yielded more than one value.
+func (returnInst *ReturnInstruction) GetValues() []value.Value {
+	return returnInst.values
+}
@@ -2,18 +2,38 @@ package nir
 
 import (
 	"fmt"
+	"io"
 	"strings"
 )
 
 type Module struct {
 	Name      string
 	Functions []*Function
+
+	// Imports holds every module this one brought in with `import "path"`,
+	// in declaration order, so GetQualifiedFunction can walk it to resolve
+	// a `pkg.func` call.
+	Imports []*Module
+
+	// Exports maps the name of every function declared `export func f(...)`
+	// to its lowered NIR function, letting an importing module's
+	// GetQualifiedFunction find it without searching all of Functions.
+	Exports map[string]*Function
+
+	// EventHandlers maps an `on <event_name>(...) { ... }` declaration's
+	// event name to its lowered NIR function (already present in
+	// Functions too), so the LLVM backend can build the global
+	// constructor-style table a host runtime iterates to wire callbacks
+	// (see codegen/llvm's ModuleConverter).
+	EventHandlers map[string]*Function
 }
 
 func NewModule(name string) *Module {
 	return &Module{
-		Name:      name,
-		Functions: make([]*Function, 0),
+		Name:          name,
+		Functions:     make([]*Function, 0),
+		Exports:       make(map[string]*Function),
+		EventHandlers: make(map[string]*Function),
 	}
 }
 
@@ -31,6 +51,25 @@ func (module *Module) GetFunction(name string) *Function {
 	return nil
 }
 
+// GetQualifiedFunction resolves a call of the form `pkg.funcName`, where
+// pkg names one of module's Imports by its module name. It returns
+// (nil, false) if pkg isn't an imported module and (nil, true) if pkg is
+// imported but funcName either doesn't exist there or isn't exported
+// (the importer's Lower pass reports the latter as an errors.TypeError,
+// since unlike "no such module" it's a mistake about a module that does
+// resolve).
+func (module *Module) GetQualifiedFunction(pkg string, funcName string) (function *Function, isImportedModule bool) {
+	for _, imported := range module.Imports {
+		if imported.Name != pkg {
+			continue
+		}
+
+		return imported.Exports[funcName], true
+	}
+
+	return nil, false
+}
+
 func (module *Module) IsComplete() bool {
 	if len(module.Functions) == 0 {
 		return false
@@ -71,3 +110,12 @@ func (module *Module) String() string {
 
 	return builder.String()
 }
+
+// DumpModule writes module's basic blocks and instructions to w in the same
+// form String() produces, for a `--dump-nir` CLI flag to print straight to
+// stdout rather than going through debug.Dumper's `-dump=nir` file-per-phase
+// mechanism (see compiler/debug).
+func DumpModule(w io.Writer, module *Module) error {
+	_, err := io.WriteString(w, module.String())
+	return err
+}
@@ -5,35 +5,186 @@ import (
 	"compiler/errors"
 	"compiler/nir/instruction"
 	"compiler/nir/value"
+	"compiler/sema"
 	"compiler/types"
 	"fmt"
+	"strings"
 )
 
+// stringConcatFunctionName is the runtime function `+` on two strings
+// lowers to, since NIR has no string-typed BinaryInstruction. The LLVM
+// backend declares it lazily on first use (see codegen/llvm/runtime).
+const stringConcatFunctionName = "naviary_string_concat"
+
 // Lowerer converts AST to NIR
 // Lowering is the process of transforming high-level AST into low-level NIR (Naviary Intermediate Representation)
 type Lowerer struct {
 	builder         *Builder
 	currentFunction *Function
 	errorCollector  *errors.ErrorCollector
+
+	// functionReturnTypes records each top-level function's return type
+	// ahead of lowering any bodies, so a call expression can tell
+	// whether its callee returns a single value or a TupleType. This is
+	// a stand-in for a real symbol table.
+	functionReturnTypes map[string]types.Type
+
+	// blockIndex generates unique names for the then/else/merge blocks an
+	// if statement introduces, reset per function.
+	blockIndex int
+
+	// classes maps a class name to its lowered type, populated before any
+	// function or method is lowered so field/parameter type annotations
+	// and `this.field` accesses can resolve it.
+	classes map[string]*types.ClassType
+
+	// structs maps a struct name to its lowered type, populated the same
+	// way as classes but for method-less `struct Name { ... }`
+	// declarations (see ast.StructStatement).
+	structs map[string]*types.StructType
+
+	// currentReceiver is the current method's receiver parameter, or nil
+	// while lowering an ordinary function. It lets lowerThisExpression
+	// resolve `this` without threading it through every lower* call.
+	currentReceiver *Parameter
+
+	// variableTypes is a stand-in for a real symbol table: it records
+	// the declared type of each local
+	// variable lowered so far in the current function, so a member
+	// expression on a plain identifier (e.g. `p.x`) can resolve p's type.
+	variableTypes map[string]types.Type
+
+	// importedModules maps each `import "path"`'s module name to its
+	// already-lowered Module, as resolved and set by modules.Importer
+	// before Lower runs. It lets lowerCallExpression tell a qualified call
+	// (`pkg.func()`) apart from an ordinary method call (`instance.m()`).
+	importedModules map[string]*Module
+
+	// resolution is sema's name-resolution result for the program being
+	// lowered, as set by SetResolution before Lower runs. It is nil when
+	// no resolver ran (e.g. in lowerer tests that build an *ast.Program by
+	// hand), in which case lowerVariableAddress falls back to
+	// variableTypes exactly as it always has.
+	resolution *sema.Resolution
+
+	// traceEnabled and traceDepth back trace/untrace (see tracing.go):
+	// traceEnabled is checked on every trace call, and traceDepth is how
+	// many lowerXxx calls deep the current trace is, for indentation.
+	traceEnabled bool
+	traceDepth   int
+}
+
+// SetResolution records resolution, sema's name-resolution result for the
+// program Lower is about to run on, so lowerFunctionBody and
+// lowerIdentifier can use a
+// parameter's or variable's real declared type instead of guessing
+// types.Int. Must be called before Lower; see sema.Resolver.
+func (lowerer *Lowerer) SetResolution(resolution *sema.Resolution) {
+	lowerer.resolution = resolution
+}
+
+// SetImports records name to already-lowered Module, so a call of the
+// form `name.funcName(...)` lowers as a cross-module call instead of a
+// method call. Must be called before Lower; see modules.Importer, which
+// lowers every dependency first and wires the results in this way.
+func (lowerer *Lowerer) SetImports(imports map[string]*Module) {
+	lowerer.importedModules = imports
 }
 
 func NewLowerer(errorCollector *errors.ErrorCollector) *Lowerer {
 	return &Lowerer{
-		builder:         NewBuilder(),
-		currentFunction: nil,
-		errorCollector:  errorCollector,
+		builder:             NewBuilder(),
+		currentFunction:     nil,
+		errorCollector:      errorCollector,
+		functionReturnTypes: make(map[string]types.Type),
+		classes:             make(map[string]*types.ClassType),
+		structs:             make(map[string]*types.StructType),
+	}
+}
+
+// mangleMethodName builds the LLVM-visible name for a method, prefixed
+// with its class so multiple classes can share method names (e.g.
+// Point_sum and Vector_sum).
+func mangleMethodName(className string, methodName string) string {
+	return className + "_" + methodName
+}
+
+// mangleModuleFunctionName builds the LLVM-visible name for an exported
+// function called through a qualified `pkg.func()` call, the same way
+// mangleMethodName disambiguates a class's methods.
+func mangleModuleFunctionName(moduleName string, functionName string) string {
+	return moduleName + "_" + functionName
+}
+
+// moduleNameFromPath derives an import's module name from its path, the
+// same way modules.Importer does when it names the Module it loads for
+// that path: the last '/'-separated segment, e.g. "std/math" -> "math".
+func moduleNameFromPath(path string) string {
+	if index := strings.LastIndex(path, "/"); index != -1 {
+		return path[index+1:]
 	}
+	return path
 }
 
 func (lowerer *Lowerer) Lower(program *ast.Program) *Module {
 	module := NewModule("main")
 
+	// Classes and structs are registered before any return type is
+	// computed, since a function or method signature may name one
+	// (`func f() -> Point`).
+	for _, statement := range program.Statements {
+		switch stmt := statement.(type) {
+		case *ast.ClassStatement:
+			lowerer.classes[stmt.Name.Value] = lowerer.buildClassType(stmt)
+		case *ast.StructStatement:
+			lowerer.structs[stmt.Name.Value] = lowerer.buildStructType(stmt)
+		}
+	}
+
+	for _, statement := range program.Statements {
+		switch stmt := statement.(type) {
+		case *ast.FunctionStatement:
+			lowerer.functionReturnTypes[stmt.Name.Value] = lowerer.getReturnType(stmt)
+		case *ast.ClassStatement:
+			for _, method := range stmt.Methods {
+				mangledName := mangleMethodName(stmt.Name.Value, method.Name.Value)
+				lowerer.functionReturnTypes[mangledName] = lowerer.getReturnType(method)
+			}
+		}
+	}
+
 	for _, statement := range program.Statements {
 		switch stmt := statement.(type) {
 		case *ast.FunctionStatement:
 			function := lowerer.lowerFunction(stmt)
 			if function != nil {
 				module.AddFunction(function)
+				if stmt.Exported {
+					module.Exports[stmt.Name.Value] = function
+				}
+			}
+		case *ast.ClassStatement:
+			for _, method := range stmt.Methods {
+				function := lowerer.lowerMethod(stmt, method)
+				if function != nil {
+					module.AddFunction(function)
+				}
+			}
+		case *ast.StructStatement:
+			// Already registered into lowerer.structs by the pre-pass
+			// above; a struct has no methods of its own to lower.
+		case *ast.EventHandlerStatement:
+			function := lowerer.lowerEventHandler(stmt)
+			if function != nil {
+				module.AddFunction(function)
+				module.EventHandlers[stmt.EventName.Value] = function
+			}
+		case *ast.ImportStatement:
+			// The Module for this import was already lowered and handed
+			// to us via SetImports (see modules.Importer); just attach it
+			// to the result in import order.
+			if imported, ok := lowerer.importedModules[moduleNameFromPath(stmt.Path)]; ok {
+				module.Imports = append(module.Imports, imported)
 			}
 		default:
 			lowerer.errorCollector.Add(errors.SyntaxError,
@@ -47,46 +198,170 @@ func (lowerer *Lowerer) Lower(program *ast.Program) *Module {
 	return module
 }
 
+// buildClassType converts a class's field declarations to a types.ClassType
+// with a stable field layout, matched by index in the LLVM struct the
+// backend generates for it.
+func (lowerer *Lowerer) buildClassType(classStmt *ast.ClassStatement) *types.ClassType {
+	fields := make([]types.ClassField, len(classStmt.Fields))
+	for i, field := range classStmt.Fields {
+		fields[i] = types.ClassField{
+			Name: field.Name.Value,
+			Type: lowerer.getType(&field.Type),
+		}
+	}
+
+	return &types.ClassType{
+		Name:   classStmt.Name.Value,
+		Fields: fields,
+	}
+}
+
+// buildStructType converts a struct's field declarations to a
+// types.StructType with a stable field layout, matched by index in the
+// LLVM struct the backend generates for it.
+func (lowerer *Lowerer) buildStructType(structStmt *ast.StructStatement) *types.StructType {
+	fields := make([]types.StructField, len(structStmt.Fields))
+	for i, field := range structStmt.Fields {
+		fields[i] = types.StructField{
+			Name: field.Name.Value,
+			Type: lowerer.getType(&field.Type),
+		}
+	}
+
+	return &types.StructType{
+		Name:   structStmt.Name.Value,
+		Fields: fields,
+	}
+}
+
+// getReturnType computes a function's NIR return type from its AST
+// signature: a TupleType for multiple declared return types, the single
+// declared type, or types.Nil for a void function (main always returns
+// int, per lowerFunction).
+func (lowerer *Lowerer) getReturnType(astFunc *ast.FunctionStatement) types.Type {
+	if astFunc.Name.Value == "main" {
+		return types.Int
+	}
+
+	if len(astFunc.ReturnTypes) > 1 {
+		elements := make([]types.Type, len(astFunc.ReturnTypes))
+		for i, typeAnnotation := range astFunc.ReturnTypes {
+			elements[i] = lowerer.getType(typeAnnotation)
+		}
+		return &types.TupleType{Elements: elements}
+	}
+
+	if astFunc.ReturnType != nil {
+		return lowerer.getType(astFunc.ReturnType)
+	}
+
+	return types.Nil
+}
+
 func (lowerer *Lowerer) lowerFunction(astFunc *ast.FunctionStatement) *Function {
+	return lowerer.lowerFunctionBody(astFunc.Name.Value, astFunc, nil)
+}
+
+// lowerMethod lowers one of classStmt's methods to a NIR function whose
+// name is mangled with its class (see mangleMethodName) and whose first
+// parameter is an implicit `this` pointing at the receiving instance.
+func (lowerer *Lowerer) lowerMethod(classStmt *ast.ClassStatement, method *ast.FunctionStatement) *Function {
+	receiver := NewParameter("this", lowerer.classes[classStmt.Name.Value])
+	mangledName := mangleMethodName(classStmt.Name.Value, method.Name.Value)
+	return lowerer.lowerFunctionBody(mangledName, method, &receiver)
+}
+
+// mangleEventHandlerName builds the LLVM-visible name for an `on`
+// handler, the same mangling convention mangleMethodName and
+// mangleModuleFunctionName use for methods and qualified calls - here
+// disambiguating a handler from any ordinary function that happens to
+// share the event's name.
+func mangleEventHandlerName(eventName string) string {
+	return "naviary_on_" + eventName
+}
+
+// lowerEventHandler lowers an `on <event>(...) { ... }` declaration to a
+// NIR function named naviary_on_<event> (see mangleEventHandlerName),
+// reusing lowerFunctionBody via a throwaway *ast.FunctionStatement built
+// from the handler's Token/Parameters/Body: a handler always returns
+// Nil (getReturnType's default) and is never extern, variadic, or a
+// method, so there's nothing else for lowerFunctionBody to see.
+func (lowerer *Lowerer) lowerEventHandler(handler *ast.EventHandlerStatement) *Function {
+	mangledName := mangleEventHandlerName(handler.EventName.Value)
+	asFunction := &ast.FunctionStatement{
+		Token:      handler.Token,
+		Name:       handler.EventName,
+		Parameters: handler.Parameters,
+		Body:       handler.Body,
+	}
+	return lowerer.lowerFunctionBody(mangledName, asFunction, nil)
+}
+
+// lowerFunctionBody lowers a function or method's parameters and body to
+// a NIR function named name, attaching receiver (non-nil only for a
+// method) as its implicit first parameter.
+func (lowerer *Lowerer) lowerFunctionBody(name string, astFunc *ast.FunctionStatement, receiver *Parameter) *Function {
 	// Reset builder for new function
 	lowerer.builder.Reset()
+	lowerer.blockIndex = 0
+	lowerer.variableTypes = make(map[string]types.Type)
+	lowerer.currentReceiver = receiver
 
 	// Convert parameters
 	var parameters []Parameter
 	for _, param := range astFunc.Parameters {
-		// For now, assume all parameters are int type
-		// TODO: Use type annotations when type system is implemented
+		// sema.Resolver recorded each parameter's declared type against
+		// its *ast.Identifier node; fall back to int when no resolver ran
+		// (see resolution's doc comment).
+		var paramType types.Type = types.Int
+		if lowerer.resolution != nil {
+			if symbol, ok := lowerer.resolution.SymbolOf(param.Name); ok {
+				paramType = symbol.Type
+			}
+		}
+		// The resolver types a class or struct name as int; look it up in
+		// this lowerer's own tables instead.
+		if aggregate := lowerer.getType(&param.Type); types.IsAggregate(aggregate) {
+			paramType = aggregate
+		}
+
 		parameters = append(parameters, NewParameter(
 			param.Name.Value,
-			types.Int,
+			paramType,
 		))
+		lowerer.variableTypes[param.Name.Value] = paramType
 	}
 
 	// Determine return type
-	// For now, default to nil
-	// TODO: Use return type annotation when type system is implemented
-	var returnType types.Type = types.Nil
-
-	if astFunc.Name.Value == "main" {
-		returnType = types.Int
-	} else if astFunc.ReturnType != nil {
-		returnType = lowerer.getType(astFunc.ReturnType)
-	}
+	returnType := lowerer.getReturnType(astFunc)
 
 	// Create NIR function
-	function := NewFunction(astFunc.Name.Value, parameters, returnType)
+	function := NewFunction(name, parameters, returnType)
+	function.IsExtern = astFunc.Extern
+	function.IsVariadic = astFunc.Variadic
+	function.Receiver = receiver
 	lowerer.currentFunction = function
 
+	// Extern functions are declarations only: they have no body or entry
+	// block, and are added to the LLVM module via llvm.AddFunction alone.
+	if astFunc.Extern {
+		return function
+	}
+
 	// Create entry block
 	entryBlock := NewBasicBlock("entry")
 	lowerer.builder.SetInsertBlock(entryBlock)
+	function.AddBasicBlock(entryBlock)
 
 	// Lower function body
 	lowerer.lowerBlockStatement(astFunc.Body)
 
-	// Add implicit return for void functions if missing
-	if !entryBlock.IsComplete() {
-		if astFunc.Name.Value == "main" {
+	// Add implicit return for void functions if missing. If/else lowering
+	// may have left the builder's insert point on a later block (e.g. an
+	// if statement's merge block), so check that one rather than entry.
+	finalBlock := lowerer.builder.GetInsertBlock()
+	if finalBlock != nil && !finalBlock.IsComplete() {
+		if name == "main" {
 			lowerer.builder.BuildReturn(lowerer.builder.CreateConstantInt(0))
 
 		} else {
@@ -94,7 +369,7 @@ func (lowerer *Lowerer) lowerFunction(astFunc *ast.FunctionStatement) *Function
 		}
 	}
 
-	function.AddBasicBlock(entryBlock)
+	function.Locations = lowerer.builder.Locations()
 
 	return function
 }
@@ -108,13 +383,24 @@ func (lowerer *Lowerer) lowerBlockStatement(block *ast.BlockStatement) {
 
 // lowerStatement lowers a single statement
 func (lowerer *Lowerer) lowerStatement(statement ast.Statement) {
+	defer untrace(trace(lowerer, "lowerStatement"))
+
 	switch stmt := statement.(type) {
 	case *ast.LetStatement:
+		lowerer.builder.SetCurrentLoc(SourceLoc{Line: stmt.Token.Line, Column: stmt.Token.Column})
 		lowerer.lowerLetStatement(stmt)
 	case *ast.ReturnStatement:
+		lowerer.builder.SetCurrentLoc(SourceLoc{Line: stmt.Token.Line, Column: stmt.Token.Column})
 		lowerer.lowerReturnStatement(stmt)
 	case *ast.ExpressionStatement:
+		lowerer.builder.SetCurrentLoc(SourceLoc{Line: stmt.Token.Line, Column: stmt.Token.Column})
 		lowerer.lowerExpressionStatement(stmt)
+	case *ast.IfStatement:
+		lowerer.builder.SetCurrentLoc(SourceLoc{Line: stmt.Token.Line, Column: stmt.Token.Column})
+		lowerer.lowerIfStatement(stmt)
+	case *ast.WhileStatement:
+		lowerer.builder.SetCurrentLoc(SourceLoc{Line: stmt.Token.Line, Column: stmt.Token.Column})
+		lowerer.lowerWhileStatement(stmt)
 	default:
 		lowerer.errorCollector.Add(
 			errors.SyntaxError,
@@ -134,19 +420,74 @@ func (lowerer *Lowerer) lowerStatement(statement ast.Statement) {
 //	  %x = Alloc(int)
 //	  Store(%x, %2)
 func (lowerer *Lowerer) lowerLetStatement(letStmt *ast.LetStatement) {
+	defer untrace(trace(lowerer, "lowerLetStatement"))
+
+	if len(letStmt.Names) > 1 {
+		lowerer.lowerDestructuringLetStatement(letStmt)
+		return
+	}
+
 	// Lower the initialization expression
 	initValue := lowerer.lowerExpression(letStmt.Value)
 	if initValue == nil {
 		return
 	}
 
+	// If the let has an explicit type annotation, convert the initializer
+	// to that type (e.g. `let x: i32 = someI64Value`).
+	allocType := initValue.Type()
+	if letStmt.TypeAnnotation != nil {
+		allocType = lowerer.getType(letStmt.TypeAnnotation)
+		initValue = lowerer.builder.BuildConvert(initValue, allocType)
+	}
+
 	// Allocate variable
-	variable := lowerer.builder.BuildAlloc(letStmt.Name.Value, initValue.Type())
+	variable := lowerer.builder.BuildAlloc(letStmt.Name.Value, allocType)
+	lowerer.variableTypes[letStmt.Name.Value] = allocType
 
 	// Store initial value
 	lowerer.builder.BuildStore(variable, initValue)
 }
 
+// lowerDestructuringLetStatement lowers `let a, b = foo()`, binding each
+// name to one element of the tuple foo() returns.
+// Example: let a, b = foo()
+//
+//	→ %0 = Call(foo, [])   ; %0 : (int, int)
+//	  %1 = Extract(%0, 0)
+//	  %a = Alloc(int)
+//	  Store(%a, %1)
+//	  %2 = Extract(%0, 1)
+//	  %b = Alloc(int)
+//	  Store(%b, %2)
+func (lowerer *Lowerer) lowerDestructuringLetStatement(letStmt *ast.LetStatement) {
+	tupleValue := lowerer.lowerExpression(letStmt.Value)
+	if tupleValue == nil {
+		return
+	}
+
+	tupleType, ok := tupleValue.Type().(*types.TupleType)
+	if !ok || len(tupleType.Elements) != len(letStmt.Names) {
+		lowerer.errorCollector.Add(
+			errors.SyntaxError,
+			letStmt.Token.Line,
+			letStmt.Token.Column,
+			len(letStmt.Token.Value),
+			"cannot destructure %d name(s) from %s",
+			len(letStmt.Names),
+			tupleValue.Type().String(),
+		)
+		return
+	}
+
+	for i, name := range letStmt.Names {
+		elementValue := lowerer.builder.BuildExtract(tupleValue, i, tupleType.Elements[i])
+		variable := lowerer.builder.BuildAlloc(name.Value, tupleType.Elements[i])
+		lowerer.variableTypes[name.Value] = tupleType.Elements[i]
+		lowerer.builder.BuildStore(variable, elementValue)
+	}
+}
+
 // lowerReturnStatement lowers a return statement
 // Example: return x + 1
 //
@@ -155,6 +496,22 @@ func (lowerer *Lowerer) lowerLetStatement(letStmt *ast.LetStatement) {
 //	  %2 = Add(%0, %1)
 //	  Return(%2)
 func (lowerer *Lowerer) lowerReturnStatement(returnStmt *ast.ReturnStatement) {
+	defer untrace(trace(lowerer, "lowerReturnStatement"))
+
+	if len(returnStmt.ReturnValues) > 1 {
+		values := make([]value.Value, len(returnStmt.ReturnValues))
+		for i, expr := range returnStmt.ReturnValues {
+			lowered := lowerer.lowerExpression(expr)
+			if lowered == nil {
+				return
+			}
+			values[i] = lowered
+		}
+
+		lowerer.builder.BuildReturnMulti(values)
+		return
+	}
+
 	if returnStmt.ReturnValue == nil {
 		// Return void
 		lowerer.builder.BuildReturn(nil)
@@ -170,6 +527,100 @@ func (lowerer *Lowerer) lowerReturnStatement(returnStmt *ast.ReturnStatement) {
 	lowerer.builder.BuildReturn(returnValue)
 }
 
+// lowerIfStatement lowers `if cond { ... } else { ... }`, splitting the
+// current block into then/[else]/merge blocks joined by (Cond)Branch
+// terminators. A branch is non-zero-is-true, matching the bytecode VM's
+// isTruthy. An `else if` arrives here as a single-statement Alternative
+// block wrapping a nested *ast.IfStatement (see ast.IfStatement), so no
+// special-casing is needed beyond the ordinary recursive lowering below.
+// Example: if x { return 1 } else { return 2 }
+//
+//	→ CondBranch(%x, then0, else0)
+//	  then0: Return(1)
+//	  else0: Return(2)
+//	  merge0:
+func (lowerer *Lowerer) lowerIfStatement(ifStmt *ast.IfStatement) {
+	defer untrace(trace(lowerer, "lowerIfStatement"))
+
+	condition := lowerer.lowerExpression(ifStmt.Condition)
+	if condition == nil {
+		return
+	}
+
+	index := lowerer.blockIndex
+	lowerer.blockIndex++
+
+	thenBlock := NewBasicBlock(fmt.Sprintf("then%d", index))
+	mergeBlock := NewBasicBlock(fmt.Sprintf("merge%d", index))
+
+	var elseBlock *BasicBlock
+	if ifStmt.Alternative != nil {
+		elseBlock = NewBasicBlock(fmt.Sprintf("else%d", index))
+		lowerer.builder.BuildCondBranch(condition, thenBlock, elseBlock)
+	} else {
+		lowerer.builder.BuildCondBranch(condition, thenBlock, mergeBlock)
+	}
+
+	lowerer.currentFunction.AddBasicBlock(thenBlock)
+	lowerer.builder.SetInsertBlock(thenBlock)
+	lowerer.lowerBlockStatement(ifStmt.Consequence)
+	if !thenBlock.IsComplete() {
+		lowerer.builder.BuildBranch(mergeBlock)
+	}
+
+	if elseBlock != nil {
+		lowerer.currentFunction.AddBasicBlock(elseBlock)
+		lowerer.builder.SetInsertBlock(elseBlock)
+		lowerer.lowerBlockStatement(ifStmt.Alternative)
+		if !elseBlock.IsComplete() {
+			lowerer.builder.BuildBranch(mergeBlock)
+		}
+	}
+
+	lowerer.currentFunction.AddBasicBlock(mergeBlock)
+	lowerer.builder.SetInsertBlock(mergeBlock)
+}
+
+// lowerWhileStatement lowers `while cond { ... }` into a loop of cond/body
+// blocks joined by (Cond)Branch terminators: cond re-evaluates Condition
+// every iteration before branching into body or out to merge.
+// Example: while x { x = x - 1 }
+//
+//	→ Branch(cond0)
+//	  cond0: CondBranch(%x, body0, merge0)
+//	  body0: ...; Branch(cond0)
+//	  merge0:
+func (lowerer *Lowerer) lowerWhileStatement(whileStmt *ast.WhileStatement) {
+	defer untrace(trace(lowerer, "lowerWhileStatement"))
+
+	index := lowerer.blockIndex
+	lowerer.blockIndex++
+
+	condBlock := NewBasicBlock(fmt.Sprintf("cond%d", index))
+	bodyBlock := NewBasicBlock(fmt.Sprintf("body%d", index))
+	mergeBlock := NewBasicBlock(fmt.Sprintf("merge%d", index))
+
+	lowerer.builder.BuildBranch(condBlock)
+
+	lowerer.currentFunction.AddBasicBlock(condBlock)
+	lowerer.builder.SetInsertBlock(condBlock)
+	condition := lowerer.lowerExpression(whileStmt.Condition)
+	if condition == nil {
+		return
+	}
+	lowerer.builder.BuildCondBranch(condition, bodyBlock, mergeBlock)
+
+	lowerer.currentFunction.AddBasicBlock(bodyBlock)
+	lowerer.builder.SetInsertBlock(bodyBlock)
+	lowerer.lowerBlockStatement(whileStmt.Body)
+	if !bodyBlock.IsComplete() {
+		lowerer.builder.BuildBranch(condBlock)
+	}
+
+	lowerer.currentFunction.AddBasicBlock(mergeBlock)
+	lowerer.builder.SetInsertBlock(mergeBlock)
+}
+
 // lowerExpressionStatement lowers an expression statement
 // Example: print(42)
 //
@@ -182,6 +633,8 @@ func (lowerer *Lowerer) lowerExpressionStatement(exprStmt *ast.ExpressionStateme
 // lowerExpression lowers an expression to a NIR value
 // This is where complex nested expressions get flattened
 func (lowerer *Lowerer) lowerExpression(expr ast.Expression) value.Value {
+	defer untrace(trace(lowerer, "lowerExpression"))
+
 	switch expression := expr.(type) {
 	case *ast.IntegerLiteral:
 		return lowerer.lowerIntegerLiteral(expression)
@@ -193,6 +646,10 @@ func (lowerer *Lowerer) lowerExpression(expr ast.Expression) value.Value {
 		return lowerer.lowerBinaryExpression(expression)
 	case *ast.CallExpression:
 		return lowerer.lowerCallExpression(expression)
+	case *ast.ThisExpression:
+		return lowerer.lowerThisExpression(expression)
+	case *ast.MemberExpression:
+		return lowerer.lowerMemberExpression(expression)
 	default:
 		lowerer.errorCollector.Add(
 			errors.SyntaxError,
@@ -223,12 +680,131 @@ func (lowerer *Lowerer) lowerStringLiteral(literal *ast.StringLiteral) value.Val
 // lowerIdentifier converts an identifier to a load instruction
 // Example: x  →  %0 = Load(%x)
 func (lowerer *Lowerer) lowerIdentifier(identifier *ast.Identifier) value.Value {
-	// Create variable reference
-	// TODO: Look up actual variable from symbol table
-	variable := lowerer.builder.CreateVariable(identifier.Value, types.Int)
+	if lowerer.resolution != nil {
+		if symbol, ok := lowerer.resolution.SymbolOf(identifier); ok {
+			return lowerer.builder.BuildLoad(lowerer.builder.CreateVariable(symbol.Name, symbol.Type))
+		}
+	}
+
+	return lowerer.builder.BuildLoad(lowerer.lowerVariableAddress(identifier.Value))
+}
+
+// lowerVariableAddress resolves name to its Alloc-result variable, typed
+// from variableTypes when known. It's lowerIdentifier's fallback when no
+// sema.Resolution was attached (see SetResolution) or it holds no symbol
+// for the identifier, and lowerObjectAddress uses it for every plain
+// identifier whether or not a Resolution is attached. An unrecognized
+// name falls back to int.
+func (lowerer *Lowerer) lowerVariableAddress(name string) value.Value {
+	variableType, ok := lowerer.variableTypes[name]
+	if !ok {
+		variableType = types.Int
+	}
+
+	return lowerer.builder.CreateVariable(name, variableType)
+}
+
+// lowerThisExpression resolves `this` to the current method's receiver
+// variable directly, without the Load lowerIdentifier adds for ordinary
+// variables: like an AllocInstruction result, the receiver already *is*
+// the pointer that field access and method calls need.
+func (lowerer *Lowerer) lowerThisExpression(this *ast.ThisExpression) value.Value {
+	if lowerer.currentReceiver == nil {
+		lowerer.errorCollector.Add(errors.SyntaxError,
+			this.Token.Line, this.Token.Column, len(this.Token.Value),
+			"'this' used outside a method",
+		)
+		return nil
+	}
+
+	return lowerer.builder.CreateVariable(lowerer.currentReceiver.Name, lowerer.currentReceiver.Type)
+}
+
+// lowerObjectAddress resolves the object half of a member expression
+// (`object.field` or `object.method()`) to its address, without loading
+// it: `this` is already a pointer, and a plain local is itself an Alloc
+// result already addressed by name.
+func (lowerer *Lowerer) lowerObjectAddress(expr ast.Expression) value.Value {
+	switch expr := expr.(type) {
+	case *ast.ThisExpression:
+		return lowerer.lowerThisExpression(expr)
+	case *ast.Identifier:
+		return lowerer.lowerVariableAddress(expr.Value)
+	default:
+		lowerer.errorCollector.Add(errors.SyntaxError,
+			0, 0, 0,
+			"unsupported object expression in member access: %T",
+			expr,
+		)
+		return nil
+	}
+}
+
+// lowerFieldAddress resolves object.field (the object half of a
+// MemberExpression) to the field's address: a GEP off object, which must
+// be an instance of a known types.ClassType or types.StructType.
+func (lowerer *Lowerer) lowerFieldAddress(member *ast.MemberExpression) value.Value {
+	object := lowerer.lowerObjectAddress(member.Object)
+	if object == nil {
+		return nil
+	}
+
+	typeName, index, fieldType, ok := fieldLayout(object.Type(), member.Property.Value)
+	if typeName == "" {
+		lowerer.errorCollector.Add(errors.SyntaxError,
+			member.Token.Line, member.Token.Column, len(member.Token.Value),
+			"cannot access field %s on non-aggregate type %s",
+			member.Property.Value, object.Type().String(),
+		)
+		return nil
+	}
+	if !ok {
+		lowerer.errorCollector.Add(errors.SyntaxError,
+			member.Token.Line, member.Token.Column, len(member.Token.Value),
+			"%s has no field %s",
+			typeName, member.Property.Value,
+		)
+		return nil
+	}
+
+	return lowerer.builder.BuildGEP(object, index, fieldType)
+}
+
+// fieldLayout looks up fieldName's index and declared type within
+// objectType's layout, whether objectType is a types.ClassType or a
+// types.StructType (the only two aggregates with named fields). typeName
+// is empty when objectType is neither, distinguishing "not an aggregate"
+// from "aggregate with no such field" for lowerFieldAddress's error
+// message.
+func fieldLayout(objectType types.Type, fieldName string) (typeName string, index int, fieldType types.Type, ok bool) {
+	switch t := objectType.(type) {
+	case *types.ClassType:
+		index, ok = t.FieldIndex(fieldName)
+		if ok {
+			fieldType = t.Fields[index].Type
+		}
+		return t.Name, index, fieldType, ok
+	case *types.StructType:
+		index, ok = t.FieldIndex(fieldName)
+		if ok {
+			fieldType = t.Fields[index].Type
+		}
+		return t.Name, index, fieldType, ok
+	default:
+		return "", 0, nil, false
+	}
+}
 
-	// Load the value
-	return lowerer.builder.BuildLoad(variable)
+// lowerMemberExpression lowers `object.field` to a GEP for the field's
+// address followed by a Load.
+// Example: p.x  →  %0 = GEP(%p, 0); %1 = Load(%0)
+func (lowerer *Lowerer) lowerMemberExpression(member *ast.MemberExpression) value.Value {
+	address := lowerer.lowerFieldAddress(member)
+	if address == nil {
+		return nil
+	}
+
+	return lowerer.builder.BuildLoad(address)
 }
 
 // lowerBinaryExpression lowers a binary operation
@@ -238,6 +814,19 @@ func (lowerer *Lowerer) lowerIdentifier(identifier *ast.Identifier) value.Value
 //	  %1 = Constant(2)
 //	  %2 = Add(%0, %1)
 func (lowerer *Lowerer) lowerBinaryExpression(binary *ast.BinaryExpression) value.Value {
+	defer untrace(trace(lowerer, "lowerBinaryExpression"))
+
+	// && and || must not evaluate their RHS eagerly (it may have side
+	// effects that shouldn't run once the LHS has already decided the
+	// result), so they're split out before the operands below are lowered
+	// unconditionally.
+	switch binary.Operator {
+	case "&&":
+		return lowerer.lowerLogicalExpression(binary, true)
+	case "||":
+		return lowerer.lowerLogicalExpression(binary, false)
+	}
+
 	// Lower left and right operands first
 	left := lowerer.lowerExpression(binary.Left)
 	if left == nil {
@@ -252,6 +841,9 @@ func (lowerer *Lowerer) lowerBinaryExpression(binary *ast.BinaryExpression) valu
 	// Generate appropriate instruction based on operator
 	switch binary.Operator {
 	case "+":
+		if left.Type().Equals(types.String) && right.Type().Equals(types.String) {
+			return lowerer.builder.BuildCall(stringConcatFunctionName, []value.Value{left, right}, types.String)
+		}
 		return lowerer.builder.BuildBinary(left, right, instruction.BinaryAdd)
 	case "-":
 		return lowerer.builder.BuildBinary(left, right, instruction.BinarySubtract)
@@ -259,6 +851,18 @@ func (lowerer *Lowerer) lowerBinaryExpression(binary *ast.BinaryExpression) valu
 		return lowerer.builder.BuildBinary(left, right, instruction.BinaryMultiply)
 	case "/":
 		return lowerer.builder.BuildBinary(left, right, instruction.BinaryDivide)
+	case "==":
+		return lowerer.builder.BuildBinary(left, right, instruction.BinaryEqual)
+	case "!=":
+		return lowerer.builder.BuildBinary(left, right, instruction.BinaryNotEqual)
+	case "<":
+		return lowerer.builder.BuildBinary(left, right, instruction.BinaryLess)
+	case "<=":
+		return lowerer.builder.BuildBinary(left, right, instruction.BinaryLessEqual)
+	case ">":
+		return lowerer.builder.BuildBinary(left, right, instruction.BinaryGreater)
+	case ">=":
+		return lowerer.builder.BuildBinary(left, right, instruction.BinaryGreaterEqual)
 	default:
 		lowerer.errorCollector.Add(
 			errors.SyntaxError,
@@ -270,38 +874,189 @@ func (lowerer *Lowerer) lowerBinaryExpression(binary *ast.BinaryExpression) valu
 	}
 }
 
+// lowerLogicalExpression lowers a short-circuiting && or || by splitting
+// the current block: the LHS is evaluated in place, then a conditional
+// branch either jumps straight to merge with the short-circuit result
+// (false for &&, true for ||) or into a new rhs block to evaluate the
+// RHS, and merge picks between the two via a PhiInstruction.
+// Example: a && b
+//
+//	→ CondBranch(%a, rhs0, merge0)
+//	  rhs0: %1 = Load(%b); Branch(merge0)
+//	  merge0: %2 = Phi([entry: 0, rhs0: %1])
+func (lowerer *Lowerer) lowerLogicalExpression(binary *ast.BinaryExpression, isAnd bool) value.Value {
+	left := lowerer.lowerExpression(binary.Left)
+	if left == nil {
+		return nil
+	}
+
+	origBlock := lowerer.builder.GetInsertBlock()
+
+	index := lowerer.blockIndex
+	lowerer.blockIndex++
+
+	rhsBlock := NewBasicBlock(fmt.Sprintf("rhs%d", index))
+	mergeBlock := NewBasicBlock(fmt.Sprintf("merge%d", index))
+
+	shortCircuitValue := lowerer.builder.CreateConstantInt(0)
+	if isAnd {
+		lowerer.builder.BuildCondBranch(left, rhsBlock, mergeBlock)
+	} else {
+		shortCircuitValue = lowerer.builder.CreateConstantInt(1)
+		lowerer.builder.BuildCondBranch(left, mergeBlock, rhsBlock)
+	}
+
+	lowerer.currentFunction.AddBasicBlock(rhsBlock)
+	lowerer.builder.SetInsertBlock(rhsBlock)
+	right := lowerer.lowerExpression(binary.Right)
+	if right == nil {
+		return nil
+	}
+
+	// The block right wound up lowered into may not be rhsBlock itself
+	// (e.g. a nested && / || introduces further blocks), so the phi must
+	// record whichever block actually falls through to merge.
+	rhsEndBlock := lowerer.builder.GetInsertBlock()
+	if !rhsEndBlock.IsComplete() {
+		lowerer.builder.BuildBranch(mergeBlock)
+	}
+
+	lowerer.currentFunction.AddBasicBlock(mergeBlock)
+	lowerer.builder.SetInsertBlock(mergeBlock)
+
+	return lowerer.builder.BuildPhi(types.Int, []instruction.PhiIncoming{
+		{Predecessor: origBlock.Name, Value: shortCircuitValue},
+		{Predecessor: rhsEndBlock.Name, Value: right},
+	})
+}
+
 // lowerCallExpression lowers a function call
 // Example: print(42)
 //
 //	→ %0 = Constant(42)
 //	  Call(print, [%0])
+//
+// A call whose Function is an ast.MemberExpression (`p.distance()`) is a
+// method call instead, dispatched by lowerMethodCallExpression.
 func (lowerer *Lowerer) lowerCallExpression(call *ast.CallExpression) value.Value {
-	// Get function name
-	functionName := ""
-	if ident, ok := call.Function.(*ast.Identifier); ok {
-		functionName = ident.Value
-	} else {
+	defer untrace(trace(lowerer, "lowerCallExpression"))
+
+	switch function := call.Function.(type) {
+	case *ast.Identifier:
+		return lowerer.lowerFunctionCallExpression(function.Value, call.Arguments)
+	case *ast.MemberExpression:
+		if pkgIdent, ok := function.Object.(*ast.Identifier); ok {
+			if importedModule, isImport := lowerer.importedModules[pkgIdent.Value]; isImport {
+				return lowerer.lowerQualifiedCallExpression(importedModule, function, call.Arguments)
+			}
+		}
+		return lowerer.lowerMethodCallExpression(function, call.Arguments)
+	default:
 		lowerer.errorCollector.Add(
 			errors.SyntaxError,
 			0, 0, 0,
-			"Only simple function calls are supported",
+			"Only simple function and method calls are supported",
 		)
 		return nil
 	}
+}
+
+// lowerFunctionCallExpression lowers a call to a plain, top-level
+// function named functionName.
+func (lowerer *Lowerer) lowerFunctionCallExpression(functionName string, argExprs []ast.Expression) value.Value {
+	arguments, ok := lowerer.lowerArguments(argExprs)
+	if !ok {
+		return nil
+	}
+
+	// Look up the callee's return type, recorded by Lower's pre-pass.
+	// Builtins (e.g. print) aren't in the map and stay void, matching
+	// prior behavior.
+	returnType := lowerer.functionReturnTypes[functionName]
+	if returnType == types.Nil {
+		returnType = nil
+	}
 
-	// Lower arguments
+	return lowerer.builder.BuildCall(functionName, arguments, returnType)
+}
+
+// lowerMethodCallExpression lowers `object.method(args)`, dispatching to
+// the NIR function the method lowered to (see mangleMethodName), with
+// object's address passed as the implicit `this` argument.
+func (lowerer *Lowerer) lowerMethodCallExpression(member *ast.MemberExpression, argExprs []ast.Expression) value.Value {
+	object := lowerer.lowerObjectAddress(member.Object)
+	if object == nil {
+		return nil
+	}
+
+	classType, ok := object.Type().(*types.ClassType)
+	if !ok {
+		lowerer.errorCollector.Add(errors.SyntaxError,
+			member.Token.Line, member.Token.Column, len(member.Token.Value),
+			"cannot call method %s on non-class type %s",
+			member.Property.Value, object.Type().String(),
+		)
+		return nil
+	}
+
+	arguments, ok := lowerer.lowerArguments(argExprs)
+	if !ok {
+		return nil
+	}
+
+	functionName := mangleMethodName(classType.Name, member.Property.Value)
+
+	returnType := lowerer.functionReturnTypes[functionName]
+	if returnType == types.Nil {
+		returnType = nil
+	}
+
+	return lowerer.builder.BuildMethodCall(functionName, object, arguments, returnType)
+}
+
+// lowerQualifiedCallExpression lowers `pkg.func(args)`, where pkg names
+// an imported module (see lowerCallExpression). Unlike
+// lowerMethodCallExpression there's no implicit `this` argument: the
+// callee is an ordinary function, just namespaced by the module it was
+// imported from (see mangleModuleFunctionName).
+func (lowerer *Lowerer) lowerQualifiedCallExpression(importedModule *Module, member *ast.MemberExpression, argExprs []ast.Expression) value.Value {
+	exportedFunction, ok := importedModule.Exports[member.Property.Value]
+	if !ok {
+		lowerer.errorCollector.Add(errors.TypeError,
+			member.Token.Line, member.Token.Column, len(member.Token.Value),
+			"%s.%s is not an exported function of module %s",
+			importedModule.Name, member.Property.Value, importedModule.Name,
+		)
+		return nil
+	}
+
+	arguments, ok := lowerer.lowerArguments(argExprs)
+	if !ok {
+		return nil
+	}
+
+	functionName := mangleModuleFunctionName(importedModule.Name, member.Property.Value)
+
+	var returnType types.Type
+	if exportedFunction.ReturnType != nil && !exportedFunction.ReturnType.Equals(types.Nil) {
+		returnType = exportedFunction.ReturnType
+	}
+
+	return lowerer.builder.BuildCall(functionName, arguments, returnType)
+}
+
+// lowerArguments lowers each of argExprs in order, returning ok=false
+// (discarding any partial result) if one fails to lower.
+func (lowerer *Lowerer) lowerArguments(argExprs []ast.Expression) ([]value.Value, bool) {
 	var arguments []value.Value
-	for _, arg := range call.Arguments {
-		argValue := lowerer.lowerExpression(arg)
+	for _, argExpr := range argExprs {
+		argValue := lowerer.lowerExpression(argExpr)
 		if argValue == nil {
-			return nil
+			return nil, false
 		}
 		arguments = append(arguments, argValue)
 	}
-
-	// For now, assume all functions are void
-	// TODO: Look up function signature from symbol table
-	return lowerer.builder.BuildCall(functionName, arguments, nil)
+	return arguments, true
 }
 
 // getType converts AST type annotation to NIR type
@@ -317,7 +1072,15 @@ func (lowerer *Lowerer) getType(typeAnnotation *ast.TypeAnnotation) types.Type {
 		return types.Bool
 	case "nil":
 		return types.Nil
+	case "i8", "i16", "i32", "i64", "u8", "u16", "u32", "u64", "f32", "f64":
+		return types.GetPrimitiveType(typeAnnotation.Value)
 	default:
+		if classType, ok := lowerer.classes[typeAnnotation.Value]; ok {
+			return classType
+		}
+		if structType, ok := lowerer.structs[typeAnnotation.Value]; ok {
+			return structType
+		}
 		return types.Int // Default fallback
 	}
 }
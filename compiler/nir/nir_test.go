@@ -0,0 +1,114 @@
+package nir
+
+import (
+	"compiler/ast"
+	"compiler/errors"
+	"compiler/nir/instruction"
+	"compiler/token"
+	"testing"
+)
+
+func intLiteral(value string) *ast.IntegerLiteral {
+	return &ast.IntegerLiteral{Token: token.Token{Type: token.INT_LITERAL, Value: value}, Value: value}
+}
+
+func ident(name string) *ast.Identifier {
+	return &ast.Identifier{Token: token.Token{Type: token.IDENTIFIER, Value: name}, Value: name}
+}
+
+func lowerMain(t *testing.T, body *ast.BlockStatement) *Function {
+	t.Helper()
+
+	program := &ast.Program{
+		Statements: []ast.Statement{
+			&ast.FunctionStatement{
+				Token:      token.Token{Type: token.FUNC, Value: "func"},
+				Name:       ident("main"),
+				Parameters: []*ast.FunctionParameter{},
+				Body:       body,
+			},
+		},
+	}
+
+	lowerer := NewLowerer(errors.New("", "test.navi"))
+	module := lowerer.Lower(program)
+
+	if len(module.Functions) != 1 {
+		t.Fatalf("expected 1 function, got %d", len(module.Functions))
+	}
+	return module.Functions[0]
+}
+
+// TestLowerIfElseBuildsThenElseMergeBlocks checks that lowerIfStatement
+// splits the function into then/else/merge blocks joined by (Cond)Branch
+// terminators, per lowerIfStatement's doc comment.
+func TestLowerIfElseBuildsThenElseMergeBlocks(t *testing.T) {
+	body := &ast.BlockStatement{
+		Statements: []ast.Statement{
+			&ast.IfStatement{
+				Condition:   intLiteral("1"),
+				Consequence: &ast.BlockStatement{Statements: []ast.Statement{}},
+				Alternative: &ast.BlockStatement{Statements: []ast.Statement{}},
+			},
+		},
+	}
+
+	function := lowerMain(t, body)
+	t.Logf("CFG dump:\n%s", function.String())
+
+	names := make([]string, len(function.BasicBlocks))
+	for i, block := range function.BasicBlocks {
+		names[i] = block.Name
+	}
+	want := []string{"entry", "then0", "else0", "merge0"}
+	if len(names) != len(want) {
+		t.Fatalf("block names = %v, want %v", names, want)
+	}
+	for i, name := range want {
+		if names[i] != name {
+			t.Errorf("block[%d] = %s, want %s", i, names[i], name)
+		}
+	}
+
+	entry := function.BasicBlocks[0]
+	if _, ok := entry.Terminator.(*instruction.CondBranchInstruction); !ok {
+		t.Errorf("entry block should terminate with CondBranch, got %T", entry.Terminator)
+	}
+}
+
+// TestLowerWhileBuildsCondBodyMergeBlocks checks that lowerWhileStatement
+// splits the function into cond/body/merge blocks, with the body branching
+// back to cond rather than falling through to merge.
+func TestLowerWhileBuildsCondBodyMergeBlocks(t *testing.T) {
+	body := &ast.BlockStatement{
+		Statements: []ast.Statement{
+			&ast.WhileStatement{
+				Condition: intLiteral("1"),
+				Body:      &ast.BlockStatement{Statements: []ast.Statement{}},
+			},
+		},
+	}
+
+	function := lowerMain(t, body)
+	t.Logf("CFG dump:\n%s", function.String())
+
+	names := make([]string, len(function.BasicBlocks))
+	for i, block := range function.BasicBlocks {
+		names[i] = block.Name
+	}
+	want := []string{"entry", "cond0", "body0", "merge0"}
+	if len(names) != len(want) {
+		t.Fatalf("block names = %v, want %v", names, want)
+	}
+	for i, name := range want {
+		if names[i] != name {
+			t.Errorf("block[%d] = %s, want %s", i, names[i], name)
+		}
+	}
+
+	bodyBlock := function.BasicBlocks[2]
+	condBlock := function.BasicBlocks[1]
+	if len(bodyBlock.Successors) != 1 || bodyBlock.Successors[0] != condBlock {
+		t.Errorf("body block should branch back to cond block, got successors %v", bodyBlock.Successors)
+	}
+}
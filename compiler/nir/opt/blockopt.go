@@ -0,0 +1,202 @@
+package opt
+
+import (
+	"compiler/nir"
+	"compiler/nir/instruction"
+)
+
+// RemoveUnreachableBlocks deletes every block other than the entry that
+// has no predecessors, along with any CFG edges and phi incoming entries
+// that pointed at it. It reports whether it removed anything.
+func RemoveUnreachableBlocks(function *nir.Function) bool {
+	if len(function.BasicBlocks) == 0 {
+		return false
+	}
+
+	entry := function.BasicBlocks[0]
+	changed := false
+
+	reachable := function.BasicBlocks[:0:0]
+	for _, block := range function.BasicBlocks {
+		if block != entry && len(block.Predecessors) == 0 {
+			unlinkBlock(block)
+			changed = true
+			continue
+		}
+		reachable = append(reachable, block)
+	}
+	function.BasicBlocks = reachable
+
+	return changed
+}
+
+// unlinkBlock removes block from every successor's Predecessors list and
+// the matching phi incoming entries, since block is about to be dropped.
+func unlinkBlock(block *nir.BasicBlock) {
+	for _, successor := range block.Successors {
+		successor.Predecessors = removeBlock(successor.Predecessors, block)
+		removePhiIncoming(successor, block.Name)
+	}
+}
+
+func removeBlock(blocks []*nir.BasicBlock, target *nir.BasicBlock) []*nir.BasicBlock {
+	filtered := blocks[:0:0]
+	for _, block := range blocks {
+		if block != target {
+			filtered = append(filtered, block)
+		}
+	}
+	return filtered
+}
+
+func removePhiIncoming(block *nir.BasicBlock, predecessorName string) {
+	for _, inst := range block.Instructions {
+		if phi, ok := inst.(*instruction.PhiInstruction); ok {
+			removeIncomingFrom(phi, predecessorName)
+		}
+	}
+}
+
+func removeIncomingFrom(phi *instruction.PhiInstruction, predecessorName string) {
+	kept := phi.GetIncoming()[:0:0]
+	for _, in := range phi.GetIncoming() {
+		if in.Predecessor != predecessorName {
+			kept = append(kept, in)
+		}
+	}
+	phi.SetIncoming(kept)
+}
+
+// renamePhiIncoming rewrites any phi in block whose incoming edge names
+// oldName to instead name newName, used when a predecessor is fused or
+// threaded away.
+func renamePhiIncoming(block *nir.BasicBlock, oldName, newName string) {
+	for _, inst := range block.Instructions {
+		if phi, ok := inst.(*instruction.PhiInstruction); ok {
+			for i, in := range phi.GetIncoming() {
+				if in.Predecessor == oldName {
+					phi.GetIncoming()[i].Predecessor = newName
+				}
+			}
+		}
+	}
+}
+
+// FuseBlocks merges a block into its unique predecessor whenever that
+// predecessor's only successor is this block and this block has no other
+// predecessor - the pair can never be entered independently, so there's
+// no reason to keep them as separate blocks. It reports whether it fused
+// anything.
+func FuseBlocks(function *nir.Function) bool {
+	changed := false
+
+	fusedSomething := true
+	for fusedSomething {
+		fusedSomething = false
+
+		for i, block := range function.BasicBlocks {
+			if i == 0 {
+				continue // never fuse the entry block away
+			}
+			if len(block.Predecessors) != 1 {
+				continue
+			}
+
+			predecessor := block.Predecessors[0]
+			if len(predecessor.Successors) != 1 || predecessor.Successors[0] != block {
+				continue
+			}
+
+			predecessor.Instructions = append(predecessor.Instructions, block.Instructions...)
+			predecessor.Terminator = block.Terminator
+			predecessor.Successors = block.Successors
+
+			for _, successor := range block.Successors {
+				successor.Predecessors = replaceBlock(successor.Predecessors, block, predecessor)
+				renamePhiIncoming(successor, block.Name, predecessor.Name)
+			}
+
+			function.BasicBlocks = removeBlock(function.BasicBlocks, block)
+			changed = true
+			fusedSomething = true
+			break
+		}
+	}
+
+	return changed
+}
+
+func replaceBlock(blocks []*nir.BasicBlock, old, new *nir.BasicBlock) []*nir.BasicBlock {
+	replaced := make([]*nir.BasicBlock, len(blocks))
+	for i, block := range blocks {
+		if block == old {
+			replaced[i] = new
+		} else {
+			replaced[i] = block
+		}
+	}
+	return replaced
+}
+
+// ThreadJumps removes a block whose body is nothing but an unconditional
+// jump - no instructions, a single successor - by redirecting every
+// predecessor straight to that successor. It reports whether anything was
+// threaded away.
+func ThreadJumps(function *nir.Function) bool {
+	changed := false
+
+	threadedSomething := true
+	for threadedSomething {
+		threadedSomething = false
+
+		for i, block := range function.BasicBlocks {
+			if i == 0 {
+				continue // the entry block is always reachable from outside the CFG
+			}
+			if len(block.Instructions) != 0 || len(block.Successors) != 1 {
+				continue
+			}
+
+			target := block.Successors[0]
+			if target == block {
+				continue // don't thread a block into itself
+			}
+
+			for _, predecessor := range block.Predecessors {
+				predecessor.Successors = replaceBlock(predecessor.Successors, block, target)
+				switch terminator := predecessor.Terminator.(type) {
+				case *instruction.BranchInstruction:
+					predecessor.Terminator = instruction.NewBranchInstruction(target.Name)
+				case *instruction.CondBranchInstruction:
+					trueTarget, falseTarget := terminator.GetTrueTarget(), terminator.GetFalseTarget()
+					if trueTarget == block.Name {
+						trueTarget = target.Name
+					}
+					if falseTarget == block.Name {
+						falseTarget = target.Name
+					}
+					predecessor.Terminator = instruction.NewCondBranchInstruction(terminator.GetCondition(), trueTarget, falseTarget)
+				}
+				target.Predecessors = appendIfAbsentBlock(target.Predecessors, predecessor)
+				renamePhiIncoming(target, block.Name, predecessor.Name)
+			}
+
+			target.Predecessors = removeBlock(target.Predecessors, block)
+			function.BasicBlocks = removeBlock(function.BasicBlocks, block)
+			changed = true
+			threadedSomething = true
+			break
+		}
+	}
+
+	return changed
+}
+
+func appendIfAbsentBlock(blocks []*nir.BasicBlock, block *nir.BasicBlock) []*nir.BasicBlock {
+	for _, existing := range blocks {
+		if existing == block {
+			return blocks
+		}
+	}
+	return append(blocks, block)
+}
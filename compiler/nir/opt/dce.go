@@ -0,0 +1,27 @@
+package opt
+
+import "compiler/nir"
+
+// DeadCodeElimination removes instructions whose result is never read and
+// which have no side effects. It reports whether it removed anything, so
+// callers can iterate it against ConstantFolding to a fixed point.
+func DeadCodeElimination(function *nir.Function) bool {
+	changed := false
+
+	for _, block := range function.BasicBlocks {
+		du := buildDefUse(function)
+
+		filtered := block.Instructions[:0:0]
+		for _, inst := range block.Instructions {
+			result := inst.GetResult()
+			if result != nil && !hasSideEffects(inst) && du.useCount[result.ID()] == 0 {
+				changed = true
+				continue
+			}
+			filtered = append(filtered, inst)
+		}
+		block.Instructions = filtered
+	}
+
+	return changed
+}
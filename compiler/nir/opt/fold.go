@@ -0,0 +1,211 @@
+package opt
+
+import (
+	"compiler/constant"
+	"compiler/nir"
+	"compiler/nir/instruction"
+	"compiler/nir/value"
+	"compiler/types"
+)
+
+// ConstantFolding rewrites BinaryInstructions whose operands are both
+// constants into a single Constant, and BinaryInstructions that match an
+// algebraic identity (x+0, x*1, x*0, x-x, ...) into whichever operand (or
+// zero constant) the identity reduces to, replacing every use of the old
+// temporary with the result. It reports whether it changed anything.
+func ConstantFolding(function *nir.Function) bool {
+	changed := false
+
+	for _, block := range function.BasicBlocks {
+		for _, inst := range block.Instructions {
+			binary, ok := inst.(*instruction.BinaryInstruction)
+			if !ok {
+				continue
+			}
+
+			left, right := binary.GetLeft(), binary.GetRight()
+
+			if leftConst, ok := left.(*value.Constant); ok {
+				if rightConst, ok := right.(*value.Constant); ok {
+					if folded, ok := foldConstants(leftConst, rightConst, binary.GetOperator()); ok {
+						// Rewriting every use to point at folded leaves
+						// this BinaryInstruction's result unused, so
+						// DeadCodeElimination will remove it on the next
+						// iteration of the fixed point.
+						replaceUses(function, binary.GetResult().ID(), folded)
+						changed = true
+						continue
+					}
+				}
+			}
+
+			if simplified, ok := algebraicIdentity(left, right, binary.GetOperator()); ok {
+				replaceUses(function, binary.GetResult().ID(), simplified)
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
+
+// algebraicIdentity recognizes a BinaryInstruction that reduces to one of
+// its operands (or zero) regardless of what the other operand is, unlike
+// foldConstants which needs both sides to already be constants: x+0, 0+x,
+// x*1, 1*x, x*0, 0*x, and x-x (same value ID, so provably equal).
+//
+// Short-circuiting && and || isn't handled here: lowerBinaryExpression
+// already branches into separate basic blocks for those rather than
+// emitting a BinaryInstruction for the RHS (see nir.go), so there's no
+// eagerly-evaluated instruction here to skip in the first place.
+func algebraicIdentity(left, right value.Value, operator instruction.BinaryOperator) (value.Value, bool) {
+	leftConst, leftIsConst := left.(*value.Constant)
+	rightConst, rightIsConst := right.(*value.Constant)
+
+	switch operator {
+	case instruction.BinaryAdd:
+		if rightIsConst && isIntConstant(rightConst, 0) {
+			return left, true
+		}
+		if leftIsConst && isIntConstant(leftConst, 0) {
+			return right, true
+		}
+	case instruction.BinarySubtract:
+		if rightIsConst && isIntConstant(rightConst, 0) {
+			return left, true
+		}
+		// x-x -> 0 only holds for integers: for a float it's unsound
+		// (NaN-NaN and Inf-Inf must not fold to 0), and the result
+		// below is an Int-kind constant.Value regardless, which would
+		// reach ConstantConverter.convertIntConstant for a float-typed
+		// left.Type() and emit llvm.ConstInt against a double/float
+		// LLVM type.
+		if left.ID() == right.ID() && !types.IsFloat(left.Type()) {
+			return value.NewConstant(constant.MakeInt64(0), left.Type()), true
+		}
+	case instruction.BinaryMultiply:
+		// Like x-x above, 0*x -> 0 only holds for integers: 0*NaN and
+		// 0*Inf are both NaN, not 0. The x*1/1*x branches below don't
+		// need the guard since they return the untouched operand
+		// rather than synthesizing a zero constant.
+		if leftIsConst && isIntConstant(leftConst, 0) && !types.IsFloat(right.Type()) {
+			return left, true
+		}
+		if rightIsConst && isIntConstant(rightConst, 0) && !types.IsFloat(left.Type()) {
+			return right, true
+		}
+		if rightIsConst && isIntConstant(rightConst, 1) {
+			return left, true
+		}
+		if leftIsConst && isIntConstant(leftConst, 1) {
+			return right, true
+		}
+	}
+
+	return nil, false
+}
+
+// isIntConstant reports whether constantValue is the int constant want.
+func isIntConstant(constantValue *value.Constant, want int64) bool {
+	intValue, ok := constant.Int64Val(constantValue.Value())
+	return ok && intValue == want
+}
+
+// foldConstants computes the result of applying operator to two int
+// constants at compile time. Only int is supported for now, matching the
+// rest of the NIR lowering pipeline (see nir.Lowerer.lowerIntegerLiteral).
+func foldConstants(left, right *value.Constant, operator instruction.BinaryOperator) (*value.Constant, bool) {
+	leftInt, leftOK := constant.Int64Val(left.Value())
+	rightInt, rightOK := constant.Int64Val(right.Value())
+	if !leftOK || !rightOK {
+		return nil, false
+	}
+
+	switch operator {
+	case instruction.BinaryAdd:
+		return value.NewConstant(constant.MakeInt64(leftInt+rightInt), left.Type()), true
+	case instruction.BinarySubtract:
+		return value.NewConstant(constant.MakeInt64(leftInt-rightInt), left.Type()), true
+	case instruction.BinaryMultiply:
+		return value.NewConstant(constant.MakeInt64(leftInt*rightInt), left.Type()), true
+	case instruction.BinaryDivide:
+		if rightInt == 0 {
+			return nil, false
+		}
+		return value.NewConstant(constant.MakeInt64(leftInt/rightInt), left.Type()), true
+	case instruction.BinaryModulo:
+		if rightInt == 0 {
+			return nil, false
+		}
+		return value.NewConstant(constant.MakeInt64(leftInt%rightInt), left.Type()), true
+	default:
+		return nil, false
+	}
+}
+
+// replaceUses rewrites every operand across function whose value ID
+// matches oldID to instead reference replacement.
+func replaceUses(function *nir.Function, oldID int, replacement value.Value) {
+	for _, block := range function.BasicBlocks {
+		for i, inst := range block.Instructions {
+			block.Instructions[i] = substitute(inst, oldID, replacement)
+		}
+		if block.Terminator != nil {
+			block.Terminator = substitute(block.Terminator, oldID, replacement)
+		}
+	}
+}
+
+// substitute returns inst with any operand matching oldID swapped for
+// replacement, rebuilding the instruction since operands are unexported.
+func substitute(inst instruction.Instruction, oldID int, replacement value.Value) instruction.Instruction {
+	pick := func(v value.Value) value.Value {
+		if v != nil && v.ID() == oldID {
+			return replacement
+		}
+		return v
+	}
+
+	switch typed := inst.(type) {
+	case *instruction.BinaryInstruction:
+		return instruction.NewBinaryInstruction(typed.GetResult(), typed.GetOperator(), pick(typed.GetLeft()), pick(typed.GetRight()))
+	case *instruction.StoreInstruction:
+		return instruction.NewStoreInstruction(pick(typed.GetDestination()), pick(typed.GetValue()))
+	case *instruction.LoadInstruction:
+		return instruction.NewLoadInstruction(typed.GetResult(), pick(typed.GetSource()))
+	case *instruction.ExtractInstruction:
+		return instruction.NewExtractInstruction(typed.GetResult(), pick(typed.GetSource()), typed.GetIndex())
+	case *instruction.PhiInstruction:
+		incoming := make([]instruction.PhiIncoming, len(typed.GetIncoming()))
+		for i, in := range typed.GetIncoming() {
+			incoming[i] = instruction.PhiIncoming{Predecessor: in.Predecessor, Value: pick(in.Value)}
+		}
+		return instruction.NewPhiInstruction(typed.GetResult(), incoming)
+	case *instruction.CallInstruction:
+		arguments := make([]value.Value, len(typed.GetArguments()))
+		for i, arg := range typed.GetArguments() {
+			arguments[i] = pick(arg)
+		}
+		if receiver := typed.GetReceiver(); receiver != nil {
+			return instruction.NewMethodCallInstruction(typed.GetResult(), typed.GetFunctionName(), pick(receiver), arguments)
+		}
+		return instruction.NewCallInstruction(typed.GetResult(), typed.GetFunctionName(), arguments)
+	case *instruction.ReturnInstruction:
+		if values := typed.GetValues(); len(values) > 0 {
+			replaced := make([]value.Value, len(values))
+			for i, v := range values {
+				replaced[i] = pick(v)
+			}
+			return instruction.NewReturnMultiInstruction(replaced)
+		}
+		return instruction.NewReturnInstruction(pick(typed.GetValue()))
+	case *instruction.ConvertInstruction:
+		return instruction.NewConvertInstruction(typed.GetResult(), typed.GetKind(), pick(typed.GetSource()))
+	case *instruction.GEPInstruction:
+		return instruction.NewGEPInstruction(typed.GetResult(), pick(typed.GetObject()), typed.GetIndex())
+	case *instruction.CondBranchInstruction:
+		return instruction.NewCondBranchInstruction(pick(typed.GetCondition()), typed.GetTrueTarget(), typed.GetFalseTarget())
+	default:
+		return inst
+	}
+}
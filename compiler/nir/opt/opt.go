@@ -0,0 +1,145 @@
+// Package opt implements optimization passes that run on NIR between
+// lowering and the codegen/LLVM backends.
+package opt
+
+import (
+	"compiler/nir"
+	"compiler/nir/instruction"
+)
+
+// defUse tracks, per function, which instruction produced a value (by
+// value ID) and how many times that value is read by other instructions'
+// operands. It's rebuilt at the start of every DeadCodeElimination pass
+// since the instruction list changes underneath it on each iteration.
+type defUse struct {
+	definedBy map[int]instructionAt
+	useCount  map[int]int
+}
+
+type instructionAt struct {
+	block *nir.BasicBlock
+	index int
+}
+
+func buildDefUse(function *nir.Function) *defUse {
+	du := &defUse{
+		definedBy: make(map[int]instructionAt),
+		useCount:  make(map[int]int),
+	}
+
+	for _, block := range function.BasicBlocks {
+		for i, inst := range block.Instructions {
+			if result := inst.GetResult(); result != nil {
+				du.definedBy[result.ID()] = instructionAt{block: block, index: i}
+			}
+			for _, operand := range operandsOf(inst) {
+				du.useCount[operand.ID()]++
+			}
+		}
+		if block.Terminator != nil {
+			for _, operand := range operandsOf(block.Terminator) {
+				du.useCount[operand.ID()]++
+			}
+		}
+	}
+
+	return du
+}
+
+// operandsOf returns the values read by inst, independent of instruction
+// kind. Instructions that don't read a Value (e.g. alloc) return nil.
+func operandsOf(inst instruction.Instruction) []valueWithID {
+	switch typed := inst.(type) {
+	case *instruction.BinaryInstruction:
+		return []valueWithID{typed.GetLeft(), typed.GetRight()}
+	case *instruction.StoreInstruction:
+		return []valueWithID{typed.GetDestination(), typed.GetValue()}
+	case *instruction.LoadInstruction:
+		return []valueWithID{typed.GetSource()}
+	case *instruction.ExtractInstruction:
+		return []valueWithID{typed.GetSource()}
+	case *instruction.ConvertInstruction:
+		return []valueWithID{typed.GetSource()}
+	case *instruction.GEPInstruction:
+		return []valueWithID{typed.GetObject()}
+	case *instruction.CondBranchInstruction:
+		return []valueWithID{typed.GetCondition()}
+	case *instruction.PhiInstruction:
+		incoming := typed.GetIncoming()
+		operands := make([]valueWithID, 0, len(incoming))
+		for _, in := range incoming {
+			if in.Value != nil {
+				operands = append(operands, in.Value)
+			}
+		}
+		return operands
+	case *instruction.CallInstruction:
+		values := make([]valueWithID, 0, len(typed.GetArguments()))
+		for _, arg := range typed.GetArguments() {
+			values = append(values, arg)
+		}
+		if receiver := typed.GetReceiver(); receiver != nil {
+			values = append(values, receiver)
+		}
+		return values
+	case *instruction.ReturnInstruction:
+		if values := typed.GetValues(); len(values) > 0 {
+			operands := make([]valueWithID, len(values))
+			for i, v := range values {
+				operands[i] = v
+			}
+			return operands
+		}
+		if typed.GetValue() != nil {
+			return []valueWithID{typed.GetValue()}
+		}
+	}
+	return nil
+}
+
+// valueWithID is the subset of nir/value.Value that operandsOf needs; it
+// exists only so this file doesn't have to import nir/value for the name.
+type valueWithID interface {
+	ID() int
+}
+
+// hasSideEffects reports whether removing inst (because its result is
+// unused) would change observable program behavior.
+func hasSideEffects(inst instruction.Instruction) bool {
+	switch inst.(type) {
+	case *instruction.CallInstruction, *instruction.StoreInstruction, *instruction.ReturnInstruction:
+		return true
+	default:
+		return false
+	}
+}
+
+// Run applies every optimization pass to each function in module, up to
+// level. level 0 (-O0) runs nothing, so the caller gets unoptimized NIR
+// straight out of lowering/SSA; level 1 (-O1, the default) runs constant
+// folding, dead code elimination, and the block optimizer together to a
+// fixed point.
+func Run(module *nir.Module, level int) {
+	if level < 1 {
+		return
+	}
+
+	for _, function := range module.Functions {
+		RunFunction(function)
+	}
+}
+
+// RunFunction applies the level-1 pass pipeline to a single function.
+func RunFunction(function *nir.Function) {
+	for {
+		changed := false
+		changed = ConstantFolding(function) || changed
+		changed = DeadCodeElimination(function) || changed
+		changed = RemoveUnreachableBlocks(function) || changed
+		changed = FuseBlocks(function) || changed
+		changed = ThreadJumps(function) || changed
+		if !changed {
+			break
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package nir
+
+// SourceLoc records where in the original source an instruction came from,
+// carried through from lexer.Token/token.Token so later phases (debug dumps,
+// DWARF emission) can point back at Naviary source lines.
+type SourceLoc struct {
+	Line   int
+	Column int
+}
+
+// HasLoc reports whether loc was ever set (the zero value means "unknown").
+func (loc SourceLoc) HasLoc() bool {
+	return loc.Line > 0
+}
@@ -0,0 +1,186 @@
+// Package ssa lifts a NIR function's Alloc/Store/Load stack variables into
+// pure SSA values: it builds a dominator tree (Lengauer-Tarjan), derives
+// dominance frontiers (Cytron et al.), places PhiInstructions at the
+// frontiers of each variable's assignments, and renames uses to the
+// reaching definition via a per-variable stack walked in dominator-tree
+// pre-order. This mirrors the structure of Go's own SSA builder
+// (cmd/compile/internal/ssa/lift.go).
+package ssa
+
+import "compiler/nir"
+
+// DominatorTree holds, for every block reachable from the function's
+// entry, its immediate dominator and the dominator tree's parent/child
+// edges derived from it.
+type DominatorTree struct {
+	entry    *nir.BasicBlock
+	idom     map[*nir.BasicBlock]*nir.BasicBlock
+	children map[*nir.BasicBlock][]*nir.BasicBlock
+	order    []*nir.BasicBlock // DFS preorder over the CFG, root first
+}
+
+// Idom returns block's immediate dominator, or nil for the entry block
+// (which dominates itself and has no immediate dominator) and for
+// unreachable blocks.
+func (tree *DominatorTree) Idom(block *nir.BasicBlock) *nir.BasicBlock {
+	return tree.idom[block]
+}
+
+// Children returns block's children in the dominator tree.
+func (tree *DominatorTree) Children(block *nir.BasicBlock) []*nir.BasicBlock {
+	return tree.children[block]
+}
+
+// Preorder returns every block reachable from the entry, in CFG
+// depth-first preorder (the same order dfnum numbers them in).
+func (tree *DominatorTree) Preorder() []*nir.BasicBlock {
+	return tree.order
+}
+
+// BuildDominatorTree computes entry's dominator tree using the
+// Lengauer-Tarjan algorithm: a DFS numbers every reachable block, semi-
+// dominators are computed processing blocks in decreasing DFS order
+// (looking up each predecessor's lowest-semi ancestor via EVAL, a
+// union-find walk with path compression), and a final forward pass
+// resolves each block's true immediate dominator from its semi-dominator.
+func BuildDominatorTree(entry *nir.BasicBlock) *DominatorTree {
+	builder := &ltBuilder{
+		dfnum:    make(map[*nir.BasicBlock]int),
+		vertex:   make([]*nir.BasicBlock, 0),
+		parent:   make(map[*nir.BasicBlock]*nir.BasicBlock),
+		semi:     make(map[*nir.BasicBlock]*nir.BasicBlock),
+		ancestor: make(map[*nir.BasicBlock]*nir.BasicBlock),
+		best:     make(map[*nir.BasicBlock]*nir.BasicBlock),
+		bucket:   make(map[*nir.BasicBlock][]*nir.BasicBlock),
+		idom:     make(map[*nir.BasicBlock]*nir.BasicBlock),
+		samedom:  make(map[*nir.BasicBlock]*nir.BasicBlock),
+	}
+
+	builder.dfs(nil, entry)
+	builder.computeSemiAndIdom()
+
+	children := make(map[*nir.BasicBlock][]*nir.BasicBlock)
+	for _, block := range builder.vertex {
+		if parent := builder.idom[block]; parent != nil {
+			children[parent] = append(children[parent], block)
+		}
+	}
+
+	return &DominatorTree{
+		entry:    entry,
+		idom:     builder.idom,
+		children: children,
+		order:    builder.vertex,
+	}
+}
+
+// ltBuilder holds the working state of a single Lengauer-Tarjan run; it's
+// discarded once BuildDominatorTree returns.
+type ltBuilder struct {
+	dfnum  map[*nir.BasicBlock]int
+	vertex []*nir.BasicBlock // vertex[i] = the block numbered i by dfs
+	parent map[*nir.BasicBlock]*nir.BasicBlock
+
+	semi    map[*nir.BasicBlock]*nir.BasicBlock
+	bucket  map[*nir.BasicBlock][]*nir.BasicBlock
+	idom    map[*nir.BasicBlock]*nir.BasicBlock
+	samedom map[*nir.BasicBlock]*nir.BasicBlock
+
+	// ancestor/best implement the union-find forest EVAL/LINK operate
+	// over: ancestor[n] is n's parent in the forest (nil at a root),
+	// best[n] is the block with the lowest-dfnum semidominator found so
+	// far on the path from n to its forest root.
+	ancestor map[*nir.BasicBlock]*nir.BasicBlock
+	best     map[*nir.BasicBlock]*nir.BasicBlock
+}
+
+func (builder *ltBuilder) dfs(parent, block *nir.BasicBlock) {
+	if _, seen := builder.dfnum[block]; seen {
+		return
+	}
+
+	builder.dfnum[block] = len(builder.vertex)
+	builder.vertex = append(builder.vertex, block)
+	builder.parent[block] = parent
+	builder.best[block] = block
+
+	for _, successor := range block.Successors {
+		builder.dfs(block, successor)
+	}
+}
+
+func (builder *ltBuilder) computeSemiAndIdom() {
+	for i := len(builder.vertex) - 1; i >= 1; i-- {
+		block := builder.vertex[i]
+		parent := builder.parent[block]
+
+		semi := parent
+		for _, pred := range block.Predecessors {
+			if _, reachable := builder.dfnum[pred]; !reachable {
+				continue
+			}
+
+			var candidate *nir.BasicBlock
+			if builder.dfnum[pred] <= builder.dfnum[block] {
+				candidate = pred
+			} else {
+				candidate = builder.semi[builder.eval(pred)]
+			}
+
+			if builder.dfnum[candidate] < builder.dfnum[semi] {
+				semi = candidate
+			}
+		}
+		builder.semi[block] = semi
+		builder.bucket[semi] = append(builder.bucket[semi], block)
+		builder.link(parent, block)
+
+		for _, v := range builder.bucket[parent] {
+			y := builder.eval(v)
+			if builder.dfnum[builder.semi[y]] < builder.dfnum[builder.semi[v]] {
+				builder.samedom[v] = y
+			} else {
+				builder.idom[v] = parent
+			}
+		}
+		builder.bucket[parent] = nil
+	}
+
+	for i := 1; i < len(builder.vertex); i++ {
+		block := builder.vertex[i]
+		if same, ok := builder.samedom[block]; ok {
+			builder.idom[block] = builder.idom[same]
+		}
+	}
+}
+
+// link adds child to the union-find forest as parent's descendant.
+func (builder *ltBuilder) link(parent, child *nir.BasicBlock) {
+	builder.ancestor[child] = parent
+}
+
+// eval returns the block with the lowest-dfnum semidominator on the path
+// from block up to its forest root, compressing the path as it goes so
+// later evals along the same path are O(1) amortized.
+func (builder *ltBuilder) eval(block *nir.BasicBlock) *nir.BasicBlock {
+	if builder.ancestor[block] == nil {
+		return builder.best[block]
+	}
+
+	builder.compress(block)
+	return builder.best[block]
+}
+
+func (builder *ltBuilder) compress(block *nir.BasicBlock) {
+	ancestor := builder.ancestor[block]
+	if builder.ancestor[ancestor] == nil {
+		return
+	}
+
+	builder.compress(ancestor)
+
+	if builder.dfnum[builder.semi[builder.best[ancestor]]] < builder.dfnum[builder.semi[builder.best[block]]] {
+		builder.best[block] = builder.best[ancestor]
+	}
+	builder.ancestor[block] = builder.ancestor[ancestor]
+}
@@ -0,0 +1,38 @@
+package ssa
+
+import "compiler/nir"
+
+// DominanceFrontiers computes, for every block in tree, the set of blocks
+// it dominates the predecessor of but does not strictly dominate itself
+// (Cytron et al.'s DF set): for each join block b (>= 2 predecessors), walk
+// up the dominator tree from each predecessor p until reaching idom(b),
+// adding b to DF(p) at every step along the way.
+func DominanceFrontiers(tree *DominatorTree) map[*nir.BasicBlock][]*nir.BasicBlock {
+	frontiers := make(map[*nir.BasicBlock][]*nir.BasicBlock)
+
+	for _, block := range tree.order {
+		if len(block.Predecessors) < 2 {
+			continue
+		}
+
+		idom := tree.Idom(block)
+		for _, pred := range block.Predecessors {
+			runner := pred
+			for runner != nil && runner != idom {
+				frontiers[runner] = appendIfAbsent(frontiers[runner], block)
+				runner = tree.Idom(runner)
+			}
+		}
+	}
+
+	return frontiers
+}
+
+func appendIfAbsent(blocks []*nir.BasicBlock, block *nir.BasicBlock) []*nir.BasicBlock {
+	for _, existing := range blocks {
+		if existing == block {
+			return blocks
+		}
+	}
+	return append(blocks, block)
+}
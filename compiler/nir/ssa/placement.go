@@ -0,0 +1,120 @@
+package ssa
+
+import (
+	"compiler/nir"
+	"compiler/nir/instruction"
+	"compiler/nir/value"
+	"compiler/types"
+)
+
+// promotableVariable returns the stack variable val's name and type if
+// it's a candidate for SSA promotion, and ok=false otherwise. Every
+// Alloc'd *value.Variable is promotable save for one case: Naviary has no
+// address-of operator, so a scalar stack slot's only readers/writers are
+// the Load/Store pairs the lowerer itself emitted for it - but a class
+// instance's fields are addressed directly via GEPInstruction (see
+// nir.Lowerer.lowerFieldAddress), which needs the Alloc's actual memory
+// to still exist, so aggregate-typed variables are excluded.
+func promotableVariable(val value.Value) (name string, variable *value.Variable, ok bool) {
+	variable, isVariable := val.(*value.Variable)
+	if !isVariable {
+		return "", nil, false
+	}
+	if types.IsAggregate(variable.Type()) {
+		return "", nil, false
+	}
+	return variable.String(), variable, true
+}
+
+// assignmentSites returns, for each promotable variable name, the set of
+// blocks containing a Store to it.
+func assignmentSites(function *nir.Function) map[string]map[*nir.BasicBlock]bool {
+	sites := make(map[string]map[*nir.BasicBlock]bool)
+
+	for _, block := range function.Blocks() {
+		for _, inst := range block.Instructions {
+			store, ok := inst.(*instruction.StoreInstruction)
+			if !ok {
+				continue
+			}
+
+			name, _, ok := promotableVariable(store.GetDestination())
+			if !ok {
+				continue
+			}
+
+			if sites[name] == nil {
+				sites[name] = make(map[*nir.BasicBlock]bool)
+			}
+			sites[name][block] = true
+		}
+	}
+
+	return sites
+}
+
+// PlacePhis runs Cytron et al.'s iterative worklist algorithm: for every
+// variable v assigned in block set A, it places an (as-yet-empty)
+// PhiInstruction at every block in the union of dominance frontiers of A,
+// iterating until no new block is added. The phis are only recorded in
+// the returned map; RenameVariables inserts each at the head of its
+// block's instruction list and fills in its operands.
+func PlacePhis(function *nir.Function, frontiers map[*nir.BasicBlock][]*nir.BasicBlock) map[*nir.BasicBlock]map[string]*instruction.PhiInstruction {
+	placed := make(map[*nir.BasicBlock]map[string]*instruction.PhiInstruction)
+
+	for variableName, defBlocks := range assignmentSites(function) {
+		var variable *value.Variable
+		for _, block := range function.Blocks() {
+			for _, inst := range block.Instructions {
+				if store, ok := inst.(*instruction.StoreInstruction); ok {
+					if name, v, ok := promotableVariable(store.GetDestination()); ok && name == variableName {
+						variable = v
+					}
+				}
+			}
+		}
+
+		hasPhi := make(map[*nir.BasicBlock]bool)
+		onWorklist := make(map[*nir.BasicBlock]bool)
+		var worklist []*nir.BasicBlock
+
+		for block := range defBlocks {
+			onWorklist[block] = true
+			worklist = append(worklist, block)
+		}
+
+		for len(worklist) > 0 {
+			block := worklist[len(worklist)-1]
+			worklist = worklist[:len(worklist)-1]
+
+			for _, frontierBlock := range frontiers[block] {
+				if hasPhi[frontierBlock] {
+					continue
+				}
+				hasPhi[frontierBlock] = true
+
+				result := value.NewTemporary(nextPhiTemporaryID, variable.Type())
+				nextPhiTemporaryID++
+
+				if placed[frontierBlock] == nil {
+					placed[frontierBlock] = make(map[string]*instruction.PhiInstruction)
+				}
+				placed[frontierBlock][variableName] = instruction.NewPhiInstruction(result, nil)
+
+				if !onWorklist[frontierBlock] {
+					onWorklist[frontierBlock] = true
+					worklist = append(worklist, frontierBlock)
+				}
+			}
+		}
+	}
+
+	return placed
+}
+
+// nextPhiTemporaryID numbers phi results independently of the lowerer's
+// own per-function counter: SSA construction runs as a pass after
+// lowering (and after nir/opt), so it can't reuse Builder's counter.
+// Using a package-level counter keeps phi result IDs unique within a
+// single compilation the same way nir/value's constant/variable IDs do.
+var nextPhiTemporaryID = 1 << 20
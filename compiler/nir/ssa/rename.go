@@ -0,0 +1,107 @@
+package ssa
+
+import (
+	"compiler/nir"
+	"compiler/nir/instruction"
+	"compiler/nir/value"
+)
+
+// RenameVariables rewrites function's promoted Loads/Stores into pure SSA
+// values: it walks the dominator tree in pre-order, keeping a stack of
+// reaching definitions per variable name. Entering a block pushes that
+// block's placed phi results (if any); each Store pushes its stored
+// value instead of emitting a memory write; each Load is replaced
+// everywhere by the top of its variable's stack instead of reading
+// memory; leaving a block pops whatever it pushed, and every successor's
+// phis are given this block's current reaching definition as the
+// incoming value for this predecessor.
+func RenameVariables(function *nir.Function, tree *DominatorTree, phis map[*nir.BasicBlock]map[string]*instruction.PhiInstruction) {
+	stacks := make(map[string][]value.Value)
+
+	var renameBlock func(block *nir.BasicBlock)
+	renameBlock = func(block *nir.BasicBlock) {
+		pushedCount := make(map[string]int)
+		push := func(name string, val value.Value) {
+			stacks[name] = append(stacks[name], val)
+			pushedCount[name]++
+		}
+
+		// Phis placed at this block are new definitions, in program order
+		// ahead of every other instruction.
+		blockPhis := phis[block]
+		for name, phi := range blockPhis {
+			push(name, phi.GetResult())
+		}
+
+		var rebuilt []instruction.Instruction
+		for _, inst := range block.Instructions {
+			switch typed := inst.(type) {
+			case *instruction.AllocInstruction:
+				if _, _, ok := promotableVariable(typed.GetResult()); ok {
+					// Promoted: the variable no longer has a memory home,
+					// so its Alloc is dropped along with the Loads/Stores
+					// that referenced it.
+					continue
+				}
+				rebuilt = append(rebuilt, inst)
+
+			case *instruction.StoreInstruction:
+				if name, _, ok := promotableVariable(typed.GetDestination()); ok {
+					push(name, typed.GetValue())
+					continue
+				}
+				rebuilt = append(rebuilt, inst)
+
+			case *instruction.LoadInstruction:
+				if name, _, ok := promotableVariable(typed.GetSource()); ok {
+					reaching := top(stacks, name)
+					if reaching != nil {
+						replaceValue(function, typed.GetResult().ID(), reaching)
+					}
+					continue
+				}
+				rebuilt = append(rebuilt, inst)
+
+			default:
+				rebuilt = append(rebuilt, inst)
+			}
+		}
+		block.Instructions = rebuilt
+
+		// Insert this block's own phis (now with a result, operands
+		// filled in below) at the head of its instruction list.
+		if len(blockPhis) > 0 {
+			headed := make([]instruction.Instruction, 0, len(blockPhis)+len(block.Instructions))
+			for _, phi := range blockPhis {
+				headed = append(headed, phi)
+			}
+			block.Instructions = append(headed, block.Instructions...)
+		}
+
+		// Tell every successor's phis what value to use for this
+		// predecessor.
+		for _, successor := range block.Successors {
+			for name, phi := range phis[successor] {
+				phi.AddIncoming(block.Name, top(stacks, name))
+			}
+		}
+
+		for _, child := range tree.Children(block) {
+			renameBlock(child)
+		}
+
+		for name, count := range pushedCount {
+			stacks[name] = stacks[name][:len(stacks[name])-count]
+		}
+	}
+
+	renameBlock(tree.entry)
+}
+
+func top(stacks map[string][]value.Value, name string) value.Value {
+	stack := stacks[name]
+	if len(stack) == 0 {
+		return nil
+	}
+	return stack[len(stack)-1]
+}
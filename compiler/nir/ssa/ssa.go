@@ -0,0 +1,30 @@
+package ssa
+
+import "compiler/nir"
+
+// Construct lifts function's Alloc/Store/Load stack variables into pure
+// SSA form in place: it builds function's dominator tree, derives
+// dominance frontiers, places phis at those frontiers, and renames every
+// Load/Store pair to the reaching SSA definition. A function with a
+// single basic block has no dominance frontiers and so gains no phis;
+// Construct still runs
+// the renaming pass, which drops their now-redundant Allocs.
+func Construct(function *nir.Function) {
+	blocks := function.Blocks()
+	if len(blocks) == 0 {
+		return
+	}
+
+	entry := blocks[0]
+	tree := BuildDominatorTree(entry)
+	frontiers := DominanceFrontiers(tree)
+	phis := PlacePhis(function, frontiers)
+	RenameVariables(function, tree, phis)
+}
+
+// Run applies Construct to every function in module.
+func Run(module *nir.Module) {
+	for _, function := range module.Functions {
+		Construct(function)
+	}
+}
@@ -0,0 +1,82 @@
+package ssa
+
+import (
+	"compiler/nir"
+	"compiler/nir/instruction"
+	"compiler/nir/value"
+)
+
+// replaceValue rewrites every operand across function whose value ID
+// matches oldID to instead reference replacement. This mirrors
+// nir/opt/fold.go's replaceUses/substitute pair; it's duplicated here
+// rather than exported from nir/opt because RenameVariables runs during
+// construction, before a block's instruction list has settled into its
+// final form (Allocs/Stores/Loads are still being dropped as it goes).
+func replaceValue(function *nir.Function, oldID int, replacement value.Value) {
+	for _, block := range function.Blocks() {
+		for i, inst := range block.Instructions {
+			block.Instructions[i] = substitute(inst, oldID, replacement)
+		}
+		if block.Terminator != nil {
+			block.Terminator = substitute(block.Terminator, oldID, replacement)
+		}
+	}
+}
+
+// substitute returns inst with any operand matching oldID swapped for
+// replacement, rebuilding the instruction since operands are unexported.
+// Every instruction shape that can reference a promoted variable's Load
+// result needs a case here, including a CondBranch's condition and a
+// method Call's receiver - missing one would leave a dangling reference
+// to a value RenameVariables has already retired.
+func substitute(inst instruction.Instruction, oldID int, replacement value.Value) instruction.Instruction {
+	pick := func(v value.Value) value.Value {
+		if v != nil && v.ID() == oldID {
+			return replacement
+		}
+		return v
+	}
+
+	switch typed := inst.(type) {
+	case *instruction.BinaryInstruction:
+		return instruction.NewBinaryInstruction(typed.GetResult(), typed.GetOperator(), pick(typed.GetLeft()), pick(typed.GetRight()))
+	case *instruction.StoreInstruction:
+		return instruction.NewStoreInstruction(pick(typed.GetDestination()), pick(typed.GetValue()))
+	case *instruction.LoadInstruction:
+		return instruction.NewLoadInstruction(typed.GetResult(), pick(typed.GetSource()))
+	case *instruction.ExtractInstruction:
+		return instruction.NewExtractInstruction(typed.GetResult(), pick(typed.GetSource()), typed.GetIndex())
+	case *instruction.PhiInstruction:
+		incoming := make([]instruction.PhiIncoming, len(typed.GetIncoming()))
+		for i, in := range typed.GetIncoming() {
+			incoming[i] = instruction.PhiIncoming{Predecessor: in.Predecessor, Value: pick(in.Value)}
+		}
+		return instruction.NewPhiInstruction(typed.GetResult(), incoming)
+	case *instruction.CallInstruction:
+		arguments := make([]value.Value, len(typed.GetArguments()))
+		for i, arg := range typed.GetArguments() {
+			arguments[i] = pick(arg)
+		}
+		if receiver := typed.GetReceiver(); receiver != nil {
+			return instruction.NewMethodCallInstruction(typed.GetResult(), typed.GetFunctionName(), pick(receiver), arguments)
+		}
+		return instruction.NewCallInstruction(typed.GetResult(), typed.GetFunctionName(), arguments)
+	case *instruction.ReturnInstruction:
+		if values := typed.GetValues(); len(values) > 0 {
+			replaced := make([]value.Value, len(values))
+			for i, v := range values {
+				replaced[i] = pick(v)
+			}
+			return instruction.NewReturnMultiInstruction(replaced)
+		}
+		return instruction.NewReturnInstruction(pick(typed.GetValue()))
+	case *instruction.ConvertInstruction:
+		return instruction.NewConvertInstruction(typed.GetResult(), typed.GetKind(), pick(typed.GetSource()))
+	case *instruction.GEPInstruction:
+		return instruction.NewGEPInstruction(typed.GetResult(), pick(typed.GetObject()), typed.GetIndex())
+	case *instruction.CondBranchInstruction:
+		return instruction.NewCondBranchInstruction(pick(typed.GetCondition()), typed.GetTrueTarget(), typed.GetFalseTarget())
+	default:
+		return inst
+	}
+}
@@ -0,0 +1,46 @@
+package nir
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetTrace turns Lowerer's indented enter/exit call trace on or off,
+// mirroring parser.Mode's ModeTrace (see compiler/parser/tracing.go):
+// once a precedence or lowering bug survives to NIR, seeing exactly
+// which lowerXxx calls produced which blocks/instructions is often
+// faster than staring at the final CFG dump.
+func (lowerer *Lowerer) SetTrace(enabled bool) {
+	lowerer.traceEnabled = enabled
+}
+
+// traceGuard is what trace returns and untrace consumes, the same
+// defer untrace(trace(lowerer, "lowerXxx")) pattern the parser uses.
+type traceGuard struct {
+	lowerer    *Lowerer
+	production string
+}
+
+func trace(lowerer *Lowerer, production string) *traceGuard {
+	if !lowerer.traceEnabled {
+		return nil
+	}
+
+	lowerer.traceDepth++
+	fmt.Printf("%sBEGIN %s\n", traceIndent(lowerer.traceDepth), production)
+
+	return &traceGuard{lowerer: lowerer, production: production}
+}
+
+func untrace(guard *traceGuard) {
+	if guard == nil {
+		return
+	}
+
+	fmt.Printf("%sEND %s\n", traceIndent(guard.lowerer.traceDepth), guard.production)
+	guard.lowerer.traceDepth--
+}
+
+func traceIndent(depth int) string {
+	return strings.Repeat("  ", depth-1)
+}
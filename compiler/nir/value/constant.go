@@ -1,42 +1,50 @@
 package value
 
 import (
+	"compiler/constant"
 	"compiler/types"
 	"fmt"
 )
 
-// Constant represents a compile-time constant value
+// Constant represents a compile-time constant value. It stores the
+// constant's exact arbitrary-precision representation (see
+// compiler/constant), not a Go primitive or a display string, so a value
+// like a negative int64 or a multi-line string survives round-tripping
+// through NIR without ever being re-parsed from String().
 type Constant struct {
-	value     any
+	id        int
+	value     constant.Value
 	valueType types.Type
 }
 
-func NewConstant(value any, valueType types.Type) *Constant {
+func NewConstant(value constant.Value, valueType types.Type) *Constant {
 	return &Constant{
+		id:        newValueID(),
 		value:     value,
 		valueType: valueType,
 	}
 }
 
-func (constant *Constant) Type() types.Type {
-	return constant.valueType
+func (c *Constant) Type() types.Type {
+	return c.valueType
 }
 
-func (constant *Constant) IsConstant() bool {
+func (c *Constant) IsConstant() bool {
 	return true
 }
 
-func (constant *Constant) String() string {
-	switch v := constant.value.(type) {
-	case int:
-		return fmt.Sprintf("Constant(%d)", v)
-	case string:
-		return fmt.Sprintf("Constant(\"%s\")", v)
-	case float64:
-		return fmt.Sprintf("Constant(%f)", v)
-	case bool:
-		return fmt.Sprintf("Constant(%t)", v)
-	default:
-		return "Constant(?)"
-	}
+// ID implements Value.
+func (c *Constant) ID() int {
+	return c.id
+}
+
+// Value returns the exact constant.Value backing this Constant, for
+// passes like nir/opt's constant folder and the LLVM ConstantConverter
+// that need to compute with or emit it directly.
+func (c *Constant) Value() constant.Value {
+	return c.value
+}
+
+func (c *Constant) String() string {
+	return fmt.Sprintf("Constant(%s)", c.value.String())
 }
@@ -34,3 +34,11 @@ func (temporary *Temporary) IsConstant() bool {
 func (temporary *Temporary) GetID() int {
 	return temporary.id
 }
+
+// ID implements Value. It is derived from the Temporary's per-function
+// index but negative, so it never equals a Constant's or Variable's ID
+// (those come from newValueID and start at 0). Temporaries of different
+// functions can share an ID.
+func (temporary *Temporary) ID() int {
+	return -temporary.id - 1
+}
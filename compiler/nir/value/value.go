@@ -8,4 +8,19 @@ type Value interface {
 	Type() types.Type
 	IsConstant() bool
 	String() string // for debugging
+
+	// ID returns a identifier stable for the lifetime of the value, unique
+	// among the values of a single function (see Temporary.ID). It lets passes like
+	// nir/opt build a def-use map without relying on pointer identity.
+	ID() int
+}
+
+// nextValueID is the monotonically increasing source for every value's ID.
+// It isn't reset between functions: ids only need to be unique, not dense.
+var nextValueID int
+
+func newValueID() int {
+	id := nextValueID
+	nextValueID++
+	return id
 }
@@ -4,12 +4,14 @@ import "compiler/types"
 
 // Variable represents a named variable in the source code
 type Variable struct {
+	id           int
 	name         string
 	variableType types.Type
 }
 
 func NewVariable(name string, variableType types.Type) *Variable {
 	return &Variable{
+		id:           newValueID(),
 		name:         name,
 		variableType: variableType,
 	}
@@ -26,3 +28,8 @@ func (variable *Variable) IsConstant() bool {
 func (variable *Variable) String() string {
 	return variable.name
 }
+
+// ID implements Value.
+func (variable *Variable) ID() int {
+	return variable.id
+}
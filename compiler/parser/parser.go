@@ -5,28 +5,103 @@ import (
 	"compiler/errors"
 	"compiler/lexer"
 	"compiler/token"
+	"fmt"
 )
 
+// prefixParseFn parses an expression that begins with currentToken, e.g. a
+// literal, an identifier, or a prefix operator.
+type prefixParseFn func() ast.Expression
+
+// infixParseFn parses the rest of an expression given the already-parsed
+// left operand, with currentToken on the infix operator/delimiter.
+type infixParseFn func(left ast.Expression) ast.Expression
+
 // Parser analyzes tokens and builds an AST
 type Parser struct {
 	lexer          *lexer.Lexer
 	currentToken   token.Token
 	peekToken      token.Token
 	errorCollector *errors.ErrorCollector
+
+	prefixParseFns map[token.TokenType]prefixParseFn
+	infixParseFns  map[token.TokenType]infixParseFn
+
+	// syncPos and syncCnt back sync's panic-mode error recovery (see
+	// recovery.go): syncPos is the line sync last stopped at, and syncCnt
+	// counts consecutive calls to sync that stopped at that same line
+	// without the parser making progress.
+	syncPos int
+	syncCnt int
+
+	// mode and traceDepth back trace/untrace (see tracing.go): mode is
+	// checked on every trace call, and traceDepth is how many productions
+	// deep the current trace is, for indentation.
+	mode       Mode
+	traceDepth int
+
+	// functionDepth counts how many function bodies (FunctionStatement,
+	// including class methods and kernel functions, which all funnel
+	// through parseFunctionStatement) and `on` handler bodies currently
+	// enclose currentToken, so parseEventHandlerStatement can reject an
+	// `on` handler declared inside one of them. An if/while block doesn't
+	// raise it, so an `on` inside a top-level if or while body is accepted.
+	functionDepth int
 }
 
+// New creates a Parser with no optional modes enabled. Use NewWithMode to
+// turn on ModeTrace.
 func New(lexer *lexer.Lexer, errorCollector *errors.ErrorCollector) *Parser {
+	return NewWithMode(lexer, errorCollector, ModeNone)
+}
+
+// NewWithMode creates a Parser with mode's bits (see Mode) enabled.
+func NewWithMode(lexer *lexer.Lexer, errorCollector *errors.ErrorCollector, mode Mode) *Parser {
 	parser := &Parser{
 		lexer:          lexer,
 		errorCollector: errorCollector,
+		mode:           mode,
 	}
 
+	parser.prefixParseFns = make(map[token.TokenType]prefixParseFn)
+	parser.registerPrefix(token.IDENTIFIER, parser.parseIdentifier)
+	parser.registerPrefix(token.INT_LITERAL, parser.parseIntegerLiteral)
+	parser.registerPrefix(token.FLOAT_LITERAL, parser.parseFloatLiteral)
+	parser.registerPrefix(token.STRING_LITERAL, parser.parseStringLiteral)
+	parser.registerPrefix(token.THIS, parser.parseThisExpression)
+	parser.registerPrefix(token.LEFT_PAREN, parser.parseGroupedExpression)
+	parser.registerPrefix(token.MINUS, parser.parseUnaryExpression)
+	parser.registerPrefix(token.LOGICAL_NOT, parser.parseUnaryExpression)
+
+	parser.infixParseFns = make(map[token.TokenType]infixParseFn)
+	parser.registerInfix(token.LEFT_PAREN, parser.parseCallExpression)
+	parser.registerInfix(token.DOT, parser.parseMemberExpression)
+	parser.registerInfix(token.PLUS, parser.parseBinaryExpression)
+	parser.registerInfix(token.MINUS, parser.parseBinaryExpression)
+	parser.registerInfix(token.ASTERISK, parser.parseBinaryExpression)
+	parser.registerInfix(token.SLASH, parser.parseBinaryExpression)
+	parser.registerInfix(token.LESS_THAN, parser.parseBinaryExpression)
+	parser.registerInfix(token.GREATER_THAN, parser.parseBinaryExpression)
+	parser.registerInfix(token.LESS_THAN_EQUAL, parser.parseBinaryExpression)
+	parser.registerInfix(token.GREATER_THAN_EQUAL, parser.parseBinaryExpression)
+	parser.registerInfix(token.EQUAL, parser.parseBinaryExpression)
+	parser.registerInfix(token.NOT_EQUAL, parser.parseBinaryExpression)
+	parser.registerInfix(token.LOGICAL_AND, parser.parseBinaryExpression)
+	parser.registerInfix(token.LOGICAL_OR, parser.parseBinaryExpression)
+
 	parser.advance()
 	parser.advance()
 
 	return parser
 }
 
+func (parser *Parser) registerPrefix(tokenType token.TokenType, fn prefixParseFn) {
+	parser.prefixParseFns[tokenType] = fn
+}
+
+func (parser *Parser) registerInfix(tokenType token.TokenType, fn infixParseFn) {
+	parser.infixParseFns[tokenType] = fn
+}
+
 func (parser *Parser) advance() {
 	parser.currentToken = parser.peekToken
 	parser.peekToken = parser.lexer.NextToken()
@@ -43,28 +118,56 @@ func (parser *Parser) ParseProgram() *ast.Program {
 			continue
 		}
 
-		statement := parser.parseStatement()
+		statement := parser.parseStatementRecovering()
 
 		if statement != nil {
 			program.Statements = append(program.Statements, statement)
+			if parser.currentToken.Type != token.EOF && parser.currentToken.Type != token.NEW_LINE {
+				parser.advance()
+			}
+			continue
 		}
 
-		if parser.currentToken.Type != token.EOF && parser.currentToken.Type != token.NEW_LINE {
-			parser.advance()
-		}
+		// statement failed to parse: resynchronize at the next token that
+		// can plausibly start a new one, rather than advancing one token
+		// at a time and reporting a follow-on error for everything in
+		// between (see recovery.go).
+		parser.sync()
 	}
 
 	return program
 }
 
 func (parser *Parser) parseStatement() ast.Statement {
+	defer untrace(trace(parser, "parseStatement"))
+
 	switch parser.currentToken.Type {
 	case token.LET:
 		return parser.parseLetStatement()
 	case token.FUNC:
-		return parser.parseFunctionStatement()
+		return parser.parseFunctionStatement(false)
+	case token.KERNEL:
+		return parser.parseKernelFunctionStatement()
+	case token.EXTERN:
+		return parser.parseExternFunctionStatement()
+	case token.IMPORT:
+		return parser.parseImportStatement()
+	case token.EXPORT:
+		return parser.parseExportStatement()
 	case token.RETURN:
 		return parser.parseReturnStatement()
+	case token.IF:
+		return parser.parseIfStatement()
+	case token.WHILE:
+		return parser.parseWhileStatement()
+	case token.BREAK:
+		return parser.parseBreakStatement()
+	case token.ON:
+		return parser.parseEventHandlerStatement()
+	case token.CLASS:
+		return parser.parseClassStatement()
+	case token.STRUCT:
+		return parser.parseStructStatement()
 	case token.IDENTIFIER:
 		return parser.parseExpressionStatement()
 	default:
@@ -73,6 +176,8 @@ func (parser *Parser) parseStatement() ast.Statement {
 }
 
 func (parser *Parser) parseLetStatement() ast.Statement {
+	defer untrace(trace(parser, "parseLetStatement"))
+
 	letToken := parser.currentToken
 
 	isMutable := false
@@ -91,6 +196,24 @@ func (parser *Parser) parseLetStatement() ast.Statement {
 
 	parser.advance() // consume identifier
 
+	names := []*ast.Identifier{name}
+
+	// Destructuring a multi-value return: `let a, b = foo()`
+	for parser.currentToken.Type == token.COMMA {
+		parser.advance() // consume comma
+
+		if !parser.expect(token.IDENTIFIER) {
+			return nil
+		}
+
+		names = append(names, &ast.Identifier{
+			Token: parser.currentToken,
+			Value: parser.currentToken.Value,
+		})
+
+		parser.advance() // consume identifier
+	}
+
 	var typeAnnotation *ast.TypeAnnotation
 	if parser.currentToken.Type == token.COLON {
 
@@ -122,14 +245,25 @@ func (parser *Parser) parseLetStatement() ast.Statement {
 		Mutable:        isMutable,
 	}
 
+	if len(names) > 1 {
+		statement.Names = names
+	}
+
 	parser.skipEndOfStatement()
 
 	return statement
 }
 
-func (parser *Parser) parseFunctionStatement() ast.Statement {
+// parseFunctionStatement parses `func f(...) [-> T] { ... }`. When
+// isExtern is true, the body (and its braces) are omitted: extern
+// functions are declarations only, defined elsewhere (typically a C
+// library function), so parsing stops right after the signature.
+func (parser *Parser) parseFunctionStatement(isExtern bool) ast.Statement {
+	defer untrace(trace(parser, "parseFunctionStatement"))
+
 	function := &ast.FunctionStatement{
-		Token: parser.currentToken,
+		Token:  parser.currentToken,
+		Extern: isExtern,
 	}
 
 	if !parser.expectPeek(token.IDENTIFIER) {
@@ -148,29 +282,53 @@ func (parser *Parser) parseFunctionStatement() ast.Statement {
 		return nil
 	}
 
-	function.Parameters = parser.parseFunctionParameters()
+	parameters, variadic := parser.parseFunctionParameters()
+	function.Parameters = parameters
+	function.Variadic = variadic
 
-	if parser.peekToken.Type == token.ARROW {
-		parser.advance()
+	if parser.currentToken.Type == token.ARROW {
 		parser.advance() // consume '->'
 
-		if !parser.expect(token.IDENTIFIER) {
-			return nil
-		}
+		for {
+			if !isTypeToken(parser.currentToken.Type) {
+				parser.errorCollector.Add(errors.SyntaxError,
+					parser.currentToken.Line,
+					parser.currentToken.Column,
+					len(parser.currentToken.Value),
+					"expected a type, got %s",
+					parser.currentToken.Type.String())
+				return nil
+			}
+
+			returnType := &ast.TypeAnnotation{
+				Token: parser.currentToken,
+				Value: parser.currentToken.Value,
+			}
+			function.ReturnTypes = append(function.ReturnTypes, returnType)
 
-		function.ReturnType = &ast.TypeAnnotation{
-			Token: parser.currentToken,
-			Value: parser.currentToken.Value,
+			parser.advance() // consume return type
+
+			if parser.currentToken.Type != token.COMMA {
+				break
+			}
+			parser.advance() // consume comma
 		}
 
-		parser.advance() // consume return type
+		function.ReturnType = function.ReturnTypes[0]
+	}
+
+	if isExtern {
+		parser.skipEndOfStatement()
+		return function
 	}
 
 	if !parser.expect(token.LEFT_BRACE) {
 		return nil
 	}
 
+	parser.functionDepth++
 	function.Body = parser.parseBlockStatement()
+	parser.functionDepth--
 
 	if function.Body == nil {
 		return nil
@@ -179,20 +337,384 @@ func (parser *Parser) parseFunctionStatement() ast.Statement {
 	return function
 }
 
-func (parser *Parser) parseFunctionParameters() []*ast.FunctionParameter {
+// parseExternFunctionStatement parses `extern func f(...) [-> T]`, a
+// body-less declaration for a function defined elsewhere (typically a C
+// library function), e.g. `extern func printf(fmt: string, ...) -> int`.
+func (parser *Parser) parseExternFunctionStatement() ast.Statement {
+	parser.advance() // consume 'extern'
+
+	if !parser.expect(token.FUNC) {
+		return nil
+	}
+
+	return parser.parseFunctionStatement(true)
+}
+
+// parseImportStatement parses `import "path"`, a single module import.
+func (parser *Parser) parseImportStatement() ast.Statement {
+	importStatement := &ast.ImportStatement{
+		Token: parser.currentToken,
+	}
+
+	if !parser.expectPeek(token.STRING_LITERAL) {
+		return nil
+	}
+	parser.advance() // advance to the path string literal
+
+	importStatement.Path = parser.currentToken.Value
+
+	parser.skipEndOfStatement()
+
+	return importStatement
+}
+
+// parseExportStatement parses `export let ...` and `export func ...`,
+// marking the wrapped declaration as Exported so it can be resolved by
+// an importing module (see modules.Importer and nir.Module.Exports).
+func (parser *Parser) parseExportStatement() ast.Statement {
+	parser.advance() // consume 'export'
+
+	switch parser.currentToken.Type {
+	case token.LET:
+		statement := parser.parseLetStatement()
+		if letStatement, ok := statement.(*ast.LetStatement); ok {
+			letStatement.Exported = true
+		}
+		return statement
+	case token.FUNC:
+		statement := parser.parseFunctionStatement(false)
+		if functionStatement, ok := statement.(*ast.FunctionStatement); ok {
+			functionStatement.Exported = true
+		}
+		return statement
+	default:
+		parser.errorCollector.Add(
+			errors.SyntaxError,
+			parser.currentToken.Line,
+			parser.currentToken.Column,
+			len(parser.currentToken.Value),
+			"expected let or func after export, got %s",
+			parser.currentToken.Type.String(),
+		)
+		return nil
+	}
+}
+
+// parseKernelFunctionStatement parses `kernel func f(...)`, then marks the
+// resulting ast.FunctionStatement as a GPU entry point for the OpenCL/CUDA
+// backends (codegen.OpenCLGenerator, codegen.CUDAGenerator).
+func (parser *Parser) parseKernelFunctionStatement() ast.Statement {
+	parser.advance() // consume 'kernel'
+
+	if !parser.expect(token.FUNC) {
+		return nil
+	}
+
+	statement := parser.parseFunctionStatement(false)
+
+	function, ok := statement.(*ast.FunctionStatement)
+	if !ok {
+		return nil
+	}
+
+	function.Kernel = true
+	return function
+}
+
+// parseIfStatement parses `if condition { ... }`, an optional trailing
+// `else { ... }`, and `else if` chains (recursing into parseIfStatement
+// and wrapping the nested *ast.IfStatement in a single-statement
+// Alternative block so Alternative is always nil or a *BlockStatement).
+func (parser *Parser) parseIfStatement() ast.Statement {
+	defer untrace(trace(parser, "parseIfStatement"))
+
+	ifStatement := &ast.IfStatement{
+		Token: parser.currentToken,
+	}
+
+	parser.advance() // consume 'if'
+
+	ifStatement.Condition = parser.parseExpression(LOWEST)
+	if ifStatement.Condition == nil {
+		return nil
+	}
+
+	if !parser.expectPeek(token.LEFT_BRACE) {
+		return nil
+	}
+	parser.advance() // advance to '{'
+
+	ifStatement.Consequence = parser.parseBlockStatement()
+	if ifStatement.Consequence == nil {
+		return nil
+	}
+
+	if parser.currentToken.Type == token.NEW_LINE && parser.peekToken.Type == token.ELSE {
+		parser.advance() // consume newline before 'else'
+	}
+
+	if parser.currentToken.Type != token.ELSE {
+		return ifStatement
+	}
+
+	parser.advance() // consume 'else'
+
+	if parser.currentToken.Type == token.IF {
+		elseIfToken := parser.currentToken
+
+		nestedIf := parser.parseIfStatement()
+		if nestedIf == nil {
+			return nil
+		}
+
+		ifStatement.Alternative = &ast.BlockStatement{
+			Token:      elseIfToken,
+			Statements: []ast.Statement{nestedIf},
+		}
+		return ifStatement
+	}
+
+	if !parser.expect(token.LEFT_BRACE) {
+		return nil
+	}
+
+	ifStatement.Alternative = parser.parseBlockStatement()
+	if ifStatement.Alternative == nil {
+		return nil
+	}
+
+	return ifStatement
+}
+
+// parseWhileStatement parses `while condition { ... }`.
+func (parser *Parser) parseWhileStatement() ast.Statement {
+	defer untrace(trace(parser, "parseWhileStatement"))
+
+	whileStatement := &ast.WhileStatement{
+		Token: parser.currentToken,
+	}
+
+	parser.advance() // consume 'while'
+
+	whileStatement.Condition = parser.parseExpression(LOWEST)
+	if whileStatement.Condition == nil {
+		return nil
+	}
+
+	if !parser.expectPeek(token.LEFT_BRACE) {
+		return nil
+	}
+	parser.advance() // advance to '{'
+
+	whileStatement.Body = parser.parseBlockStatement()
+	if whileStatement.Body == nil {
+		return nil
+	}
+
+	return whileStatement
+}
+
+// parseClassStatement parses `class Name { ... }`, a body of `name: Type`
+// field declarations interleaved with `func` method declarations in any
+// order, each separated by a newline.
+func (parser *Parser) parseClassStatement() ast.Statement {
+	class := &ast.ClassStatement{
+		Token: parser.currentToken,
+	}
+
+	if !parser.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+	parser.advance() // consume 'class'
+
+	class.Name = &ast.Identifier{
+		Token: parser.currentToken,
+		Value: parser.currentToken.Value,
+	}
+
+	if !parser.expectPeek(token.LEFT_BRACE) {
+		return nil
+	}
+	parser.advance() // consume class name
+	parser.advance() // consume '{'
+
+	for parser.currentToken.Type != token.RIGHT_BRACE && parser.currentToken.Type != token.EOF {
+		if parser.currentToken.Type == token.NEW_LINE {
+			parser.advance()
+			continue
+		}
+
+		if parser.currentToken.Type == token.FUNC {
+			method, ok := parser.parseFunctionStatement(false).(*ast.FunctionStatement)
+			if !ok {
+				return nil
+			}
+			class.Methods = append(class.Methods, method)
+		} else if parser.currentToken.Type == token.IDENTIFIER {
+			field := parser.parseClassField()
+			if field == nil {
+				return nil
+			}
+			class.Fields = append(class.Fields, field)
+		} else {
+			parser.errorCollector.Add(errors.SyntaxError,
+				parser.currentToken.Line,
+				parser.currentToken.Column,
+				len(parser.currentToken.Value),
+				"expected a field or method declaration, got %s",
+				parser.currentToken.Type.String(),
+			)
+			return nil
+		}
+
+		if parser.currentToken.Type != token.RIGHT_BRACE && parser.currentToken.Type != token.EOF {
+			parser.advance()
+		}
+	}
+
+	if !parser.expect(token.RIGHT_BRACE) {
+		return nil
+	}
+
+	return class
+}
+
+// parseClassField parses a single `name: Type` field declaration inside a
+// class body.
+func (parser *Parser) parseClassField() *ast.ClassField {
+	name := &ast.Identifier{
+		Token: parser.currentToken,
+		Value: parser.currentToken.Value,
+	}
+
+	parser.advance() // consume field name
+
+	typeAnnotation := parser.parseTypeAnnotation()
+	if typeAnnotation == nil {
+		return nil
+	}
+
+	return &ast.ClassField{
+		Name: name,
+		Type: *typeAnnotation,
+	}
+}
+
+// parseStructStatement parses `struct Name { ... }`, a body of `name:
+// Type` field declarations separated by a newline. Unlike a class body, a
+// struct has no `func` methods.
+func (parser *Parser) parseStructStatement() ast.Statement {
+	structStmt := &ast.StructStatement{
+		Token: parser.currentToken,
+	}
+
+	if !parser.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+	parser.advance() // consume 'struct'
+
+	structStmt.Name = &ast.Identifier{
+		Token: parser.currentToken,
+		Value: parser.currentToken.Value,
+	}
+
+	if !parser.expectPeek(token.LEFT_BRACE) {
+		return nil
+	}
+	parser.advance() // consume struct name
+	parser.advance() // consume '{'
+
+	for parser.currentToken.Type != token.RIGHT_BRACE && parser.currentToken.Type != token.EOF {
+		if parser.currentToken.Type == token.NEW_LINE {
+			parser.advance()
+			continue
+		}
+
+		if parser.currentToken.Type != token.IDENTIFIER {
+			parser.errorCollector.Add(errors.SyntaxError,
+				parser.currentToken.Line,
+				parser.currentToken.Column,
+				len(parser.currentToken.Value),
+				"expected a field declaration, got %s",
+				parser.currentToken.Type.String(),
+			)
+			return nil
+		}
+
+		field := parser.parseStructField()
+		if field == nil {
+			return nil
+		}
+		structStmt.Fields = append(structStmt.Fields, field)
+
+		if parser.currentToken.Type != token.RIGHT_BRACE && parser.currentToken.Type != token.EOF {
+			parser.advance()
+		}
+	}
+
+	if !parser.expect(token.RIGHT_BRACE) {
+		return nil
+	}
+
+	return structStmt
+}
+
+// parseStructField parses a single `name: Type` field declaration inside
+// a struct body.
+func (parser *Parser) parseStructField() *ast.StructField {
+	name := &ast.Identifier{
+		Token: parser.currentToken,
+		Value: parser.currentToken.Value,
+	}
+
+	parser.advance() // consume field name
+
+	typeAnnotation := parser.parseTypeAnnotation()
+	if typeAnnotation == nil {
+		return nil
+	}
+
+	return &ast.StructField{
+		Name: name,
+		Type: *typeAnnotation,
+	}
+}
+
+// parseFunctionParameters parses the parenthesized parameter list,
+// including a trailing `...` marking the function as variadic (only
+// meaningful on `extern` declarations, since variadic calls pass the
+// trailing arguments directly with no Naviary-side type checking).
+func (parser *Parser) parseFunctionParameters() ([]*ast.FunctionParameter, bool) {
 	parameters := []*ast.FunctionParameter{}
+	variadic := false
 
 	if parser.peekToken.Type == token.RIGHT_PAREN {
 		parser.advance()
 		parser.advance() // consume '()'
-		return parameters
+		return parameters, variadic
 	}
 
 	parser.advance() // consume '('
 
 	for {
+		if parser.currentToken.Type == token.ELLIPSIS {
+			variadic = true
+			parser.advance() // consume '...'
+			break
+		}
+
+		// Kernel parameters may carry an optional GPU memory-space
+		// qualifier ("global", "local", "shared", "private") before
+		// the parameter name.
+		memorySpace := ""
+		switch parser.currentToken.Type {
+		case token.GLOBAL, token.LOCAL, token.SHARED, token.PRIVATE:
+			memorySpace = parser.currentToken.Value
+			parser.advance() // consume memory-space qualifier
+		}
+
 		if !parser.expect(token.IDENTIFIER) {
-			return nil
+			return nil, false
 		}
 
 		parameter := &ast.FunctionParameter{
@@ -200,13 +722,14 @@ func (parser *Parser) parseFunctionParameters() []*ast.FunctionParameter {
 				Token: parser.currentToken,
 				Value: parser.currentToken.Value,
 			},
+			MemorySpace: memorySpace,
 		}
 
 		parser.advance() // consume parameter name
 
 		parameterType := parser.parseTypeAnnotation()
 		if parameterType == nil {
-			return nil
+			return nil, false
 		}
 
 		parameter.Type = *parameterType
@@ -216,16 +739,17 @@ func (parser *Parser) parseFunctionParameters() []*ast.FunctionParameter {
 			parser.advance() // consume comma
 			continue
 		}
-		if !parser.expect(token.RIGHT_PAREN) {
-			return nil
-		}
 
 		break
 	}
 
+	if !parser.expect(token.RIGHT_PAREN) {
+		return nil, false
+	}
+
 	parser.advance() // consume ')'
 
-	return parameters
+	return parameters, variadic
 }
 
 func (parser *Parser) parseBlockStatement() *ast.BlockStatement {
@@ -241,14 +765,19 @@ func (parser *Parser) parseBlockStatement() *ast.BlockStatement {
 			continue
 		}
 
-		statement := parser.parseStatement()
+		statement := parser.parseStatementRecovering()
 		if statement != nil {
 			block.Statements = append(block.Statements, statement)
+			if parser.currentToken.Type != token.RIGHT_BRACE && parser.currentToken.Type != token.EOF {
+				parser.advance()
+			}
+			continue
 		}
 
-		if parser.currentToken.Type != token.RIGHT_BRACE && parser.currentToken.Type != token.EOF {
-			parser.advance()
-		}
+		// isSyncPoint treats RIGHT_BRACE as a stopping point too, so this
+		// can't skip past the brace that closes this block (see
+		// recovery.go).
+		parser.sync()
 	}
 
 	if !parser.expect(token.RIGHT_BRACE) {
@@ -260,6 +789,8 @@ func (parser *Parser) parseBlockStatement() *ast.BlockStatement {
 }
 
 func (parser *Parser) parseExpressionStatement() ast.Statement {
+	defer untrace(trace(parser, "parseExpressionStatement"))
+
 	statement := &ast.ExpressionStatement{
 		Token:      parser.currentToken,
 		Expression: parser.parseExpression(LOWEST),
@@ -271,13 +802,36 @@ func (parser *Parser) parseExpressionStatement() ast.Statement {
 }
 
 func (parser *Parser) parseReturnStatement() ast.Statement {
+	defer untrace(trace(parser, "parseReturnStatement"))
+
 	returnStatement := &ast.ReturnStatement{
 		Token: parser.currentToken,
 	}
 
 	parser.advance()
 
-	returnStatement.ReturnValue = parser.parseExpression(LOWEST)
+	firstValue := parser.parseExpression(LOWEST)
+	if firstValue == nil {
+		return returnStatement
+	}
+
+	returnStatement.ReturnValue = firstValue
+	returnStatement.ReturnValues = []ast.Expression{firstValue}
+
+	for parser.peekToken.Type == token.COMMA {
+		parser.advance()
+		parser.advance() // consume ','
+
+		value := parser.parseExpression(LOWEST)
+		if value == nil {
+			return returnStatement
+		}
+		returnStatement.ReturnValues = append(returnStatement.ReturnValues, value)
+	}
+
+	if len(returnStatement.ReturnValues) == 1 {
+		returnStatement.ReturnValues = nil
+	}
 
 	parser.skipEndOfStatement()
 
@@ -285,47 +839,180 @@ func (parser *Parser) parseReturnStatement() ast.Statement {
 
 }
 
+func (parser *Parser) parseBreakStatement() ast.Statement {
+	defer untrace(trace(parser, "parseBreakStatement"))
+
+	breakStatement := &ast.BreakStatement{
+		Token: parser.currentToken,
+	}
+
+	parser.skipEndOfStatement()
+
+	return breakStatement
+}
+
+// parseEventHandlerStatement parses `on <event_name>(params) { ... }`, a
+// declaration rejected whenever functionDepth says currentToken is inside
+// a function body (a class method, kernel function or another handler
+// included), wiring Body to run when the host fires event_name.
+// Unlike parseFunctionStatement it never parses a return type: the host
+// runtime invoking a handler has nothing to do with a return value.
+func (parser *Parser) parseEventHandlerStatement() ast.Statement {
+	defer untrace(trace(parser, "parseEventHandlerStatement"))
+
+	if parser.functionDepth > 0 {
+		parser.errorCollector.Add(errors.SyntaxError, parser.currentToken.Line, parser.currentToken.Column, len(parser.currentToken.Value), "'on' event handlers may only be declared at program top level, not inside a function body")
+		return nil
+	}
+
+	handler := &ast.EventHandlerStatement{
+		Token: parser.currentToken,
+	}
+
+	if !parser.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+	parser.advance() // consume 'on'
+
+	handler.EventName = &ast.Identifier{
+		Token: parser.currentToken,
+		Value: parser.currentToken.Value,
+	}
+
+	parser.advance() // consume event name
+	if !parser.expect(token.LEFT_PAREN) {
+		return nil
+	}
+
+	parameters, _ := parser.parseFunctionParameters()
+	handler.Parameters = parameters
+
+	if !parser.expect(token.LEFT_BRACE) {
+		return nil
+	}
+
+	parser.functionDepth++
+	handler.Body = parser.parseBlockStatement()
+	parser.functionDepth--
+
+	if handler.Body == nil {
+		return nil
+	}
+
+	return handler
+}
+
+// parseExpression is a Pratt parser: it dispatches on currentToken's type
+// to a registered prefixParseFn to get a left operand, then repeatedly
+// dispatches on peekToken's type to a registered infixParseFn as long as
+// that token binds tighter than precedence.
 func (parser *Parser) parseExpression(precedence int) ast.Expression {
-	left := parser.parseAtom()
+	defer untrace(trace(parser, fmt.Sprintf("parseExpression(precedence=%d)", precedence)))
+
+	prefix := parser.prefixParseFns[parser.currentToken.Type]
+	if prefix == nil {
+		parser.errorCollector.Add(errors.SyntaxError,
+			parser.currentToken.Line,
+			parser.currentToken.Column,
+			len(parser.currentToken.Value),
+			"Unexpected token '%s' in expression",
+			parser.currentToken.Type.String(),
+		)
+		// There's no sensible left operand to keep building on: unwind all
+		// the way out to the nearest parseStatementRecovering instead of
+		// returning nil through every frame between here and there (see
+		// recovery.go).
+		panic(bailout{})
+	}
+
+	left := prefix()
 	if left == nil {
 		return nil
 	}
 
 	for !parser.isStatementEnd() && precedence < getPrecedence(parser.peekToken.Type) {
-
-		if parser.peekToken.Type == token.LEFT_PAREN {
-			parser.advance() // advance to '('
-			left = parser.parseCallExpression(left)
-			continue
-		}
-
-		if !parser.peekToken.Type.IsOperator() {
+		infix := parser.infixParseFns[parser.peekToken.Type]
+		if infix == nil {
 			break
 		}
 
-		operatorToken := parser.peekToken
-		operatorPrecedence := getPrecedence(operatorToken.Type)
-
 		parser.advance()
-		parser.advance() // advance to right operand
 
-		right := parser.parseExpression(operatorPrecedence)
-		if right == nil {
+		left = infix(left)
+		if left == nil {
 			return nil
 		}
-
-		left = &ast.BinaryExpression{
-			Token:    operatorToken,
-			Left:     left,
-			Operator: operatorToken.Value,
-			Right:    right,
-		}
 	}
 
 	return left
 }
 
+// parseBinaryExpression parses the right-hand side of an infix binary
+// operator (currentToken is the operator), recursing at the operator's own
+// precedence so operators of equal precedence are left-associative.
+func (parser *Parser) parseBinaryExpression(left ast.Expression) ast.Expression {
+	operatorToken := parser.currentToken
+	operatorPrecedence := getPrecedence(operatorToken.Type)
+
+	defer untrace(trace(parser, fmt.Sprintf("parseBinaryExpression(precedence=%d)", operatorPrecedence)))
+
+	parser.advance() // advance to right operand
+
+	right := parser.parseExpression(operatorPrecedence)
+	if right == nil {
+		return nil
+	}
+
+	return &ast.BinaryExpression{
+		Token:    operatorToken,
+		Left:     left,
+		Operator: operatorToken.Value,
+		Right:    right,
+	}
+}
+
+// parseUnaryExpression parses a prefix `-x` or `!x` (currentToken is the
+// operator), binding tighter than any binary operator (though looser than
+// a call or `.`) so `-a + b` parses as `(-a) + b`.
+func (parser *Parser) parseUnaryExpression() ast.Expression {
+	defer untrace(trace(parser, "parseUnaryExpression"))
+
+	operatorToken := parser.currentToken
+
+	parser.advance() // advance to operand
+
+	operand := parser.parseExpression(UNARY)
+	if operand == nil {
+		return nil
+	}
+
+	return &ast.UnaryExpression{
+		Token:    operatorToken,
+		Operator: operatorToken.Value,
+		Operand:  operand,
+	}
+}
+
+// parseGroupedExpression parses `(expr)`, currentToken on the '('.
+func (parser *Parser) parseGroupedExpression() ast.Expression {
+	parser.advance() // consume '('
+
+	expression := parser.parseExpression(LOWEST)
+	if expression == nil {
+		return nil
+	}
+
+	if !parser.expectPeek(token.RIGHT_PAREN) {
+		return nil
+	}
+	parser.advance() // consume ')'
+
+	return expression
+}
+
 func (parser *Parser) parseCallExpression(function ast.Expression) ast.Expression {
+	defer untrace(trace(parser, "parseCallExpression"))
+
 	call := &ast.CallExpression{
 		Token:     parser.currentToken,
 		Function:  function,
@@ -337,6 +1024,29 @@ func (parser *Parser) parseCallExpression(function ast.Expression) ast.Expressio
 	return call
 }
 
+// parseMemberExpression parses `object.property`, called with
+// currentToken on the '.' that follows object.
+func (parser *Parser) parseMemberExpression(object ast.Expression) ast.Expression {
+	defer untrace(trace(parser, "parseMemberExpression"))
+
+	member := &ast.MemberExpression{
+		Token:  parser.currentToken,
+		Object: object,
+	}
+
+	if !parser.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+	parser.advance() // consume '.'
+
+	member.Property = &ast.Identifier{
+		Token: parser.currentToken,
+		Value: parser.currentToken.Value,
+	}
+
+	return member
+}
+
 func (parser *Parser) parseCallArguments() []ast.Expression {
 	arguments := []ast.Expression{}
 
@@ -369,28 +1079,37 @@ func (parser *Parser) skipEndOfStatement() {
 	}
 }
 
-// parseAtom parses an literals and identifiers
-func (parser *Parser) parseAtom() ast.Expression {
-	switch parser.currentToken.Type {
-	case token.INT_LITERAL:
-		return &ast.IntegerLiteral{
-			Token: parser.currentToken,
-			Value: parser.currentToken.Value,
-		}
-	case token.IDENTIFIER:
-		return &ast.Identifier{
-			Token: parser.currentToken,
-			Value: parser.currentToken.Value,
-		}
-	default:
-		parser.errorCollector.Add(errors.SyntaxError,
-			parser.currentToken.Line,
-			parser.currentToken.Column,
-			len(parser.currentToken.Value),
-			"Unexpected token '%s' in expression",
-			parser.currentToken.Type.String(),
-		)
-		return nil
+func (parser *Parser) parseIdentifier() ast.Expression {
+	return &ast.Identifier{
+		Token: parser.currentToken,
+		Value: parser.currentToken.Value,
+	}
+}
+
+func (parser *Parser) parseIntegerLiteral() ast.Expression {
+	return &ast.IntegerLiteral{
+		Token: parser.currentToken,
+		Value: parser.currentToken.Value,
+	}
+}
+
+func (parser *Parser) parseFloatLiteral() ast.Expression {
+	return &ast.FloatLiteral{
+		Token: parser.currentToken,
+		Value: parser.currentToken.Value,
+	}
+}
+
+func (parser *Parser) parseStringLiteral() ast.Expression {
+	return &ast.StringLiteral{
+		Token: parser.currentToken,
+		Value: parser.currentToken.Value,
+	}
+}
+
+func (parser *Parser) parseThisExpression() ast.Expression {
+	return &ast.ThisExpression{
+		Token: parser.currentToken,
 	}
 }
 
@@ -436,6 +1155,22 @@ func (parser *Parser) expect(tokenType token.TokenType) bool {
 	return false
 }
 
+// isTypeToken reports whether tokenType can start a type name: either one
+// of the built-in primitive/sized-numeric keywords, or a user-defined type
+// referenced by identifier (e.g. a struct or class name).
+func isTypeToken(tokenType token.TokenType) bool {
+	switch tokenType {
+	case token.INT, token.FLOAT, token.STRING, token.BOOL,
+		token.I8, token.I16, token.I32, token.I64,
+		token.U8, token.U16, token.U32, token.U64,
+		token.F32, token.F64,
+		token.IDENTIFIER:
+		return true
+	default:
+		return false
+	}
+}
+
 func (parser *Parser) parseTypeAnnotation() *ast.TypeAnnotation {
 	if !parser.expect(token.COLON) {
 		return nil
@@ -443,19 +1178,17 @@ func (parser *Parser) parseTypeAnnotation() *ast.TypeAnnotation {
 
 	parser.advance() // consume ':'
 
-	switch parser.currentToken.Type {
-	case token.INT, token.FLOAT, token.STRING, token.BOOL, token.IDENTIFIER:
+	if !isTypeToken(parser.currentToken.Type) {
+		return nil
+	}
 
-		typeAnnotation := &ast.TypeAnnotation{
-			Token: parser.currentToken,
-			Value: parser.currentToken.Value,
-		}
+	typeAnnotation := &ast.TypeAnnotation{
+		Token: parser.currentToken,
+		Value: parser.currentToken.Value,
+	}
 
-		parser.advance() // consume type
+	parser.advance() // consume type
 
-		return typeAnnotation
-	default:
-		return nil
-	}
+	return typeAnnotation
 
 }
@@ -1,8 +1,9 @@
 package parser
 
 import (
-	"naviary/compiler/ast"
-	"naviary/compiler/lexer"
+	"compiler/ast"
+	"compiler/errors"
+	"compiler/lexer"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -115,15 +116,16 @@ func TestParseLetStatements(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create lexer and parser
-			lex := lexer.New(tt.input, "test.navi")
-			parser := New(lex)
+			errorCollector := errors.New(tt.input, "test.navi")
+			lex := lexer.New(tt.input, "test.navi", errorCollector)
+			parser := New(lex, errorCollector)
 
 			// Parse program
 			program := parser.ParseProgram()
 
 			// Check for parse errors
-			if lex.Errors().HasErrors() {
-				t.Fatalf("lexer errors: %v", lex.Errors())
+			if errorCollector.HasErrors() {
+				t.Fatalf("parser errors: %v", errorCollector)
 			}
 
 			// Should have exactly one statement
@@ -169,12 +171,13 @@ let mut y = 10
 let name := "Bob"
 `
 
-	lex := lexer.New(input, "test.navi")
-	parser := New(lex)
+	errorCollector := errors.New(input, "test.navi")
+	lex := lexer.New(input, "test.navi", errorCollector)
+	parser := New(lex, errorCollector)
 	program := parser.ParseProgram()
 
 	// Check no errors
-	assert.False(t, lex.Errors().HasErrors())
+	assert.False(t, errorCollector.HasErrors())
 
 	// Should have 3 statements
 	assert.Len(t, program.Statements, 3)
@@ -218,12 +221,13 @@ func TestParseErrors(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			lex := lexer.New(tt.input, "test.navi")
-			parser := New(lex)
+			errorCollector := errors.New(tt.input, "test.navi")
+			lex := lexer.New(tt.input, "test.navi", errorCollector)
+			parser := New(lex, errorCollector)
 			parser.ParseProgram()
 
 			// Should have errors
-			assert.True(t, lex.Errors().HasErrors())
+			assert.True(t, errorCollector.HasErrors())
 		})
 	}
 }
@@ -253,11 +257,12 @@ func TestStatementTerminators(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			lex := lexer.New(tt.input, "test.navi")
-			parser := New(lex)
+			errorCollector := errors.New(tt.input, "test.navi")
+			lex := lexer.New(tt.input, "test.navi", errorCollector)
+			parser := New(lex, errorCollector)
 			program := parser.ParseProgram()
 
-			assert.False(t, lex.Errors().HasErrors())
+			assert.False(t, errorCollector.HasErrors())
 			assert.Len(t, program.Statements, tt.count)
 		})
 	}
@@ -313,13 +318,14 @@ func TestParseInfixExpressions(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			lex := lexer.New(tt.input, "test.navi")
-			parser := New(lex)
+			errorCollector := errors.New(tt.input, "test.navi")
+			lex := lexer.New(tt.input, "test.navi", errorCollector)
+			parser := New(lex, errorCollector)
 			program := parser.ParseProgram()
 
 			// Check for errors
-			assert.False(t, lex.Errors().HasErrors(),
-				"parser errors: %v", lex.Errors())
+			assert.False(t, errorCollector.HasErrors(),
+				"parser errors: %v", errorCollector)
 
 			// Check we have one statement
 			require.Len(t, program.Statements, 1)
@@ -411,15 +417,16 @@ func TestParseFunctionStatements(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create lexer and parser
-			lex := lexer.New(tt.input, "test.navi")
-			parser := New(lex)
+			errorCollector := errors.New(tt.input, "test.navi")
+			lex := lexer.New(tt.input, "test.navi", errorCollector)
+			parser := New(lex, errorCollector)
 
 			// Parse program
 			program := parser.ParseProgram()
 
 			// Check for parse errors
-			if parser.Errors().HasErrors() {
-				t.Fatalf("parser errors: %v", parser.Errors())
+			if errorCollector.HasErrors() {
+				t.Fatalf("parser errors: %v", errorCollector)
 			}
 
 			// Should have exactly one statement
@@ -533,15 +540,16 @@ func TestParseFunctionCalls(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create lexer and parser
-			lex := lexer.New(tt.input, "test.navi")
-			parser := New(lex)
+			errorCollector := errors.New(tt.input, "test.navi")
+			lex := lexer.New(tt.input, "test.navi", errorCollector)
+			parser := New(lex, errorCollector)
 
 			// Parse program
 			program := parser.ParseProgram()
 
 			// Check for parse errors
-			if parser.Errors().HasErrors() {
-				t.Fatalf("parser errors: %v", parser.Errors())
+			if errorCollector.HasErrors() {
+				t.Fatalf("parser errors: %v", errorCollector)
 			}
 
 			// Should have exactly one statement
@@ -580,12 +588,13 @@ func main() {
 }
 `
 
-	lex := lexer.New(input, "test.navi")
-	parser := New(lex)
+	errorCollector := errors.New(input, "test.navi")
+	lex := lexer.New(input, "test.navi", errorCollector)
+	parser := New(lex, errorCollector)
 	program := parser.ParseProgram()
 
 	// Check for parse errors
-	assert.False(t, parser.Errors().HasErrors(), "parser should have no errors")
+	assert.False(t, errorCollector.HasErrors(), "parser should have no errors")
 
 	// Should have one function statement
 	require.Len(t, program.Statements, 1)
@@ -602,3 +611,37 @@ func main() {
 		assert.True(t, ok, "statement %d should be ExpressionStatement", i)
 	}
 }
+
+// Test import and export statement parsing
+func TestParseImportAndExportStatements(t *testing.T) {
+	input := `
+import "mathutils"
+
+export func square(x: int) -> int {
+	return x * x
+}
+
+export let limit = 10
+`
+
+	errorCollector := errors.New(input, "test.navi")
+	lex := lexer.New(input, "test.navi", errorCollector)
+	parser := New(lex, errorCollector)
+	program := parser.ParseProgram()
+
+	require.False(t, errorCollector.HasErrors(), "parser should have no errors")
+	require.Len(t, program.Statements, 3)
+
+	importStmt, ok := program.Statements[0].(*ast.ImportStatement)
+	require.True(t, ok, "first statement should be ImportStatement")
+	assert.Equal(t, "mathutils", importStmt.Path)
+
+	funcStmt, ok := program.Statements[1].(*ast.FunctionStatement)
+	require.True(t, ok, "second statement should be FunctionStatement")
+	assert.True(t, funcStmt.Exported)
+	assert.Equal(t, "square", funcStmt.Name.Value)
+
+	letStmt, ok := program.Statements[2].(*ast.LetStatement)
+	require.True(t, ok, "third statement should be LetStatement")
+	assert.True(t, letStmt.Exported)
+}
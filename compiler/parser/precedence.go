@@ -22,8 +22,9 @@ const (
 	SUM                // 14: +, -
 	PRODUCT            // 15: *, /, %
 	EXPONENT           // 16: ** (future)
-	UNARY              // 17: !, ~, -, + prefix (future)
-	CALL               // 18: function(), [], ., ?., :: (highest)
+	UNARY              // 17: !, - prefix (~, + prefix: future)
+	CALL               // 18: function(), . (?. and :: future)
+	INDEX              // 19: a[i] (future, awaits [ ] tokens)
 )
 
 // precedenceMap maps token types to their precedence level
@@ -34,18 +35,18 @@ var precedenceMap = map[token.TokenType]int{
 	token.SLASH:    PRODUCT,
 
 	// Comparison operators
-	// token.LESS_THAN:          COMPARISON,
-	// token.GREATER_THAN:       COMPARISON,
-	// token.LESS_THAN_EQUAL:    COMPARISON,
-	// token.GREATER_THAN_EQUAL: COMPARISON,
+	token.LESS_THAN:          COMPARISON,
+	token.GREATER_THAN:       COMPARISON,
+	token.LESS_THAN_EQUAL:    COMPARISON,
+	token.GREATER_THAN_EQUAL: COMPARISON,
 
 	// Equality operators
-	// token.EQUAL:     EQUALITY,
-	// token.NOT_EQUAL: EQUALITY,
+	token.EQUAL:     EQUALITY,
+	token.NOT_EQUAL: EQUALITY,
 
 	// Logical operators
-	// token.LOGICAL_AND: LOGICAL_AND,
-	// token.LOGICAL_OR:  LOGICAL_OR,
+	token.LOGICAL_AND: LOGICAL_AND,
+	token.LOGICAL_OR:  LOGICAL_OR,
 
 	// Assignment operators
 	// token.ASSIGN:          ASSIGNMENT,
@@ -55,8 +56,9 @@ var precedenceMap = map[token.TokenType]int{
 	// token.ASTERISK_ASSIGN: ASSIGNMENT,
 	// token.SLASH_ASSIGN:    ASSIGNMENT,
 
-	// Function call has highest precedence
+	// Function call and member access both have highest precedence
 	token.LEFT_PAREN: CALL,
+	token.DOT:        CALL,
 }
 
 // getPrecedence returns the precedence level for a given token type
@@ -1,7 +1,7 @@
 package parser
 
 import (
-	"naviary/compiler/token"
+	"compiler/token"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,7 +24,7 @@ func TestGetPrecedence(t *testing.T) {
 		{"greater than should have COMPARISON precedence", token.GREATER_THAN, COMPARISON},
 
 		// Non-operators should have LOWEST precedence
-		{"identifier should have LOWEST precedence", token.IDENT, LOWEST},
+		{"identifier should have LOWEST precedence", token.IDENTIFIER, LOWEST},
 		{"integer should have LOWEST precedence", token.INT, LOWEST},
 		{"EOF should have LOWEST precedence", token.EOF, LOWEST},
 	}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"compiler/ast"
+	"compiler/token"
+)
+
+// bailout is the sentinel value parseExpression panics with when it hits
+// a token with no registered prefix parse function (see parser.go):
+// there's no left operand to keep building on, so unwinding all the way
+// out to parseStatementRecovering beats returning nil through every
+// frame in between and hoping each one checks for it.
+type bailout struct{}
+
+// maxSyncAttemptsWithoutProgress bounds how many times sync can stop at
+// the same line before giving up and force-advancing a token, so
+// ParseProgram's loop is guaranteed to terminate even on pathological
+// input (e.g. a single token that can never be a sync point).
+const maxSyncAttemptsWithoutProgress = 10
+
+// parseStatementRecovering calls parseStatement, recovering from a
+// bailout panic so a single malformed statement can't abort parsing of
+// the rest of the file. This, together with sync below, is modelled on
+// Go's go/parser panic-mode recovery: ParseProgram always returns a
+// partial AST plus a full ErrorCollector, which is what lets tooling
+// (LSP, REPL) get something useful out of broken input instead of
+// nothing at all.
+func (parser *Parser) parseStatementRecovering() (statement ast.Statement) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if _, ok := recovered.(bailout); !ok {
+				panic(recovered)
+			}
+			statement = nil
+		}
+	}()
+
+	return parser.parseStatement()
+}
+
+// sync implements panic-mode error recovery: it advances past tokens
+// until it reaches one that can plausibly start a new statement, so a
+// syntax error is reported once instead of cascading into a follow-on
+// error for every token between the failure and the next real statement.
+func (parser *Parser) sync() {
+	for parser.currentToken.Type != token.EOF {
+		if parser.isSyncPoint() {
+			if parser.currentToken.Line == parser.syncPos {
+				parser.syncCnt++
+				if parser.syncCnt > maxSyncAttemptsWithoutProgress {
+					parser.syncCnt = 0
+					parser.advance()
+				}
+				return
+			}
+
+			parser.syncPos = parser.currentToken.Line
+			parser.syncCnt = 0
+			return
+		}
+
+		parser.advance()
+	}
+}
+
+// isSyncPoint reports whether currentToken can plausibly start a new
+// statement: a statement keyword, a NEW_LINE or '}' that already ends the
+// broken one, or a bare identifier at the start of a line (the common
+// case of a call or assignment statement).
+func (parser *Parser) isSyncPoint() bool {
+	switch parser.currentToken.Type {
+	case token.LET, token.FUNC, token.RETURN, token.IF, token.WHILE,
+		token.BREAK, token.CLASS, token.STRUCT, token.IMPORT, token.EXPORT,
+		token.EXTERN, token.KERNEL, token.ON, token.NEW_LINE, token.RIGHT_BRACE:
+		return true
+	}
+
+	return parser.currentToken.Type == token.IDENTIFIER && parser.currentToken.Column == 1
+}
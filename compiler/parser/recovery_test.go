@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"compiler/errors"
+	"compiler/lexer"
+	"testing"
+)
+
+// TestParseProgramRecoversFromBadExpression checks that a single
+// malformed statement doesn't stop ParseProgram from returning the
+// well-formed statements around it (see recovery.go's sync/bailout).
+func TestParseProgramRecoversFromBadExpression(t *testing.T) {
+	source := "let x = 1\nlet y = )\nlet z = 3\n"
+	errorCollector := errors.New(source, "test.navi")
+	parserInstance := New(lexer.New(source, "test.navi", errorCollector), errorCollector)
+
+	program := parserInstance.ParseProgram()
+
+	if !errorCollector.HasErrors() {
+		t.Fatal("expected the malformed `let y = )` to report an error")
+	}
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("expected 2 well-formed statements to survive, got %d", len(program.Statements))
+	}
+}
+
+// TestSyncStopsAtNextStatementKeyword checks that sync advances past a
+// run of junk tokens and stops once it reaches a token that can start a
+// new statement, rather than consuming the whole rest of the file.
+func TestSyncStopsAtNextStatementKeyword(t *testing.T) {
+	source := ") ) ) let x = 1"
+	errorCollector := errors.New(source, "test.navi")
+	parserInstance := New(lexer.New(source, "test.navi", errorCollector), errorCollector)
+
+	parserInstance.sync()
+
+	if parserInstance.currentToken.Value != "let" {
+		t.Errorf("sync stopped on %q, want \"let\"", parserInstance.currentToken.Value)
+	}
+}
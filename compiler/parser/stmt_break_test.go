@@ -0,0 +1,36 @@
+package parser
+
+import (
+	"compiler/ast"
+	"compiler/errors"
+	"compiler/lexer"
+	"testing"
+)
+
+// TestParseBreakStatementInsideWhile checks that `break` parses to a bare
+// *ast.BreakStatement as the last statement in a while loop's body.
+func TestParseBreakStatementInsideWhile(t *testing.T) {
+	source := "while x < 10 {\nbreak\n}\n"
+	errorCollector := errors.New(source, "test.navi")
+	parserInstance := New(lexer.New(source, "test.navi", errorCollector), errorCollector)
+
+	program := parserInstance.ParseProgram()
+
+	if errorCollector.HasErrors() {
+		t.Fatalf("unexpected parse errors: %v", errorCollector)
+	}
+	if len(program.Statements) != 1 {
+		t.Fatalf("expected 1 statement, got %d", len(program.Statements))
+	}
+
+	whileStatement, ok := program.Statements[0].(*ast.WhileStatement)
+	if !ok {
+		t.Fatalf("expected *ast.WhileStatement, got %T", program.Statements[0])
+	}
+	if len(whileStatement.Body.Statements) != 1 {
+		t.Fatalf("expected 1 statement in while body, got %d", len(whileStatement.Body.Statements))
+	}
+	if _, ok := whileStatement.Body.Statements[0].(*ast.BreakStatement); !ok {
+		t.Errorf("expected *ast.BreakStatement, got %T", whileStatement.Body.Statements[0])
+	}
+}
@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"compiler/ast"
+	"compiler/errors"
+	"compiler/lexer"
+	"testing"
+)
+
+// TestParseEventHandlerStatements checks `on <event>(params) { ... }`
+// with zero and multiple typed parameters, analogous to
+// TestParseFunctionStatements' coverage of FunctionStatement.
+func TestParseEventHandlerStatements(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		eventName string
+		params    []struct{ name, typeName string }
+	}{
+		{
+			name:      "handler without parameters",
+			input:     "on tick() { }",
+			eventName: "tick",
+			params:    []struct{ name, typeName string }{},
+		},
+		{
+			name:      "handler with multiple typed parameters",
+			input:     "on collide(a: int, b: int) { }",
+			eventName: "collide",
+			params: []struct{ name, typeName string }{
+				{name: "a", typeName: "int"},
+				{name: "b", typeName: "int"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errorCollector := errors.New(test.input, "test.navi")
+			parserInstance := New(lexer.New(test.input, "test.navi", errorCollector), errorCollector)
+
+			program := parserInstance.ParseProgram()
+
+			if errorCollector.HasErrors() {
+				t.Fatalf("unexpected parse errors: %v", errorCollector)
+			}
+			if len(program.Statements) != 1 {
+				t.Fatalf("expected 1 statement, got %d", len(program.Statements))
+			}
+
+			handler, ok := program.Statements[0].(*ast.EventHandlerStatement)
+			if !ok {
+				t.Fatalf("expected *ast.EventHandlerStatement, got %T", program.Statements[0])
+			}
+
+			if handler.EventName.Value != test.eventName {
+				t.Errorf("expected event name %q, got %q", test.eventName, handler.EventName.Value)
+			}
+			if len(handler.Parameters) != len(test.params) {
+				t.Fatalf("expected %d parameters, got %d", len(test.params), len(handler.Parameters))
+			}
+			for i, expectedParam := range test.params {
+				param := handler.Parameters[i]
+				if param.Name.Value != expectedParam.name {
+					t.Errorf("parameter %d: expected name %q, got %q", i, expectedParam.name, param.Name.Value)
+				}
+				if param.Type.Value != expectedParam.typeName {
+					t.Errorf("parameter %d: expected type %q, got %q", i, expectedParam.typeName, param.Type.Value)
+				}
+			}
+		})
+	}
+}
+
+// TestParseEventHandlerRejectedInsideFunctionBody checks that `on`
+// nested inside a func body is rejected.
+func TestParseEventHandlerRejectedInsideFunctionBody(t *testing.T) {
+	input := "func main() {\non tick() { }\n}\n"
+
+	errorCollector := errors.New(input, "test.navi")
+	parserInstance := New(lexer.New(input, "test.navi", errorCollector), errorCollector)
+
+	parserInstance.ParseProgram()
+
+	if !errorCollector.HasErrors() {
+		t.Fatal("expected a parse error for 'on' nested inside a function body, got none")
+	}
+}
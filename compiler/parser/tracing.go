@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mode configures optional parser behavior as independent bits, so more
+// can be added later without changing New's signature.
+type Mode uint
+
+const (
+	ModeNone Mode = 0
+
+	// ModeTrace makes every traced parseXxx method (see trace/untrace
+	// below) print an indented enter/exit line naming the production and
+	// the current token. Ported from the Monkey book's
+	// parser_tracing.go; invaluable when a failing precedence test gives
+	// you nothing but a bad AST string to go on.
+	ModeTrace Mode = 1 << 0
+)
+
+// traceGuard is what trace returns and untrace consumes: the defer
+// untrace(trace(parser, "parseXxx")) pattern needs trace's return value
+// to carry both which parser's depth counter to unwind and which
+// production name the matching "exit" line should print.
+type traceGuard struct {
+	parser     *Parser
+	production string
+}
+
+// trace prints an indented "entering production" line when parser.mode
+// has ModeTrace set, showing the current token and call depth, and
+// returns a guard for the matching defer untrace(...) call to close out.
+// It's a no-op (returns nil) when tracing is off, so production builds
+// pay nothing beyond the mode check.
+func trace(parser *Parser, production string) *traceGuard {
+	if parser.mode&ModeTrace == 0 {
+		return nil
+	}
+
+	parser.traceDepth++
+	fmt.Printf("%sBEGIN %s (token=%q)\n", traceIndent(parser.traceDepth), production, parser.currentToken.Value)
+
+	return &traceGuard{parser: parser, production: production}
+}
+
+// untrace prints the "exiting production" line matching guard's trace
+// call and unwinds the depth counter. It's a no-op when guard is nil
+// (tracing was off when trace was called).
+func untrace(guard *traceGuard) {
+	if guard == nil {
+		return
+	}
+
+	fmt.Printf("%sEND %s\n", traceIndent(guard.parser.traceDepth), guard.production)
+	guard.parser.traceDepth--
+}
+
+func traceIndent(depth int) string {
+	return strings.Repeat("  ", depth-1)
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"compiler/errors"
+	"compiler/lexer"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestTracing checks the shape of ModeTrace's output: an indented
+// "BEGIN production (token=...)" line for each traced parseXxx call,
+// matched by a "END production" line as it returns, exercising the
+// precedence-bearing productions (see the parseExpression/
+// parseBinaryExpression trace calls in parser.go) that a bad precedence
+// test otherwise gives no visibility into beyond the final AST string.
+func TestTracing(t *testing.T) {
+	source := "let x = 1 + 2\n"
+	errorCollector := errors.New(source, "test.navi")
+	parserInstance := NewWithMode(lexer.New(source, "test.navi", errorCollector), errorCollector, ModeTrace)
+
+	output := captureStdout(t, func() {
+		parserInstance.ParseProgram()
+	})
+
+	if !strings.Contains(output, "BEGIN parseLetStatement") {
+		t.Errorf("trace output missing BEGIN parseLetStatement:\n%s", output)
+	}
+	if !strings.Contains(output, "BEGIN parseExpression(precedence=") {
+		t.Errorf("trace output missing parseExpression's precedence detail:\n%s", output)
+	}
+	if !strings.Contains(output, "END parseLetStatement") {
+		t.Errorf("trace output missing END parseLetStatement:\n%s", output)
+	}
+}
+
+// captureStdout redirects os.Stdout for the duration of fn, since trace
+// (see tracing.go) prints straight to it rather than taking a io.Writer.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	original := os.Stdout
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	os.Stdout = writer
+
+	fn()
+
+	writer.Close()
+	os.Stdout = original
+
+	captured, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read captured output: %v", err)
+	}
+	return string(captured)
+}
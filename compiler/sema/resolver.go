@@ -0,0 +1,348 @@
+package sema
+
+import (
+	"compiler/ast"
+	"compiler/errors"
+	"compiler/types"
+)
+
+// Resolution is Resolver's output: a side-table from every resolved
+// *ast.Identifier (a reference, a declaring name, or a plain call's
+// callee) to the Symbol it resolved to. It's attached to nir.Lowerer (see
+// Lowerer.SetResolution) the same way modules.Importer attaches already-
+// lowered imports via Lowerer.SetImports, so the lowerer can stop
+// fabricating variable and return types.
+type Resolution struct {
+	symbols map[ast.Node]*Symbol
+}
+
+// SymbolOf returns the symbol node resolved to, if Resolver visited it.
+func (resolution *Resolution) SymbolOf(node ast.Node) (*Symbol, bool) {
+	symbol, ok := resolution.symbols[node]
+	return symbol, ok
+}
+
+// Resolver walks a parsed *ast.Program and builds a nested Scope tree
+// recording every LetStatement, FunctionParameter, and FunctionStatement
+// it sees, in source order. Because a name is only defined in its scope
+// once the resolver reaches the declaring statement, a reference that
+// comes earlier in the same scope simply fails to resolve there and
+// reports as use-before-declare rather than finding a later declaration.
+type Resolver struct {
+	errorCollector *errors.ErrorCollector
+	resolution     *Resolution
+	scope          *Scope
+	nextID         int
+}
+
+func NewResolver(errorCollector *errors.ErrorCollector) *Resolver {
+	return &Resolver{
+		errorCollector: errorCollector,
+		resolution:     &Resolution{symbols: make(map[ast.Node]*Symbol)},
+	}
+}
+
+// Resolve runs name resolution over program, returning the Resolution to
+// pass to Lowerer.SetResolution. It never fails outright: problems are
+// reported through errorCollector, and resolution keeps going so the
+// caller still gets a best-effort Resolution for the rest of the program.
+func (resolver *Resolver) Resolve(program *ast.Program) *Resolution {
+	resolver.scope = NewScope(nil)
+
+	// Functions are registered ahead of any body being resolved, so a
+	// call to a function declared later in the file (or one calling
+	// itself recursively) still resolves.
+	for _, statement := range program.Statements {
+		if function, ok := statement.(*ast.FunctionStatement); ok {
+			resolver.defineFunction(function)
+		}
+	}
+
+	for _, statement := range program.Statements {
+		switch stmt := statement.(type) {
+		case *ast.FunctionStatement:
+			resolver.resolveFunctionBody(stmt)
+		case *ast.ClassStatement:
+			for _, method := range stmt.Methods {
+				resolver.resolveFunctionBody(method)
+			}
+		case *ast.EventHandlerStatement:
+			resolver.resolveEventHandler(stmt)
+		default:
+			resolver.resolveStatement(stmt)
+		}
+	}
+
+	return resolver.resolution
+}
+
+func (resolver *Resolver) newID() int {
+	id := resolver.nextID
+	resolver.nextID++
+	return id
+}
+
+// defineFunction binds name's top-level function signature into the
+// program scope as a *types.FunctionType, so a call site resolved before
+// the body below is lowered can already tell what it returns.
+func (resolver *Resolver) defineFunction(function *ast.FunctionStatement) {
+	parameterTypes := make([]types.Type, len(function.Parameters))
+	for i, param := range function.Parameters {
+		parameterTypes[i] = resolver.resolveTypeAnnotation(&param.Type)
+	}
+
+	returnType := types.Type(types.Nil)
+	if function.ReturnType != nil {
+		returnType = resolver.resolveTypeAnnotation(function.ReturnType)
+	}
+
+	symbol := &Symbol{
+		ID:   resolver.newID(),
+		Name: function.Name.Value,
+		Type: &types.FunctionType{ParameterTypes: parameterTypes, ReturnType: returnType},
+	}
+
+	if !resolver.scope.Define(symbol) {
+		resolver.errorCollector.Add(errors.SyntaxError,
+			function.Token.Line, function.Token.Column, len(function.Name.Value),
+			"function %s redeclared", function.Name.Value,
+		)
+		return
+	}
+
+	resolver.resolution.symbols[function.Name] = symbol
+}
+
+// resolveFunctionBody resolves astFunc's parameters and body in a fresh
+// scope nested under the program scope, so locals never leak between
+// functions.
+func (resolver *Resolver) resolveFunctionBody(astFunc *ast.FunctionStatement) {
+	outer := resolver.scope
+	resolver.scope = NewScope(outer)
+	defer func() { resolver.scope = outer }()
+
+	for _, param := range astFunc.Parameters {
+		symbol := &Symbol{
+			ID:      resolver.newID(),
+			Name:    param.Name.Value,
+			Type:    resolver.resolveTypeAnnotation(&param.Type),
+			Mutable: true,
+		}
+
+		if !resolver.scope.Define(symbol) {
+			resolver.errorCollector.Add(errors.SyntaxError,
+				astFunc.Token.Line, astFunc.Token.Column, len(param.Name.Value),
+				"parameter %s redeclared", param.Name.Value,
+			)
+			continue
+		}
+
+		resolver.resolution.symbols[param.Name] = symbol
+	}
+
+	resolver.resolveBlock(astFunc.Body)
+}
+
+// resolveEventHandler resolves handler's parameters and body in a fresh
+// scope nested under the program scope, exactly like resolveFunctionBody.
+// It has no defineFunction counterpart: a handler is invoked by the host
+// runtime by event name, not called from Naviary source, so there's no
+// call site that needs its signature registered in scope.
+func (resolver *Resolver) resolveEventHandler(handler *ast.EventHandlerStatement) {
+	outer := resolver.scope
+	resolver.scope = NewScope(outer)
+	defer func() { resolver.scope = outer }()
+
+	for _, param := range handler.Parameters {
+		symbol := &Symbol{
+			ID:      resolver.newID(),
+			Name:    param.Name.Value,
+			Type:    resolver.resolveTypeAnnotation(&param.Type),
+			Mutable: true,
+		}
+
+		if !resolver.scope.Define(symbol) {
+			resolver.errorCollector.Add(errors.SyntaxError,
+				handler.Token.Line, handler.Token.Column, len(param.Name.Value),
+				"parameter %s redeclared", param.Name.Value,
+			)
+			continue
+		}
+
+		resolver.resolution.symbols[param.Name] = symbol
+	}
+
+	resolver.resolveBlock(handler.Body)
+}
+
+// resolveBlock resolves a nested block in its own child scope, so a
+// `let` inside an `if`/`while` body doesn't escape it.
+func (resolver *Resolver) resolveBlock(block *ast.BlockStatement) {
+	outer := resolver.scope
+	resolver.scope = NewScope(outer)
+	defer func() { resolver.scope = outer }()
+
+	for _, statement := range block.Statements {
+		resolver.resolveStatement(statement)
+	}
+}
+
+func (resolver *Resolver) resolveStatement(statement ast.Statement) {
+	switch stmt := statement.(type) {
+	case *ast.LetStatement:
+		resolver.resolveLetStatement(stmt)
+	case *ast.AssignmentStatement:
+		resolver.resolveAssignmentStatement(stmt)
+	case *ast.ReturnStatement:
+		if stmt.ReturnValue != nil {
+			resolver.resolveExpression(stmt.ReturnValue)
+		}
+		for _, value := range stmt.ReturnValues {
+			resolver.resolveExpression(value)
+		}
+	case *ast.ExpressionStatement:
+		resolver.resolveExpression(stmt.Expression)
+	case *ast.IfStatement:
+		resolver.resolveExpression(stmt.Condition)
+		resolver.resolveBlock(stmt.Consequence)
+		if stmt.Alternative != nil {
+			resolver.resolveBlock(stmt.Alternative)
+		}
+	case *ast.WhileStatement:
+		resolver.resolveExpression(stmt.Condition)
+		resolver.resolveBlock(stmt.Body)
+	}
+}
+
+// resolveLetStatement resolves the initializer first (so `let x = x`
+// reports x as use-before-declare, not a self-reference), then defines
+// the bound name(s) in the current scope.
+func (resolver *Resolver) resolveLetStatement(letStmt *ast.LetStatement) {
+	if letStmt.Value != nil {
+		resolver.resolveExpression(letStmt.Value)
+	}
+
+	names := letStmt.Names
+	if len(names) == 0 {
+		names = []*ast.Identifier{letStmt.Name}
+	}
+
+	for _, name := range names {
+		declaredType := types.Type(types.Int)
+		if letStmt.TypeAnnotation != nil {
+			declaredType = resolver.resolveTypeAnnotation(letStmt.TypeAnnotation)
+		}
+
+		symbol := &Symbol{
+			ID:      resolver.newID(),
+			Name:    name.Value,
+			Type:    declaredType,
+			Mutable: letStmt.Mutable,
+		}
+
+		if !resolver.scope.Define(symbol) {
+			resolver.errorCollector.Add(errors.SyntaxError,
+				letStmt.Token.Line, letStmt.Token.Column, len(name.Value),
+				"%s redeclared in this scope", name.Value,
+			)
+			continue
+		}
+
+		resolver.resolution.symbols[name] = symbol
+	}
+}
+
+// resolveAssignmentStatement reports an assignment to a name that either
+// doesn't exist or was declared without `mut`.
+func (resolver *Resolver) resolveAssignmentStatement(assignment *ast.AssignmentStatement) {
+	resolver.resolveExpression(assignment.Value)
+
+	symbol, ok := resolver.scope.Lookup(assignment.Name.Value)
+	if !ok {
+		resolver.errorCollector.Add(errors.SyntaxError,
+			assignment.Token.Line, assignment.Token.Column, len(assignment.Name.Value),
+			"undefined: %s", assignment.Name.Value,
+		)
+		return
+	}
+
+	if !symbol.Mutable {
+		resolver.errorCollector.Add(errors.SyntaxError,
+			assignment.Token.Line, assignment.Token.Column, len(assignment.Name.Value),
+			"cannot assign to %s: declared without mut", assignment.Name.Value,
+		)
+	}
+
+	resolver.resolution.symbols[assignment.Name] = symbol
+}
+
+// resolveExpression descends into expr's subexpressions, resolving every
+// *ast.Identifier it finds against the current scope and every callee it
+// can recognize as a plain function call.
+func (resolver *Resolver) resolveExpression(expr ast.Expression) {
+	switch expression := expr.(type) {
+	case *ast.Identifier:
+		resolver.resolveIdentifier(expression)
+	case *ast.BinaryExpression:
+		resolver.resolveExpression(expression.Left)
+		resolver.resolveExpression(expression.Right)
+	case *ast.UnaryExpression:
+		resolver.resolveExpression(expression.Operand)
+	case *ast.CallExpression:
+		resolver.resolveCallExpression(expression)
+	case *ast.MemberExpression:
+		resolver.resolveExpression(expression.Object)
+	}
+}
+
+func (resolver *Resolver) resolveIdentifier(identifier *ast.Identifier) {
+	symbol, ok := resolver.scope.Lookup(identifier.Value)
+	if !ok {
+		resolver.errorCollector.Add(errors.SyntaxError,
+			identifier.Token.Line, identifier.Token.Column, len(identifier.Value),
+			"undefined: %s", identifier.Value,
+		)
+		return
+	}
+
+	resolver.resolution.symbols[identifier] = symbol
+}
+
+// resolveCallExpression resolves every argument, plus the callee itself
+// when it's a plain `name(...)` call rather than a method call (see
+// nir.Lowerer.lowerCallExpression, which dispatches those separately).
+func (resolver *Resolver) resolveCallExpression(call *ast.CallExpression) {
+	if callee, ok := call.Function.(*ast.Identifier); ok {
+		resolver.resolveIdentifier(callee)
+	} else {
+		resolver.resolveExpression(call.Function)
+	}
+
+	for _, argument := range call.Arguments {
+		resolver.resolveExpression(argument)
+	}
+}
+
+// resolveTypeAnnotation converts a type annotation to a types.Type the
+// same way nir.Lowerer.getType does for builtin names. Any other name (a
+// class or struct type) becomes types.Int here; the lowerer looks such a
+// name up in its own class/struct tables when it types a let binding or
+// a parameter, but the Symbol's Type stays types.Int.
+func (resolver *Resolver) resolveTypeAnnotation(typeAnnotation *ast.TypeAnnotation) types.Type {
+	switch typeAnnotation.Value {
+	case "int":
+		return types.Int
+	case "float":
+		return types.Float
+	case "string":
+		return types.String
+	case "bool":
+		return types.Bool
+	case "nil":
+		return types.Nil
+	case "i8", "i16", "i32", "i64", "u8", "u16", "u32", "u64", "f32", "f64":
+		return types.GetPrimitiveType(typeAnnotation.Value)
+	default:
+		return types.Int
+	}
+}
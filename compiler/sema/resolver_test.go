@@ -0,0 +1,104 @@
+package sema
+
+import (
+	"compiler/ast"
+	"compiler/errors"
+	"compiler/token"
+	"testing"
+)
+
+func ident(name string) *ast.Identifier {
+	return &ast.Identifier{Token: token.Token{Type: token.IDENTIFIER, Value: name}, Value: name}
+}
+
+func TestResolverAttachesParameterType(t *testing.T) {
+	paramName := ident("x")
+	function := &ast.FunctionStatement{
+		Token: token.Token{Type: token.FUNC, Value: "func"},
+		Name:  ident("identity"),
+		Parameters: []*ast.FunctionParameter{
+			{Name: paramName, Type: ast.TypeAnnotation{Value: "float"}},
+		},
+		ReturnType: &ast.TypeAnnotation{Value: "float"},
+		Body: &ast.BlockStatement{
+			Statements: []ast.Statement{
+				&ast.ReturnStatement{ReturnValue: ident("x")},
+			},
+		},
+	}
+	program := &ast.Program{Statements: []ast.Statement{function}}
+
+	resolution := NewResolver(errors.New("", "test.navi")).Resolve(program)
+
+	symbol, ok := resolution.SymbolOf(paramName)
+	if !ok {
+		t.Fatal("expected parameter x to resolve to a symbol")
+	}
+	if symbol.Type.String() != "float" {
+		t.Errorf("parameter type = %s, want float", symbol.Type.String())
+	}
+
+	use := function.Body.Statements[0].(*ast.ReturnStatement).ReturnValue.(*ast.Identifier)
+	useSymbol, ok := resolution.SymbolOf(use)
+	if !ok {
+		t.Fatal("expected return-expression x to resolve to a symbol")
+	}
+	if useSymbol.ID != symbol.ID {
+		t.Errorf("return-expression x resolved to a different symbol than the parameter")
+	}
+}
+
+func TestResolverReportsUseBeforeDeclare(t *testing.T) {
+	function := &ast.FunctionStatement{
+		Token: token.Token{Type: token.FUNC, Value: "func"},
+		Name:  ident("f"),
+		Body: &ast.BlockStatement{
+			Statements: []ast.Statement{
+				&ast.LetStatement{
+					Token: token.Token{Type: token.LET, Value: "let"},
+					Name:  ident("x"),
+					Value: ident("x"),
+				},
+			},
+		},
+	}
+	program := &ast.Program{Statements: []ast.Statement{function}}
+
+	errorCollector := errors.New("", "test.navi")
+	NewResolver(errorCollector).Resolve(program)
+
+	if !errorCollector.HasErrors() {
+		t.Error("expected `let x = x` to report x as undefined")
+	}
+}
+
+func TestResolverReportsAssignToImmutable(t *testing.T) {
+	function := &ast.FunctionStatement{
+		Token: token.Token{Type: token.FUNC, Value: "func"},
+		Name:  ident("f"),
+		Body: &ast.BlockStatement{
+			Statements: []ast.Statement{
+				&ast.LetStatement{
+					Token:   token.Token{Type: token.LET, Value: "let"},
+					Name:    ident("x"),
+					Value:   &ast.IntegerLiteral{Value: "1"},
+					Mutable: false,
+				},
+				&ast.AssignmentStatement{
+					Token:    token.Token{Type: token.ASSIGN, Value: "="},
+					Name:     ident("x"),
+					Operator: "=",
+					Value:    &ast.IntegerLiteral{Value: "2"},
+				},
+			},
+		},
+	}
+	program := &ast.Program{Statements: []ast.Statement{function}}
+
+	errorCollector := errors.New("", "test.navi")
+	NewResolver(errorCollector).Resolve(program)
+
+	if !errorCollector.HasErrors() {
+		t.Error("expected assignment to an immutable let binding to report an error")
+	}
+}
@@ -0,0 +1,45 @@
+package sema
+
+// Scope is one nesting level of name resolution: the program's top level,
+// or a function/block body nested inside it. It mirrors
+// types.SymbolTable's parent-chain shape, but Define additionally reports
+// whether name was already bound in *this* scope, so Resolver can turn a
+// redeclaration into an error instead of silently shadowing it.
+type Scope struct {
+	parent  *Scope
+	symbols map[string]*Symbol
+}
+
+// NewScope creates a scope nested inside parent. parent is nil for the
+// program's top-level scope.
+func NewScope(parent *Scope) *Scope {
+	return &Scope{
+		parent:  parent,
+		symbols: make(map[string]*Symbol),
+	}
+}
+
+// Define binds name to symbol in this scope, returning false without
+// changing anything if name is already bound locally (a redeclaration).
+func (scope *Scope) Define(symbol *Symbol) bool {
+	if _, exists := scope.symbols[symbol.Name]; exists {
+		return false
+	}
+
+	scope.symbols[symbol.Name] = symbol
+	return true
+}
+
+// Lookup finds name in this scope or, failing that, in the nearest
+// enclosing scope that binds it.
+func (scope *Scope) Lookup(name string) (*Symbol, bool) {
+	if symbol, ok := scope.symbols[name]; ok {
+		return symbol, true
+	}
+
+	if scope.parent != nil {
+		return scope.parent.Lookup(name)
+	}
+
+	return nil, false
+}
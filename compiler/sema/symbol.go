@@ -0,0 +1,14 @@
+package sema
+
+import "compiler/types"
+
+// Symbol records everything the resolver learned about a single binding:
+// a `let`-bound variable, a function parameter, or a top-level function.
+// ID is unique across the whole Resolution, so two symbols that happen to
+// share a Name in unrelated scopes are never confused with one another.
+type Symbol struct {
+	ID      int
+	Name    string
+	Type    types.Type
+	Mutable bool
+}
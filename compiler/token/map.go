@@ -7,7 +7,10 @@ var tokenMap = [...]string{
 
 	// Literals
 	INT_LITERAL:    "INT_LITERAL",
+	FLOAT_LITERAL:  "FLOAT_LITERAL",
 	STRING_LITERAL: "STRING_LITERAL",
+	CHAR_LITERAL:   "CHAR_LITERAL",
+	DOC_COMMENT:    "DOC_COMMENT",
 
 	// Identifier
 	IDENTIFIER: "IDENTIFIER",
@@ -18,7 +21,23 @@ var tokenMap = [...]string{
 	FUNC:   "func",
 	RETURN: "return",
 	CLASS:  "class",
+	STRUCT: "struct",
 	THIS:   "this",
+	KERNEL: "kernel",
+	IF:     "if",
+	ELSE:   "else",
+	WHILE:  "while",
+	BREAK:  "break",
+	EXTERN: "extern",
+	IMPORT: "import",
+	EXPORT: "export",
+	ON:     "on",
+
+	// Memory space qualifiers
+	GLOBAL:  "global",
+	LOCAL:   "local",
+	SHARED:  "shared",
+	PRIVATE: "private",
 
 	// Type keywords
 	INT:    "int",
@@ -26,14 +45,43 @@ var tokenMap = [...]string{
 	STRING: "string",
 	BOOL:   "bool",
 
+	I8:  "i8",
+	I16: "i16",
+	I32: "i32",
+	I64: "i64",
+	U8:  "u8",
+	U16: "u16",
+	U32: "u32",
+	U64: "u64",
+	F32: "f32",
+	F64: "f64",
+
 	// Operators
 	PLUS:         "+",
 	MINUS:        "-",
 	ASTERISK:     "*",
 	SLASH:        "/",
+	PERCENT:      "%",
 	ASSIGN:       "=",
 	COLON_ASSIGN: ":=",
 	DOT:          ".",
+	ELLIPSIS:     "...",
+	LOGICAL_AND:  "&&",
+	LOGICAL_OR:   "||",
+	LOGICAL_NOT:  "!",
+
+	EQUAL:              "==",
+	NOT_EQUAL:          "!=",
+	LESS_THAN:          "<",
+	LESS_THAN_EQUAL:    "<=",
+	GREATER_THAN:       ">",
+	GREATER_THAN_EQUAL: ">=",
+
+	PLUS_ASSIGN:     "+=",
+	MINUS_ASSIGN:    "-=",
+	ASTERISK_ASSIGN: "*=",
+	SLASH_ASSIGN:    "/=",
+	PERCENT_ASSIGN:  "%=",
 
 	// Delimiters
 	LEFT_PAREN:  "(",
@@ -0,0 +1,15 @@
+package token
+
+// Position identifies a single point in the original source, independent
+// of any particular Token. It's used where only a line/column pair needs
+// to be carried around (e.g. bytecode.Bytecode's SourceMap), rather than
+// a full Token with its literal value and type.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// PositionOf builds a Position from any token's line/column.
+func PositionOf(t Token) Position {
+	return Position{Line: t.Line, Column: t.Column}
+}
@@ -24,12 +24,38 @@ var keywords = map[string]TokenType{
 	"return": RETURN,
 	"mut":    MUT,
 	"class":  CLASS,
+	"struct": STRUCT,
 	"this":   THIS,
+	"kernel": KERNEL,
+	"if":     IF,
+	"else":   ELSE,
+	"while":  WHILE,
+	"break":  BREAK,
+	"extern": EXTERN,
+	"import": IMPORT,
+	"export": EXPORT,
+	"on":     ON,
+
+	"global":  GLOBAL,
+	"local":   LOCAL,
+	"shared":  SHARED,
+	"private": PRIVATE,
 
 	"int":    INT,
 	"float":  FLOAT,
 	"string": STRING,
 	"bool":   BOOL,
+
+	"i8":  I8,
+	"i16": I16,
+	"i32": I32,
+	"i64": I64,
+	"u8":  U8,
+	"u16": U16,
+	"u32": U32,
+	"u64": U64,
+	"f32": F32,
+	"f64": F64,
 }
 
 // LookupIdentifier checks if an identifier is a keyword
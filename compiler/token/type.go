@@ -11,8 +11,11 @@ const (
 	ILLEGAL
 
 	// Literals
-	INT_LITERAL    // 123
+	INT_LITERAL    // 123, 0xFF, 0b1010, 0o755, 1_000
+	FLOAT_LITERAL  // 3.14, 1e10, 2.5e-3
 	STRING_LITERAL // "hello"
+	CHAR_LITERAL   // 'h'
+	DOC_COMMENT    // /// doc or /** doc */, value is the trimmed body
 	IDENTIFIER     // variable names
 
 	// Keywords
@@ -21,7 +24,23 @@ const (
 	RETURN // return
 	FUNC   // func
 	CLASS  // class
+	STRUCT // struct
 	THIS   // this
+	KERNEL // kernel
+	IF     // if
+	ELSE   // else
+	WHILE  // while
+	BREAK  // break
+	EXTERN // extern
+	IMPORT // import
+	EXPORT // export
+	ON     // on
+
+	// Memory space qualifiers (GPU kernel parameters)
+	GLOBAL  // global
+	LOCAL   // local
+	SHARED  // shared
+	PRIVATE // private
 
 	// Type keywords
 	INT    // int
@@ -29,15 +48,47 @@ const (
 	STRING // string
 	BOOL   // bool
 
+	// Sized integer and float type keywords
+	I8  // i8
+	I16 // i16
+	I32 // i32
+	I64 // i64
+	U8  // u8
+	U16 // u16
+	U32 // u32
+	U64 // u64
+	F32 // f32
+	F64 // f64
+
 	operatorBegin
 	// Operators
 	PLUS         // +
 	MINUS        // -
 	ASTERISK     // *
 	SLASH        // /
+	PERCENT      // %
 	ASSIGN       // =
 	COLON_ASSIGN // :=
 	DOT          // .
+	ELLIPSIS     // ...
+	LOGICAL_AND  // &&
+	LOGICAL_OR   // ||
+	LOGICAL_NOT  // !
+
+	// Comparison operators
+	EQUAL              // ==
+	NOT_EQUAL          // !=
+	LESS_THAN          // <
+	LESS_THAN_EQUAL    // <=
+	GREATER_THAN       // >
+	GREATER_THAN_EQUAL // >=
+
+	// Compound assignment operators
+	PLUS_ASSIGN     // +=
+	MINUS_ASSIGN    // -=
+	ASTERISK_ASSIGN // *=
+	SLASH_ASSIGN    // /=
+	PERCENT_ASSIGN  // %=
 
 	operatorEnd
 
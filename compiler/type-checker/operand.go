@@ -0,0 +1,73 @@
+package typechecker
+
+import (
+	"compiler/constant"
+	"compiler/types"
+)
+
+// OperandMode classifies what an Operand denotes, mirroring the subset of
+// go/types' operand modes this checker actually needs.
+type OperandMode int
+
+const (
+	// Invalid marks an operand whose expression already produced a
+	// diagnostic; callers should bail out without reporting another one.
+	Invalid OperandMode = iota
+	// Constant is a compile-time value - either a literal or the result
+	// of folding an operation over constant operands.
+	Constant
+	// Variable is a name bound by let/a function parameter; Mutable
+	// reports whether it was declared with `mut`.
+	Variable
+	// Value is anything else with a type but no compile-time value or
+	// assignable storage, e.g. the result of a non-constant call.
+	Value
+	// Builtin is a function/builtin name referenced but not called.
+	Builtin
+	// TypeExpr is a type used where an expression was expected.
+	TypeExpr
+)
+
+func (mode OperandMode) String() string {
+	switch mode {
+	case Constant:
+		return "constant"
+	case Variable:
+		return "variable"
+	case Value:
+		return "value"
+	case Builtin:
+		return "builtin"
+	case TypeExpr:
+		return "type"
+	default:
+		return "invalid"
+	}
+}
+
+// Operand is the result of checking a single expression: its mode, type,
+// constant value (if it has one), and source position, so later stages -
+// constant folding across a whole expression, deciding whether a name can
+// be assigned to - don't need to re-walk the AST to recover information
+// checkExpression already had.
+type Operand struct {
+	Mode   OperandMode
+	Type   types.Type
+	Value  constant.Value
+	Line   int
+	Column int
+
+	// Mutable is only meaningful when Mode == Variable: whether the
+	// underlying binding was declared with `mut` and can be assigned to.
+	Mutable bool
+}
+
+// IsInvalid reports whether o's expression already failed to check, so
+// the caller should propagate the failure rather than report its own.
+func (o Operand) IsInvalid() bool {
+	return o.Mode == Invalid
+}
+
+// invalidOperand is returned by every checkXxx function in place of a
+// type once an error has been reported for that expression.
+var invalidOperand = Operand{Mode: Invalid}
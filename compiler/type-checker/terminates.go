@@ -0,0 +1,35 @@
+package typechecker
+
+import "compiler/ast"
+
+// terminates reports whether control flow is guaranteed to leave stmt
+// through a return rather than fall off its end, the analysis go/types'
+// return.go performs to catch a missing return before it becomes
+// undefined fall-through behavior at codegen. Only the statement kinds
+// that affect this are handled; anything else (an assignment, a bare
+// expression, a break, a while loop whatever its condition) answers
+// false, so the analysis is conservative.
+//
+// switch and for aren't in the grammar yet; once they land, a
+// switch terminates iff every case (including a required default)
+// terminates, and an infinite `for {}` with no break terminates.
+func terminates(stmt ast.Statement) bool {
+	switch statement := stmt.(type) {
+	case *ast.ReturnStatement:
+		return true
+	case *ast.BlockStatement:
+		for _, inner := range statement.Statements {
+			if terminates(inner) {
+				return true
+			}
+		}
+		return false
+	case *ast.IfStatement:
+		if statement.Alternative == nil {
+			return false
+		}
+		return terminates(statement.Consequence) && terminates(statement.Alternative)
+	default:
+		return false
+	}
+}
@@ -1,15 +1,33 @@
 package typechecker
 
 import (
-	"naviary/compiler/ast"
-	"naviary/compiler/errors"
-	"naviary/compiler/types"
+	"fmt"
+
+	"compiler/ast"
+	"compiler/constant"
+	"compiler/errors"
+	"compiler/token"
+	"compiler/types"
 )
 
 type TypeChecker struct {
 	errorCollector  *errors.ErrorCollector
 	symbolTable     *types.SymbolTable
 	currentFunction *types.FunctionType
+
+	// kernelSharedParams holds the names of the current kernel function's
+	// `shared`-qualified parameters, or nil outside a kernel function.
+	// seenBarrier is reset to false whenever a new kernel is entered and
+	// flips to true once a call to the `barrier` builtin is seen, since
+	// writes to shared memory are only well-defined once every thread in
+	// the work-group has reached that synchronization point.
+	kernelSharedParams map[string]bool
+	seenBarrier        bool
+
+	// typeRegistry maps a declared struct's name to its StructType, so a
+	// later type annotation (`x: Point`) or field access can resolve it
+	// the same way types.GetPrimitiveType resolves a builtin name.
+	typeRegistry map[string]*types.StructType
 }
 
 func New(source string, filename string) *TypeChecker {
@@ -27,10 +45,19 @@ func New(source string, filename string) *TypeChecker {
 func (checker *TypeChecker) registerBuiltins() {
 	// print function: print(value: any) -> void
 	printType := &types.FunctionType{
-		Parameters: []types.Type{types.Int}, // For now, only int
-		ReturnType: nil,                     // void
+		ParameterTypes: []types.Type{types.Int}, // For now, only int
+		ReturnType:     nil,                     // void
 	}
 	checker.symbolTable.Define("print", printType, false)
+
+	// barrier(): synchronizes every thread in a kernel's work-group/block.
+	// Only meaningful inside a `kernel` function, but declared globally
+	// like every other builtin.
+	barrierType := &types.FunctionType{
+		ParameterTypes: []types.Type{},
+		ReturnType:     nil,
+	}
+	checker.symbolTable.Define("barrier", barrierType, false)
 }
 
 func (checker *TypeChecker) Errors() *errors.ErrorCollector {
@@ -45,24 +72,58 @@ func (checker *TypeChecker) Check(program *ast.Program) {
 	}
 }
 
-func (checker *TypeChecker) checkExpression(expression ast.Expression) types.Type {
+// checkExpression type checks expression and returns an Operand describing
+// it: its type, mode (constant/variable/value/builtin), constant value if
+// it has one, and source position. It mirrors go/types' operand.go -
+// carrying the value and mode alongside the type lets a folded constant
+// flow from one expression straight into the next (checkBinaryOperation,
+// checkLetStatement, checkReturnStatement) without a side channel.
+func (checker *TypeChecker) checkExpression(expression ast.Expression) Operand {
 	switch expr := expression.(type) {
 	case *ast.IntegerLiteral:
-		return types.Int
+		return Operand{
+			Mode:   Constant,
+			Type:   types.UntypedInt,
+			Value:  constant.MakeFromLiteral(expr.Value, constant.Int),
+			Line:   expr.Token.Line,
+			Column: expr.Token.Column,
+		}
 	case *ast.FloatLiteral:
-		return types.Float
+		return Operand{
+			Mode:   Constant,
+			Type:   types.UntypedFloat,
+			Value:  constant.MakeFromLiteral(expr.Value, constant.Float),
+			Line:   expr.Token.Line,
+			Column: expr.Token.Column,
+		}
 	case *ast.StringLiteral:
-		return types.String
+		return Operand{
+			Mode:   Constant,
+			Type:   types.UntypedString,
+			Value:  constant.MakeString(expr.Value),
+			Line:   expr.Token.Line,
+			Column: expr.Token.Column,
+		}
 	case *ast.BooleanLiteral:
-		return types.Bool
+		return Operand{
+			Mode:   Constant,
+			Type:   types.UntypedBool,
+			Value:  constant.MakeBool(expr.Value),
+			Line:   expr.Token.Line,
+			Column: expr.Token.Column,
+		}
 	case *ast.Identifier:
 		return checker.checkIdentifier(expr)
 	case *ast.BinaryExpression:
 		return checker.checkBinaryOperation(expr)
+	case *ast.UnaryExpression:
+		return checker.checkUnaryOperation(expr)
 	case *ast.CallExpression:
 		return checker.checkCallExpression(expr)
+	case *ast.MemberExpression:
+		return checker.checkFieldAccess(expr)
 	default:
-		return nil
+		return invalidOperand
 	}
 }
 
@@ -79,12 +140,16 @@ func (checker *TypeChecker) checkStatement(statement ast.Statement) {
 		checker.checkExpressionStatement(stmt)
 	case *ast.BlockStatement:
 		checker.checkBlockStatement(stmt)
+	case *ast.AssignmentStatement:
+		checker.checkAssignmentStatement(stmt)
+	case *ast.StructStatement:
+		checker.checkStructStatement(stmt)
 	default:
 		// Unknown statement type
 	}
 }
 
-func (checker *TypeChecker) checkIdentifier(identifier *ast.Identifier) types.Type {
+func (checker *TypeChecker) checkIdentifier(identifier *ast.Identifier) Operand {
 	symbol := checker.symbolTable.Lookup(identifier.Value)
 
 	if symbol == nil {
@@ -96,24 +161,133 @@ func (checker *TypeChecker) checkIdentifier(identifier *ast.Identifier) types.Ty
 			"undefined variable %s",
 			identifier.Value,
 		)
-		return nil
+		return invalidOperand
+	}
+
+	mode := Variable
+	if _, ok := symbol.Type.(*types.FunctionType); ok {
+		mode = Builtin
 	}
 
-	return symbol.Type
+	return Operand{
+		Mode:    mode,
+		Type:    symbol.Type,
+		Mutable: symbol.Mutable,
+		Line:    identifier.Token.Line,
+		Column:  identifier.Token.Column,
+	}
 }
 
-func (checker *TypeChecker) checkBinaryOperation(binary *ast.BinaryExpression) types.Type {
-	leftType := checker.checkExpression(binary.Left)
-	if leftType == nil {
-		return nil // error already reported
+// resolveType resolves a type name written in source to its types.Type:
+// a builtin primitive, or a struct previously registered into
+// typeRegistry by checkStructStatement. It returns nil for an unknown
+// name, the same sentinel types.GetPrimitiveType uses on its own.
+func (checker *TypeChecker) resolveType(name string) types.Type {
+	if primitive := types.GetPrimitiveType(name); primitive != nil {
+		return primitive
 	}
+	return checker.typeRegistry[name]
+}
 
-	rightType := checker.checkExpression(binary.Right)
-	if rightType == nil {
-		return nil // error already reported
+// checkStructStatement resolves a struct declaration's field types and
+// registers the resulting types.StructType so later `x: Name` annotations
+// and field accesses can resolve it.
+func (checker *TypeChecker) checkStructStatement(structStmt *ast.StructStatement) {
+	fields := make([]types.StructField, len(structStmt.Fields))
+	for i, field := range structStmt.Fields {
+		fieldType := checker.resolveType(field.Type.Value)
+		if fieldType == nil {
+			checker.errorCollector.Add(
+				errors.TypeError,
+				field.Type.Token.Line,
+				field.Type.Token.Column,
+				len(field.Type.Value),
+				"unknown type: %s",
+				field.Type.Value,
+			)
+			return
+		}
+		fields[i] = types.StructField{Name: field.Name.Value, Type: fieldType}
 	}
 
-	if !leftType.Equals(rightType) {
+	if _, exists := checker.typeRegistry[structStmt.Name.Value]; exists {
+		checker.errorCollector.Add(
+			errors.TypeError,
+			structStmt.Name.Token.Line,
+			structStmt.Name.Token.Column,
+			len(structStmt.Name.Value),
+			"type '%s' already defined",
+			structStmt.Name.Value,
+		)
+		return
+	}
+
+	if checker.typeRegistry == nil {
+		checker.typeRegistry = make(map[string]*types.StructType)
+	}
+	checker.typeRegistry[structStmt.Name.Value] = &types.StructType{
+		Name:   structStmt.Name.Value,
+		Fields: fields,
+	}
+}
+
+// checkFieldAccess resolves `object.field` (ast.MemberExpression) against
+// the receiver's type: field lookup via types.StructType.LookupField,
+// which also reports an ambiguous embedded field.
+func (checker *TypeChecker) checkFieldAccess(member *ast.MemberExpression) Operand {
+	object := checker.checkExpression(member.Object)
+	if object.IsInvalid() {
+		return invalidOperand
+	}
+
+	structType, ok := object.Type.(*types.StructType)
+	if !ok {
+		checker.errorCollector.Add(
+			errors.TypeError,
+			member.Token.Line,
+			member.Token.Column,
+			len(member.Property.Value),
+			"%s is not a struct",
+			object.Type.String(),
+		)
+		return invalidOperand
+	}
+
+	field, ok := structType.LookupField(member.Property.Value)
+	if !ok {
+		checker.errorCollector.Add(
+			errors.TypeError,
+			member.Property.Token.Line,
+			member.Property.Token.Column,
+			len(member.Property.Value),
+			"undefined field '%s' on %s",
+			member.Property.Value,
+			structType.Name,
+		)
+		return invalidOperand
+	}
+
+	return Operand{
+		Mode:   Value,
+		Type:   field.Type,
+		Line:   member.Token.Line,
+		Column: member.Token.Column,
+	}
+}
+
+func (checker *TypeChecker) checkBinaryOperation(binary *ast.BinaryExpression) Operand {
+	left := checker.checkExpression(binary.Left)
+	if left.IsInvalid() {
+		return invalidOperand
+	}
+
+	right := checker.checkExpression(binary.Right)
+	if right.IsInvalid() {
+		return invalidOperand
+	}
+
+	operandType, ok := unifyOperandTypes(left.Type, right.Type)
+	if !ok {
 		checker.errorCollector.Add(
 			errors.TypeError,
 			binary.Token.Line,
@@ -121,24 +295,48 @@ func (checker *TypeChecker) checkBinaryOperation(binary *ast.BinaryExpression) t
 			len(binary.Operator),
 			"type mismatch: cannot apply '%s' to %s and %s",
 			binary.Operator,
-			leftType.String(),
-			rightType.String(),
+			left.Type.String(),
+			right.Type.String(),
 		)
-		return nil
+		return invalidOperand
+	}
+
+	result := Operand{
+		Mode:   Value,
+		Type:   operandType,
+		Line:   binary.Token.Line,
+		Column: binary.Token.Column,
+	}
+
+	// Both operands constant: fold at compile time and carry the result
+	// in the Operand, so an enclosing expression can keep folding. The
+	// AST itself is left unchanged; nir/opt.ConstantFolding folds constant
+	// BinaryInstructions separately, after lowering.
+	if types.IsUntyped(operandType) && left.Value != nil && right.Value != nil {
+		if opToken, ok := binaryOperatorToken(binary.Operator); ok {
+			if folded := constant.BinaryOp(left.Value, opToken, right.Value); folded.Kind() != constant.Unknown {
+				result.Mode = Constant
+				result.Value = folded
+			}
+		}
 	}
 
 	switch binary.Operator {
 	case "+", "-", "*", "/":
-		if leftType == types.Int || leftType == types.Float {
-			return leftType
+		if operandType == types.Int || operandType == types.Float ||
+			operandType == types.UntypedInt || operandType == types.UntypedFloat {
+			return result
 		}
 
 		// string concat
-		if binary.Operator == "+" && leftType == types.String {
-			return types.String
+		if binary.Operator == "+" && (operandType == types.String || operandType == types.UntypedString) {
+			return result
 		}
-	case "==", "1=", ">", "<", ">=", "<=":
-		return types.Bool
+	case "==", "!=", ">", "<", ">=", "<=":
+		result.Type = types.Bool
+		result.Mode = Value
+		result.Value = nil
+		return result
 	}
 
 	checker.errorCollector.Add(
@@ -147,17 +345,231 @@ func (checker *TypeChecker) checkBinaryOperation(binary *ast.BinaryExpression) t
 		binary.Token.Column,
 		len(binary.Operator),
 		"invalid operation: %s %s %s",
-		leftType.String(),
+		left.Type.String(),
 		binary.Operator,
-		rightType.String(),
+		right.Type.String(),
 	)
-	return nil
+	return invalidOperand
+}
+
+// checkUnaryOperation checks `-x` and `!x`: `-` requires a numeric operand
+// and `!` requires bool, folding a constant operand at compile time via
+// constant.UnaryOp the same way checkBinaryOperation folds a binary one.
+func (checker *TypeChecker) checkUnaryOperation(unary *ast.UnaryExpression) Operand {
+	operand := checker.checkExpression(unary.Operand)
+	if operand.IsInvalid() {
+		return invalidOperand
+	}
+
+	switch unary.Operator {
+	case "-":
+		if operand.Type != types.Int && operand.Type != types.Float &&
+			operand.Type != types.UntypedInt && operand.Type != types.UntypedFloat {
+			checker.errorCollector.Add(
+				errors.TypeError,
+				unary.Token.Line,
+				unary.Token.Column,
+				len(unary.Operator),
+				"invalid operation: %s%s",
+				unary.Operator,
+				operand.Type.String(),
+			)
+			return invalidOperand
+		}
+	case "!":
+		if operand.Type != types.Bool && operand.Type != types.UntypedBool {
+			checker.errorCollector.Add(
+				errors.TypeError,
+				unary.Token.Line,
+				unary.Token.Column,
+				len(unary.Operator),
+				"invalid operation: %s%s",
+				unary.Operator,
+				operand.Type.String(),
+			)
+			return invalidOperand
+		}
+	}
+
+	result := Operand{
+		Mode:   Value,
+		Type:   operand.Type,
+		Line:   unary.Token.Line,
+		Column: unary.Token.Column,
+	}
+
+	if types.IsUntyped(operand.Type) && operand.Value != nil {
+		if folded := constant.UnaryOp(unary.Token.Type, operand.Value); folded.Kind() != constant.Unknown {
+			result.Mode = Constant
+			result.Value = folded
+		}
+	}
+
+	return result
+}
+
+// unifyOperandTypes reconciles a binary operation's operand types,
+// implicitly converting whichever side is untyped so `1 + 2.0` and
+// `1 + someFloat` both type-check. It returns the unified type (typed if
+// either operand was) and false if left and right can't be unified at all.
+func unifyOperandTypes(left, right types.Type) (types.Type, bool) {
+	if left.Equals(right) {
+		return left, true
+	}
+
+	leftUntyped := types.IsUntyped(left)
+	rightUntyped := types.IsUntyped(right)
+
+	switch {
+	case leftUntyped && !rightUntyped:
+		if canConvertUntyped(left, right) {
+			return right, true
+		}
+	case rightUntyped && !leftUntyped:
+		if canConvertUntyped(right, left) {
+			return left, true
+		}
+	case leftUntyped && rightUntyped:
+		if widened, ok := widenUntyped(left, right); ok {
+			return widened, true
+		}
+	}
+
+	return nil, false
+}
+
+// canConvertUntyped reports whether untypedType can implicitly convert to
+// target, ignoring whether the specific constant value is representable -
+// checkLetStatement/checkCallExpression do that finer-grained range check
+// once they know the actual destination type and can emit an overflow
+// diagnostic.
+func canConvertUntyped(untypedType, target types.Type) bool {
+	switch untypedType {
+	case types.UntypedInt:
+		return types.IntWidth(target) > 0 || types.IsFloat(target)
+	case types.UntypedFloat:
+		return types.IsFloat(target)
+	case types.UntypedString:
+		return target == types.String
+	case types.UntypedBool:
+		return target == types.Bool
+	default:
+		return false
+	}
+}
+
+// widenUntyped unifies two untyped operand kinds, promoting int to float
+// when mixed (`1 + 2.0` is untyped float) and otherwise requiring an exact
+// kind match.
+func widenUntyped(left, right types.Type) (types.Type, bool) {
+	if left == right {
+		return left, true
+	}
+	if (left == types.UntypedInt && right == types.UntypedFloat) ||
+		(left == types.UntypedFloat && right == types.UntypedInt) {
+		return types.UntypedFloat, true
+	}
+	return nil, false
+}
+
+// binaryOperatorToken maps the AST's string operator spelling to the
+// token.TokenType compiler/constant.BinaryOp expects.
+func binaryOperatorToken(operator string) (token.TokenType, bool) {
+	switch operator {
+	case "+":
+		return token.PLUS, true
+	case "-":
+		return token.MINUS, true
+	case "*":
+		return token.ASTERISK, true
+	case "/":
+		return token.SLASH, true
+	default:
+		return 0, false
+	}
+}
+
+// convertUntypedTo implicitly converts an untyped constant's type to
+// target, reporting a "subject (untyped int constant N) overflows T"
+// diagnostic (and returning false) if value doesn't fit target's range.
+// subject names whatever is being converted - a variable name, "return
+// value", "argument 2" - for that diagnostic. If valueType isn't one of
+// the Untyped* kinds, it just reports whether valueType already equals
+// target - the caller is responsible for producing its own diagnostic in
+// that case, since the wording differs between a let statement and a
+// call argument.
+func (checker *TypeChecker) convertUntypedTo(valueType types.Type, value constant.Value, target types.Type, subject string, line, column, length int) (types.Type, bool) {
+	if !types.IsUntyped(valueType) {
+		return valueType, valueType.Equals(target)
+	}
+
+	switch valueType {
+	case types.UntypedBool:
+		return target, target == types.Bool
+	case types.UntypedString:
+		return target, target == types.String
+	case types.UntypedFloat:
+		return target, types.IsFloat(target)
+	case types.UntypedInt:
+		if types.IsFloat(target) {
+			return target, true
+		}
+
+		width := types.IntWidth(target)
+		if width == 0 {
+			return target, false
+		}
+
+		intValue, ok := constant.Int64Val(value)
+		if !ok || !fitsInWidth(intValue, width, types.IsUnsigned(target)) {
+			checker.errorCollector.Add(
+				errors.TypeError,
+				line, column, length,
+				"%s (untyped int constant %s) overflows %s",
+				subject,
+				value.String(),
+				target.String(),
+			)
+			return target, false
+		}
+		return target, true
+	default:
+		return target, false
+	}
+}
+
+// fitsInWidth reports whether value is representable in a two's-
+// complement integer of the given bit width (signed unless unsigned is
+// set), the same range check an explicit int-to-iN cast would need.
+func fitsInWidth(value int64, width int, unsigned bool) bool {
+	if width <= 0 {
+		return true
+	}
+
+	if unsigned {
+		if value < 0 {
+			return false
+		}
+		if width >= 64 {
+			return true
+		}
+		return uint64(value) < uint64(1)<<uint(width)
+	}
+
+	if width >= 64 {
+		return true
+	}
+	limit := int64(1) << uint(width-1)
+	return value >= -limit && value < limit
 }
 
 // checkCallExpression checks function calls
-func (checker *TypeChecker) checkCallExpression(call *ast.CallExpression) types.Type {
+func (checker *TypeChecker) checkCallExpression(call *ast.CallExpression) Operand {
 	// Get function identifier
 	funcIdent, ok := call.Function.(*ast.Identifier)
+	if ok && funcIdent.Value == "barrier" {
+		checker.seenBarrier = true
+	}
 	if !ok {
 		checker.errorCollector.Add(
 			errors.TypeError,
@@ -166,7 +578,7 @@ func (checker *TypeChecker) checkCallExpression(call *ast.CallExpression) types.
 			1,
 			"invalid function call: not an identifier",
 		)
-		return nil
+		return invalidOperand
 	}
 
 	// Look up function in symbol table
@@ -180,7 +592,7 @@ func (checker *TypeChecker) checkCallExpression(call *ast.CallExpression) types.
 			"undefined function: %s",
 			funcIdent.Value,
 		)
-		return nil
+		return invalidOperand
 	}
 
 	// Check if it's actually a function type
@@ -194,55 +606,108 @@ func (checker *TypeChecker) checkCallExpression(call *ast.CallExpression) types.
 			"'%s' is not a function",
 			funcIdent.Value,
 		)
-		return nil
+		return invalidOperand
 	}
 
 	// Check argument count
-	if len(call.Arguments) != len(funcType.Parameters) {
+	if len(call.Arguments) != len(funcType.ParameterTypes) {
 		checker.errorCollector.Add(
 			errors.TypeError,
 			call.Token.Line,
 			call.Token.Column,
 			1,
 			"wrong number of arguments: expected %d, got %d",
-			len(funcType.Parameters),
+			len(funcType.ParameterTypes),
 			len(call.Arguments),
 		)
-		return nil
+		return invalidOperand
 	}
 
 	// Check each argument type
 	for i, arg := range call.Arguments {
-		argType := checker.checkExpression(arg)
-		if argType == nil {
+		argOperand := checker.checkExpression(arg)
+		if argOperand.IsInvalid() {
 			continue // Error already reported
 		}
 
-		expectedType := funcType.Parameters[i]
-		if !argType.Equals(expectedType) {
-			checker.errorCollector.Add(
-				errors.TypeError,
-				call.Token.Line,
-				call.Token.Column,
-				1,
-				"argument %d: expected %s, got %s",
-				i+1,
-				expectedType.String(),
-				argType.String(),
-			)
+		expectedType := funcType.ParameterTypes[i]
+		subject := fmt.Sprintf("argument %d", i+1)
+		if _, ok := checker.convertUntypedTo(argOperand.Type, argOperand.Value, expectedType, subject, call.Token.Line, call.Token.Column, 1); !ok {
+			if !types.IsUntyped(argOperand.Type) {
+				checker.errorCollector.Add(
+					errors.TypeError,
+					call.Token.Line,
+					call.Token.Column,
+					1,
+					"argument %d: expected %s, got %s",
+					i+1,
+					expectedType.String(),
+					argOperand.Type.String(),
+				)
+			}
+			// else: convertUntypedTo already reported the overflow
 		}
 	}
 
-	return funcType.ReturnType // Can be nil for void functions
+	// Can be void (ReturnType nil)
+	return Operand{
+		Mode:   Value,
+		Type:   funcType.ReturnType,
+		Line:   call.Token.Line,
+		Column: call.Token.Column,
+	}
 }
 
 // checkLetStatement checks variable declarations
 func (checker *TypeChecker) checkLetStatement(letStmt *ast.LetStatement) {
+	if len(letStmt.Names) > 1 {
+		checker.checkDestructuringLetStatement(letStmt)
+		return
+	}
+
 	// First, check the value expression to get its type
-	valueType := checker.checkExpression(letStmt.Value)
-	if valueType == nil {
+	value := checker.checkExpression(letStmt.Value)
+	if value.IsInvalid() {
 		return // Error already reported
 	}
+	valueType := value.Type
+
+	if letStmt.TypeAnnotation != nil {
+		declaredType := checker.resolveType(letStmt.TypeAnnotation.Value)
+		if declaredType == nil {
+			checker.errorCollector.Add(
+				errors.TypeError,
+				letStmt.TypeAnnotation.Token.Line,
+				letStmt.TypeAnnotation.Token.Column,
+				len(letStmt.TypeAnnotation.Value),
+				"unknown type: %s",
+				letStmt.TypeAnnotation.Value,
+			)
+			return
+		}
+
+		convertedType, ok := checker.convertUntypedTo(valueType, value.Value, declaredType,
+			letStmt.Name.Value, letStmt.Name.Token.Line, letStmt.Name.Token.Column, len(letStmt.Name.Value))
+		if !ok {
+			if types.IsUntyped(valueType) {
+				return // overflow (or mismatch) already reported
+			}
+			checker.errorCollector.Add(
+				errors.TypeError,
+				letStmt.Name.Token.Line,
+				letStmt.Name.Token.Column,
+				len(letStmt.Name.Value),
+				"cannot assign %s to variable '%s' of type %s",
+				valueType.String(),
+				letStmt.Name.Value,
+				declaredType.String(),
+			)
+			return
+		}
+		valueType = convertedType
+	} else {
+		valueType = types.DefaultType(valueType)
+	}
 
 	// Now define the variable with its type
 	if !checker.symbolTable.Define(letStmt.Name.Value, valueType, letStmt.Mutable) {
@@ -258,12 +723,48 @@ func (checker *TypeChecker) checkLetStatement(letStmt *ast.LetStatement) {
 	}
 }
 
+// checkDestructuringLetStatement checks `let a, b = foo()`, binding each
+// name to the matching element of foo()'s declared TupleType return.
+func (checker *TypeChecker) checkDestructuringLetStatement(letStmt *ast.LetStatement) {
+	value := checker.checkExpression(letStmt.Value)
+	if value.IsInvalid() {
+		return // Error already reported
+	}
+
+	tupleType, ok := value.Type.(*types.TupleType)
+	if !ok || len(tupleType.Elements) != len(letStmt.Names) {
+		checker.errorCollector.Add(
+			errors.TypeError,
+			letStmt.Token.Line,
+			letStmt.Token.Column,
+			len(letStmt.Token.Value),
+			"cannot destructure %d name(s) from %s",
+			len(letStmt.Names),
+			value.Type.String(),
+		)
+		return
+	}
+
+	for i, name := range letStmt.Names {
+		if !checker.symbolTable.Define(name.Value, tupleType.Elements[i], letStmt.Mutable) {
+			checker.errorCollector.Add(
+				errors.TypeError,
+				name.Token.Line,
+				name.Token.Column,
+				len(name.Value),
+				"variable '%s' already defined in this scope",
+				name.Value,
+			)
+		}
+	}
+}
+
 // checkFunctionStatement checks function declarations
 func (checker *TypeChecker) checkFunctionStatement(funcStmt *ast.FunctionStatement) {
 	// Create function type from AST
 	paramTypes := make([]types.Type, len(funcStmt.Parameters))
 	for i, param := range funcStmt.Parameters {
-		paramType := types.GetPrimitiveType(param.Type.Value)
+		paramType := checker.resolveType(param.Type.Value)
 		if paramType == nil {
 			checker.errorCollector.Add(
 				errors.TypeError,
@@ -280,8 +781,26 @@ func (checker *TypeChecker) checkFunctionStatement(funcStmt *ast.FunctionStateme
 
 	// Get return type (nil if no return type specified)
 	var returnType types.Type
-	if funcStmt.ReturnType != nil {
-		returnType = types.GetPrimitiveType(funcStmt.ReturnType.Value)
+	if len(funcStmt.ReturnTypes) > 1 {
+		elements := make([]types.Type, len(funcStmt.ReturnTypes))
+		for i, returnTypeAnnotation := range funcStmt.ReturnTypes {
+			elementType := checker.resolveType(returnTypeAnnotation.Value)
+			if elementType == nil {
+				checker.errorCollector.Add(
+					errors.TypeError,
+					returnTypeAnnotation.Token.Line,
+					returnTypeAnnotation.Token.Column,
+					len(returnTypeAnnotation.Value),
+					"unknown return type: %s",
+					returnTypeAnnotation.Value,
+				)
+				return
+			}
+			elements[i] = elementType
+		}
+		returnType = &types.TupleType{Elements: elements}
+	} else if funcStmt.ReturnType != nil {
+		returnType = checker.resolveType(funcStmt.ReturnType.Value)
 		if returnType == nil {
 			checker.errorCollector.Add(
 				errors.TypeError,
@@ -297,8 +816,8 @@ func (checker *TypeChecker) checkFunctionStatement(funcStmt *ast.FunctionStateme
 
 	// Create function type
 	funcType := &types.FunctionType{
-		Parameters: paramTypes,
-		ReturnType: returnType,
+		ParameterTypes: paramTypes,
+		ReturnType:     returnType,
 	}
 
 	// Define function in symbol table
@@ -335,12 +854,43 @@ func (checker *TypeChecker) checkFunctionStatement(funcStmt *ast.FunctionStateme
 	previousFunction := checker.currentFunction
 	checker.currentFunction = funcType
 
+	// Track this kernel's `shared` parameters so assignments to them can
+	// be rejected outside a barrier() region; non-kernel functions never
+	// have shared parameters, so this stays nil for them.
+	previousSharedParams := checker.kernelSharedParams
+	previousSeenBarrier := checker.seenBarrier
+	checker.kernelSharedParams = nil
+	checker.seenBarrier = false
+
+	if funcStmt.Kernel {
+		for _, param := range funcStmt.Parameters {
+			if param.MemorySpace == "shared" {
+				if checker.kernelSharedParams == nil {
+					checker.kernelSharedParams = make(map[string]bool)
+				}
+				checker.kernelSharedParams[param.Name.Value] = true
+			}
+		}
+	}
+
 	// Check function body
 	checker.checkBlockStatement(funcStmt.Body)
 
-	// Restore previous function and scope
+	if funcType.ReturnType != nil && !terminates(funcStmt.Body) {
+		checker.errorCollector.Add(
+			errors.TypeError,
+			funcStmt.Name.Token.Line,
+			funcStmt.Name.Token.Column,
+			len(funcStmt.Name.Value),
+			"missing return at end of function",
+		)
+	}
+
+	// Restore previous function, scope and kernel tracking state
 	checker.currentFunction = previousFunction
 	checker.symbolTable = checker.symbolTable.Parent()
+	checker.kernelSharedParams = previousSharedParams
+	checker.seenBarrier = previousSeenBarrier
 }
 
 // checkBlockStatement checks a block of statements
@@ -365,12 +915,18 @@ func (checker *TypeChecker) checkReturnStatement(returnStmt *ast.ReturnStatement
 			errors.TypeError,
 			returnStmt.Token.Line,
 			returnStmt.Token.Column,
-			len(returnStmt.Token.Literal),
+			len(returnStmt.Token.Value),
 			"return statement outside function",
 		)
 		return
 	}
 
+	// Check multi-value return: `return a, b`
+	if len(returnStmt.ReturnValues) > 1 {
+		checker.checkMultiReturnStatement(returnStmt)
+		return
+	}
+
 	// Check return value
 	if returnStmt.ReturnValue == nil {
 		// No return value
@@ -379,7 +935,7 @@ func (checker *TypeChecker) checkReturnStatement(returnStmt *ast.ReturnStatement
 				errors.TypeError,
 				returnStmt.Token.Line,
 				returnStmt.Token.Column,
-				len(returnStmt.Token.Literal),
+				len(returnStmt.Token.Value),
 				"missing return value: expected %s",
 				checker.currentFunction.ReturnType.String(),
 			)
@@ -388,8 +944,8 @@ func (checker *TypeChecker) checkReturnStatement(returnStmt *ast.ReturnStatement
 	}
 
 	// Has return value - check its type
-	returnType := checker.checkExpression(returnStmt.ReturnValue)
-	if returnType == nil {
+	value := checker.checkExpression(returnStmt.ReturnValue)
+	if value.IsInvalid() {
 		return // Error already reported
 	}
 
@@ -399,19 +955,80 @@ func (checker *TypeChecker) checkReturnStatement(returnStmt *ast.ReturnStatement
 			errors.TypeError,
 			returnStmt.Token.Line,
 			returnStmt.Token.Column,
-			len(returnStmt.Token.Literal),
+			len(returnStmt.Token.Value),
 			"unexpected return value in void function",
 		)
-	} else if !returnType.Equals(checker.currentFunction.ReturnType) {
+		return
+	}
+
+	if _, ok := checker.convertUntypedTo(value.Type, value.Value, checker.currentFunction.ReturnType,
+		"return value", returnStmt.Token.Line, returnStmt.Token.Column, len(returnStmt.Token.Value)); !ok {
+		if types.IsUntyped(value.Type) {
+			return // overflow already reported
+		}
 		checker.errorCollector.Add(
 			errors.TypeError,
 			returnStmt.Token.Line,
 			returnStmt.Token.Column,
-			len(returnStmt.Token.Literal),
+			len(returnStmt.Token.Value),
 			"return type mismatch: expected %s, got %s",
 			checker.currentFunction.ReturnType.String(),
-			returnType.String(),
+			value.Type.String(),
+		)
+	}
+}
+
+// checkMultiReturnStatement checks a `return a, b, ...` statement against
+// the enclosing function's declared TupleType return type.
+func (checker *TypeChecker) checkMultiReturnStatement(returnStmt *ast.ReturnStatement) {
+	expectedTuple, ok := checker.currentFunction.ReturnType.(*types.TupleType)
+	if !ok {
+		checker.errorCollector.Add(
+			errors.TypeError,
+			returnStmt.Token.Line,
+			returnStmt.Token.Column,
+			len(returnStmt.Token.Value),
+			"function does not declare multiple return values",
+		)
+		return
+	}
+
+	if len(returnStmt.ReturnValues) != len(expectedTuple.Elements) {
+		checker.errorCollector.Add(
+			errors.TypeError,
+			returnStmt.Token.Line,
+			returnStmt.Token.Column,
+			len(returnStmt.Token.Value),
+			"return value count mismatch: expected %d, got %d",
+			len(expectedTuple.Elements),
+			len(returnStmt.ReturnValues),
 		)
+		return
+	}
+
+	for i, valueExpr := range returnStmt.ReturnValues {
+		value := checker.checkExpression(valueExpr)
+		if value.IsInvalid() {
+			continue // Error already reported
+		}
+
+		subject := fmt.Sprintf("return value at position %d", i)
+		if _, ok := checker.convertUntypedTo(value.Type, value.Value, expectedTuple.Elements[i],
+			subject, returnStmt.Token.Line, returnStmt.Token.Column, len(returnStmt.Token.Value)); !ok {
+			if types.IsUntyped(value.Type) {
+				continue // overflow already reported
+			}
+			checker.errorCollector.Add(
+				errors.TypeError,
+				returnStmt.Token.Line,
+				returnStmt.Token.Column,
+				len(returnStmt.Token.Value),
+				"return type mismatch at position %d: expected %s, got %s",
+				i,
+				expectedTuple.Elements[i].String(),
+				value.Type.String(),
+			)
+		}
 	}
 }
 
@@ -420,3 +1037,35 @@ func (checker *TypeChecker) checkExpressionStatement(exprStmt *ast.ExpressionSta
 	// Just check the expression, ignore the return type
 	checker.checkExpression(exprStmt.Expression)
 }
+
+// checkAssignmentStatement checks 'name = expression' / 'name += expression'.
+// Besides type checking the right-hand side, it rejects assigning to a
+// binding that wasn't declared `mut` and, for GPU kernels, writing to a
+// `shared`-qualified parameter before the kernel has called `barrier()` -
+// a race, since other threads in the work-group may not have finished
+// writing to (or may not yet have reached) that shared memory.
+func (checker *TypeChecker) checkAssignmentStatement(assignment *ast.AssignmentStatement) {
+	checker.checkExpression(assignment.Value)
+
+	if symbol := checker.symbolTable.Lookup(assignment.Name.Value); symbol != nil && !symbol.Mutable {
+		checker.errorCollector.Add(
+			errors.TypeError,
+			assignment.Token.Line,
+			assignment.Token.Column,
+			len(assignment.Name.Value),
+			"cannot assign to %s (declared without mut)",
+			assignment.Name.Value,
+		)
+	}
+
+	if checker.kernelSharedParams != nil && checker.kernelSharedParams[assignment.Name.Value] && !checker.seenBarrier {
+		checker.errorCollector.Add(
+			errors.TypeError,
+			assignment.Token.Line,
+			assignment.Token.Column,
+			len(assignment.Name.Value),
+			"write to shared parameter '%s' outside a barrier() region",
+			assignment.Name.Value,
+		)
+	}
+}
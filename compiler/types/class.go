@@ -0,0 +1,44 @@
+package types
+
+// ClassType represents a user-defined class: an ordered, named set of
+// fields forming a stable memory layout, declared with `class Name { ... }`
+// (see ast.ClassStatement). Methods aren't part of the type itself - they
+// lower to ordinary top-level NIR functions name-mangled as
+// ClassName_methodName (see nir.Function.Receiver), so there's nothing
+// about them to represent here.
+type ClassType struct {
+	Name   string
+	Fields []ClassField
+}
+
+// ClassField is a single field's name and declared type within a
+// ClassType's layout, in declaration order.
+type ClassField struct {
+	Name string
+	Type Type
+}
+
+func (class *ClassType) String() string {
+	return class.Name
+}
+
+func (class *ClassType) Equals(other Type) bool {
+	otherClass, ok := other.(*ClassType)
+	if !ok {
+		return false
+	}
+	return class.Name == otherClass.Name
+}
+
+// FieldIndex returns the position of name within class's field layout,
+// matching the order the LLVM backend lays out the corresponding struct
+// in, so a GEPInstruction can address the field by index. Returns
+// (0, false) if class has no such field.
+func (class *ClassType) FieldIndex(name string) (int, bool) {
+	for i, field := range class.Fields {
+		if field.Name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
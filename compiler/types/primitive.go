@@ -20,6 +20,20 @@ var (
 	Float  = &PrimitiveType{Name: "float"}
 	String = &PrimitiveType{Name: "string"}
 	Bool   = &PrimitiveType{Name: "bool"}
+
+	// Sized integer and float primitives. Int/Float above remain the
+	// default, unsized spellings (int is I64-width, float is F64-width)
+	// for source code that doesn't care about exact width.
+	I8  = &PrimitiveType{Name: "i8"}
+	I16 = &PrimitiveType{Name: "i16"}
+	I32 = &PrimitiveType{Name: "i32"}
+	I64 = &PrimitiveType{Name: "i64"}
+	U8  = &PrimitiveType{Name: "u8"}
+	U16 = &PrimitiveType{Name: "u16"}
+	U32 = &PrimitiveType{Name: "u32"}
+	U64 = &PrimitiveType{Name: "u64"}
+	F32 = &PrimitiveType{Name: "f32"}
+	F64 = &PrimitiveType{Name: "f64"}
 )
 
 func GetPrimitiveType(name string) Type {
@@ -32,7 +46,113 @@ func GetPrimitiveType(name string) Type {
 		return String
 	case "bool":
 		return Bool
+	case "i8":
+		return I8
+	case "i16":
+		return I16
+	case "i32":
+		return I32
+	case "i64":
+		return I64
+	case "u8":
+		return U8
+	case "u16":
+		return U16
+	case "u32":
+		return U32
+	case "u64":
+		return U64
+	case "f32":
+		return F32
+	case "f64":
+		return F64
 	default:
 		return nil
 	}
 }
+
+// IsUnsigned reports whether typ is one of the unsigned sized integer
+// primitives (u8/u16/u32/u64), the signal InstructionConverter.ConvertBinary
+// uses to pick CreateUDiv/CreateURem over CreateSDiv/CreateSRem.
+func IsUnsigned(typ Type) bool {
+	primitive, ok := typ.(*PrimitiveType)
+	if !ok {
+		return false
+	}
+	switch primitive.Name {
+	case "u8", "u16", "u32", "u64":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFloat reports whether typ is a floating-point primitive (float, f32,
+// or f64), the signal InstructionConverter.ConvertBinary uses to pick
+// CreateFAdd/FSub/FMul/FDiv over the integer instructions.
+func IsFloat(typ Type) bool {
+	primitive, ok := typ.(*PrimitiveType)
+	if !ok {
+		return false
+	}
+	switch primitive.Name {
+	case "float", "f32", "f64":
+		return true
+	default:
+		return false
+	}
+}
+
+// IntWidth returns typ's bit width as an integer primitive (int and i64
+// are both 64 bits). It returns 0 for a non-integer type, so callers
+// comparing widths to decide sign-/zero-extend vs truncate should check
+// IsFloat first.
+func IntWidth(typ Type) int {
+	primitive, ok := typ.(*PrimitiveType)
+	if !ok {
+		return 0
+	}
+	switch primitive.Name {
+	case "i8", "u8":
+		return 8
+	case "i16", "u16":
+		return 16
+	case "i32", "u32":
+		return 32
+	case "int", "i64", "u64":
+		return 64
+	default:
+		return 0
+	}
+}
+
+// IsAggregate reports whether typ is a multi-field composite (ClassType,
+// StructType or TupleType) rather than a scalar, the signal nir/ssa's promotion pass
+// uses to bail out of promoting a variable to SSA: a class instance can
+// have its address taken via GEPInstruction, so dropping its Alloc would
+// leave that GEP with no memory to address.
+func IsAggregate(typ Type) bool {
+	switch typ.(type) {
+	case *ClassType, *StructType, *TupleType:
+		return true
+	default:
+		return false
+	}
+}
+
+// FloatWidth returns typ's bit width as a floating-point primitive
+// (float and f64 are both 64 bits). It returns 0 for a non-float type.
+func FloatWidth(typ Type) int {
+	primitive, ok := typ.(*PrimitiveType)
+	if !ok {
+		return 0
+	}
+	switch primitive.Name {
+	case "f32":
+		return 32
+	case "float", "f64":
+		return 64
+	default:
+		return 0
+	}
+}
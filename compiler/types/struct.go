@@ -0,0 +1,104 @@
+package types
+
+// StructType represents a user-defined plain data aggregate: an ordered,
+// named set of fields forming a stable memory layout, declared with
+// `struct Name { ... }` (see ast.StructStatement). Unlike ClassType, a
+// struct has no methods - it exists purely to give a GEPInstruction
+// something to address.
+//
+// EmbeddedFields holds the struct types embedded by composition, so
+// LookupField can promote their fields the way an embedded anonymous
+// field does; it's nil until struct embedding syntax lands in the
+// grammar.
+type StructType struct {
+	Name           string
+	Fields         []StructField
+	EmbeddedFields []*StructType
+}
+
+// StructField is a single field's name and declared type within a
+// StructType's layout, in declaration order.
+type StructField struct {
+	Name string
+	Type Type
+}
+
+func (structType *StructType) String() string {
+	return structType.Name
+}
+
+// Equals compares structs by name and field layout (name + type, in
+// order), rather than name alone: two structs declared with the same
+// name but a different layout (e.g. across separately-lowered modules)
+// are not the same type.
+func (structType *StructType) Equals(other Type) bool {
+	otherStruct, ok := other.(*StructType)
+	if !ok {
+		return false
+	}
+
+	if structType.Name != otherStruct.Name {
+		return false
+	}
+
+	if len(structType.Fields) != len(otherStruct.Fields) {
+		return false
+	}
+
+	for i, field := range structType.Fields {
+		otherField := otherStruct.Fields[i]
+		if field.Name != otherField.Name || !field.Type.Equals(otherField.Type) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FieldIndex returns the position of name within structType's field
+// layout, matching the order the LLVM backend lays out the corresponding
+// struct in, so a GEPInstruction can address the field by index.
+// Returns (0, false) if structType has no such field.
+func (structType *StructType) FieldIndex(name string) (int, bool) {
+	for i, field := range structType.Fields {
+		if field.Name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// LookupField resolves name against structType's own fields first, then
+// breadth-first across EmbeddedFields, so a name declared directly on
+// structType shadows the same name promoted from something it embeds,
+// and two embedded types that promote the same name at equal depth are
+// reported ambiguous (ok is false) rather than one being picked
+// arbitrarily.
+func (structType *StructType) LookupField(name string) (StructField, bool) {
+	level := []*StructType{structType}
+
+	for len(level) > 0 {
+		var matches []StructField
+		var next []*StructType
+
+		for _, current := range level {
+			for _, field := range current.Fields {
+				if field.Name == name {
+					matches = append(matches, field)
+				}
+			}
+			next = append(next, current.EmbeddedFields...)
+		}
+
+		switch len(matches) {
+		case 0:
+			level = next
+		case 1:
+			return matches[0], true
+		default:
+			return StructField{}, false
+		}
+	}
+
+	return StructField{}, false
+}
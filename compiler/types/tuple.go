@@ -0,0 +1,42 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TupleType represents the type of a multi-value return: a fixed-size,
+// ordered group of result types produced by a single return statement.
+// It only appears as a FunctionType's ReturnType; Naviary has no tuple
+// literal syntax, so TupleType can't occur anywhere else.
+type TupleType struct {
+	Elements []Type
+}
+
+func (tuple *TupleType) String() string {
+	elements := make([]string, len(tuple.Elements))
+	for i, element := range tuple.Elements {
+		elements[i] = element.String()
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(elements, ", "))
+}
+
+func (tuple *TupleType) Equals(other Type) bool {
+	otherTuple, ok := other.(*TupleType)
+	if !ok {
+		return false
+	}
+
+	if len(tuple.Elements) != len(otherTuple.Elements) {
+		return false
+	}
+
+	for i, element := range tuple.Elements {
+		if !element.Equals(otherTuple.Elements[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,37 @@
+package types
+
+// Untyped* are the provisional types the typechecker assigns to a bare
+// literal (`3`, `3.0`, `"hi"`, `true`) before it's bound to a concrete
+// destination type. They carry their constant.Value around unconverted so
+// `let x: float = 3` or `print(1 + 2)` can implicitly convert the literal
+// to whatever type it ends up needing, rather than erroring with a type
+// mismatch against the default Int/Float/String/Bool.
+var (
+	UntypedInt    = &PrimitiveType{Name: "untyped int"}
+	UntypedFloat  = &PrimitiveType{Name: "untyped float"}
+	UntypedString = &PrimitiveType{Name: "untyped string"}
+	UntypedBool   = &PrimitiveType{Name: "untyped bool"}
+)
+
+// IsUntyped reports whether typ is one of the Untyped* constant kinds.
+func IsUntyped(typ Type) bool {
+	return typ == UntypedInt || typ == UntypedFloat || typ == UntypedString || typ == UntypedBool
+}
+
+// DefaultType returns the concrete type an untyped constant defaults to
+// when it isn't unified against some other type, e.g. a bare `let x = 3`
+// with no destination type and no other operand to widen against.
+func DefaultType(typ Type) Type {
+	switch typ {
+	case UntypedInt:
+		return Int
+	case UntypedFloat:
+		return Float
+	case UntypedString:
+		return String
+	case UntypedBool:
+		return Bool
+	default:
+		return typ
+	}
+}